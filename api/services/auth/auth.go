@@ -13,7 +13,6 @@ import (
 	"github.com/ardanlabs/encore/app/sdk/auth"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/domain/userbus/stores/userdb"
-	"github.com/ardanlabs/encore/business/sdk/delegate"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/foundation/keystore"
 	"github.com/ardanlabs/encore/foundation/logger"
@@ -44,8 +43,9 @@ type Service struct {
 
 // NewService is called to create a new encore Service.
 func NewService(log *logger.Logger, db *sqlx.DB, ath *auth.Auth) (*Service, error) {
-	delegate := delegate.New(log)
-	userBus := userbus.NewBusiness(log, delegate, userdb.NewStore(log, db))
+	// The service only queries users, so there's no outbox to write the
+	// events of changes to.
+	userBus := userbus.NewBusiness(log, nil, userdb.NewStore(log, db))
 
 	s := Service{
 		log:     log,
@@ -0,0 +1,136 @@
+package sales
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"encore.dev/cron"
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+// We need a job that applies scheduled product price changes once their
+// effective date has been reached.
+var _ = cron.NewJob("apply-product-prices", cron.JobConfig{
+	Title:    "Apply scheduled product price changes",
+	Every:    1 * cron.Minute,
+	Endpoint: ApplyProductPrices,
+})
+
+// We need a job that retries the webhook deliveries that failed once their
+// next attempt is due.
+var _ = cron.NewJob("retry-webhook-deliveries", cron.JobConfig{
+	Title:    "Retry failed webhook deliveries",
+	Every:    1 * cron.Minute,
+	Endpoint: RetryWebhookDeliveries,
+})
+
+// We need a job that publishes the events left in the outbox, which are
+// otherwise relayed once the transaction that wrote them commits.
+var _ = cron.NewJob("relay-outbox", cron.JobConfig{
+	Title:    "Publish the events in the outbox",
+	Every:    1 * cron.Minute,
+	Endpoint: RelayOutbox,
+})
+
+// We need a job that removes the events from the outbox once they can't be
+// delivered again.
+var _ = cron.NewJob("purge-outbox", cron.JobConfig{
+	Title:    "Purge sent events from the outbox",
+	Every:    24 * cron.Hour,
+	Endpoint: PurgeOutbox,
+})
+
+// We need a job that drops the oldest events from the event log once it
+// grows beyond its size.
+var _ = cron.NewJob("trim-events", cron.JobConfig{
+	Title:    "Trim the event log",
+	Every:    1 * cron.Hour,
+	Endpoint: TrimEvents,
+})
+
+// outboxRetention is how long sent events, and the record of the consumers
+// that handled them, are kept.
+const outboxRetention = 7 * 24 * time.Hour
+
+// ApplyProductPrices is called by the cron system to apply any scheduled
+// product price changes that are due.
+//
+//encore:api private method=POST path=/v1/jobs/apply-product-prices
+func (s *Service) ApplyProductPrices(ctx context.Context) error {
+	ctx, events := outboxbus.Track(ctx)
+
+	// Every price is committed on its own, so the events of the prices that
+	// were applied are relayed even when a later one fails.
+	n, err := s.productBus.ApplyDuePrices(ctx, sqldb.NewBeginner(s.db), time.Now())
+	s.relayOutbox(ctx, events())
+
+	if err != nil {
+		return fmt.Errorf("applydueprices: applied[%d]: %w", n, err)
+	}
+
+	if n > 0 {
+		s.log.Info(ctx, "apply-product-prices", "applied", n)
+	}
+
+	return nil
+}
+
+// RetryWebhookDeliveries is called by the cron system to retry any webhook
+// deliveries that are due.
+//
+//encore:api private method=POST path=/v1/jobs/retry-webhook-deliveries
+func (s *Service) RetryWebhookDeliveries(ctx context.Context) error {
+	n, err := s.webhookBus.RetryDue(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("retrydue: retried[%d]: %w", n, err)
+	}
+
+	if n > 0 {
+		s.log.Info(ctx, "retry-webhook-deliveries", "retried", n)
+	}
+
+	return nil
+}
+
+// RelayOutbox is called by the cron system to publish the events in the
+// outbox that haven't been sent.
+//
+//encore:api private method=POST path=/v1/jobs/relay-outbox
+func (s *Service) RelayOutbox(ctx context.Context) error {
+	n, err := s.outboxBus.Relay(ctx)
+	if err != nil {
+		return fmt.Errorf("relay: sent[%d]: %w", n, err)
+	}
+
+	if n > 0 {
+		s.log.Info(ctx, "relay-outbox", "sent", n)
+	}
+
+	return nil
+}
+
+// PurgeOutbox is called by the cron system to remove the events sent more
+// than the retention period ago.
+//
+//encore:api private method=POST path=/v1/jobs/purge-outbox
+func (s *Service) PurgeOutbox(ctx context.Context) error {
+	if err := s.outboxBus.Purge(ctx, time.Now().Add(-outboxRetention)); err != nil {
+		return fmt.Errorf("purge: %w", err)
+	}
+
+	return nil
+}
+
+// TrimEvents is called by the cron system to drop the oldest events beyond
+// the size of the event log.
+//
+//encore:api private method=POST path=/v1/jobs/trim-events
+func (s *Service) TrimEvents(ctx context.Context) error {
+	if err := s.eventBus.Trim(ctx); err != nil {
+		return fmt.Errorf("trim: %w", err)
+	}
+
+	return nil
+}
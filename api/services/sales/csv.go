@@ -0,0 +1,58 @@
+package sales
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	eerrs "encore.dev/beta/errs"
+	"github.com/ardanlabs/encore/app/sdk/csvio"
+)
+
+// csvExport streams the csv written by export to the client as a download
+// with the specified file name. An error found before any of the file is
+// written goes back to the client, while one found after can only be logged
+// since the response is already on its way.
+func (s *Service) csvExport(w http.ResponseWriter, r *http.Request, filename string, export func(ctx context.Context, w io.Writer) error) {
+	cw := countWriter{w: w}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if err := export(r.Context(), &cw); err != nil {
+		if cw.n == 0 {
+			w.Header().Del("Content-Disposition")
+			eerrs.HTTPError(w, err)
+			return
+		}
+
+		s.log.Error(r.Context(), "csv export", "file", filename, "msg", err)
+	}
+}
+
+// csvImport sends the report of an import to the client.
+func (s *Service) csvImport(w http.ResponseWriter, rpt csvio.Report, err error) {
+	if err != nil {
+		eerrs.HTTPError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rpt); err != nil {
+		eerrs.HTTPError(w, err)
+	}
+}
+
+// countWriter counts the bytes written through it.
+type countWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += n
+	return n, err
+}
@@ -9,7 +9,9 @@ import (
 	authsrv "github.com/ardanlabs/encore/api/services/auth"
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/google/uuid"
 )
 
 // NOTE: The order matters so be careful when injecting new middleware. Global
@@ -104,13 +106,56 @@ func (s *Service) authorizeHome(req middleware.Request, next middleware.Next) mi
 	return next(req)
 }
 
+//lint:ignore U1000 "called by encore"
+//encore:middleware target=tag:authorize_webhook
+func (s *Service) authorizeWebhook(req middleware.Request, next middleware.Next) middleware.Response {
+	p, req, err := mid.AuthorizeWebhook(s.webhookBus, req)
+	if err != nil {
+		return errs.NewResponse(errs.Unauthenticated, err)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := authsrv.Authorize(ctx, p); err != nil {
+		err = fmt.Errorf("%s", err.Error()[17:]) // Remove "unauthenticated:" from the error string.
+		return errs.NewResponse(errs.Unauthenticated, err)
+	}
+
+	return next(req)
+}
+
 // =============================================================================
 // Specific middleware functions
 
 //lint:ignore U1000 "called by encore"
 //encore:middleware target=tag:transaction
 func (s *Service) beginCommitRollback(req middleware.Request, next middleware.Next) middleware.Response {
-	return mid.BeginCommitRollback(s.log, sqldb.NewBeginner(s.db), req, next)
+	ctx, events := outboxbus.Track(req.Context())
+
+	resp := mid.BeginCommitRollback(s.log, sqldb.NewBeginner(s.db), req.WithContext(ctx), next)
+	if resp.Err != nil {
+		return resp
+	}
+
+	s.relayOutbox(ctx, events())
+
+	return resp
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:middleware target=tag:transaction_client_errors
+func (s *Service) beginCommitRollbackClientErrors(req middleware.Request, next middleware.Next) middleware.Response {
+	ctx, events := outboxbus.Track(req.Context())
+
+	resp := mid.BeginCommitRollbackClientErrors(s.log, sqldb.NewBeginner(s.db), req.WithContext(ctx), next)
+	if resp.Err != nil {
+		return resp
+	}
+
+	s.relayOutbox(ctx, events())
+
+	return resp
 }
 
 //lint:ignore U1000 "called by encore"
@@ -118,3 +163,14 @@ func (s *Service) beginCommitRollback(req middleware.Request, next middleware.Ne
 func (s *Service) metrics(req middleware.Request, next middleware.Next) middleware.Response {
 	return mid.Metrics(s.mtrcs, req, next)
 }
+
+// =============================================================================
+
+// relayOutbox publishes the events written to the outbox by a call now that
+// they are committed. The rest of the outbox, and any events left behind, are
+// sent by the relay job.
+func (s *Service) relayOutbox(ctx context.Context, eventIDs []uuid.UUID) {
+	if _, err := s.outboxBus.RelayEvents(ctx, eventIDs); err != nil {
+		s.log.Error(ctx, "relay outbox", "msg", err)
+	}
+}
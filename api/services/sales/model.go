@@ -1,28 +1,50 @@
 package sales
 
 import (
+	categoryapp "github.com/ardanlabs/encore/app/domain/categoryapp"
+	"github.com/ardanlabs/encore/app/domain/deadletterapp"
+	"github.com/ardanlabs/encore/app/domain/eventapp"
 	homeapp "github.com/ardanlabs/encore/app/domain/homeapp"
 	productapp "github.com/ardanlabs/encore/app/domain/productapp"
+	tagapp "github.com/ardanlabs/encore/app/domain/tagapp"
 	tranapp "github.com/ardanlabs/encore/app/domain/tranapp"
 	userapp "github.com/ardanlabs/encore/app/domain/userapp"
 	vproductapp "github.com/ardanlabs/encore/app/domain/vproductapp"
+	"github.com/ardanlabs/encore/app/domain/webhookapp"
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/ardanlabs/encore/business/domain/deadletterbus"
+	"github.com/ardanlabs/encore/business/domain/eventbus"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/tagbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
 )
 
 type appDomain struct {
-	homeApp     *homeapp.App
-	productApp  *productapp.App
-	tranApp     *tranapp.App
-	userApp     *userapp.App
-	vproductApp *vproductapp.App
+	categoryApp   *categoryapp.App
+	deadLetterApp *deadletterapp.App
+	eventApp      *eventapp.App
+	homeApp       *homeapp.App
+	productApp    *productapp.App
+	tagApp        *tagapp.App
+	tranApp       *tranapp.App
+	userApp       *userapp.App
+	vproductApp   *vproductapp.App
+	webhookApp    *webhookapp.App
 }
 
 type busDomain struct {
-	delegate   *delegate.Delegate
-	homeBus    *homebus.Business
-	productBus *productbus.Business
-	userBus    *userbus.Business
+	delegate      *delegate.Delegate
+	categoryBus   *categorybus.Business
+	deadLetterBus *deadletterbus.Business
+	eventBus      *eventbus.Business
+	homeBus       *homebus.Business
+	outboxBus     *outboxbus.Business
+	productBus    *productbus.Business
+	tagBus        *tagbus.Business
+	userBus       *userbus.Business
+	webhookBus    *webhookbus.Business
 }
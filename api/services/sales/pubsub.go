@@ -2,23 +2,111 @@ package sales
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"encore.dev/pubsub"
+	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/notify"
 	bpubsub "github.com/ardanlabs/encore/business/sdk/pubsub"
+	"github.com/google/uuid"
+)
+
+// Set of subscriptions to the delegate topic. The names also identify the
+// consumers when dropping the events they have already handled.
+const (
+	subDelegateCall    = "handle-delegate-call"
+	subWebhookDelivery = "webhook-delivery"
 )
 
 // We need a single subscription which will route a message to the
 // delegate system.
-var _ = pubsub.NewSubscription(bpubsub.Delegate, "handle-delegate-call",
+var _ = pubsub.NewSubscription(bpubsub.Delegate, subDelegateCall,
 	pubsub.SubscriptionConfig[delegate.Data]{
 		Handler: pubsub.MethodHandler((*Service).DelegateHandler),
 	},
 )
 
+// Webhooks are sent from their own subscription so a webhook that is slow to
+// respond doesn't hold up the delegate system.
+var _ = pubsub.NewSubscription(bpubsub.Delegate, subWebhookDelivery,
+	pubsub.SubscriptionConfig[delegate.Data]{
+		Handler: pubsub.MethodHandler((*Service).WebhookHandler),
+	},
+)
+
 // DelegateHandler receives a message from the pubsub system and passes it
-// into the delegate system.
+// into the delegate system. The copies of an event sent to the asynchronous
+// functions keep the id of the event, so each target is its own consumer.
 func (s *Service) DelegateHandler(ctx context.Context, data delegate.Data) error {
 	s.log.Info(ctx, "DelegateHandler", "data", data)
-	return s.delegate.Call(ctx, data)
+
+	consumer := subDelegateCall
+	if data.Target != "" {
+		consumer += ":" + data.Target
+	}
+
+	return s.once(ctx, consumer, data, s.delegate.Call)
+}
+
+// WebhookHandler receives a message from the pubsub system and sends the
+// event to the webhooks registered for it. The copies of an event sent to
+// the asynchronous delegate functions aren't sent again.
+func (s *Service) WebhookHandler(ctx context.Context, data delegate.Data) error {
+	if data.Target != "" {
+		return nil
+	}
+
+	return s.once(ctx, subWebhookDelivery, data, s.webhookBus.Dispatch)
+}
+
+// once calls fn with an event the consumer hasn't handled yet. Events from
+// the outbox can be delivered more than once, the ones published without
+// going through it have no id and are always handled. The event is only
+// claimed once fn succeeds, so an event is never lost to a consumer that
+// failed while handling it, but deliveries handled at the same time both
+// call fn and the functions must be safe to call again with an event.
+func (s *Service) once(ctx context.Context, consumer string, data delegate.Data, fn delegate.Func) error {
+	if data.ID == uuid.Nil {
+		return fn(ctx, data)
+	}
+
+	claimed, err := s.outboxBus.Claimed(ctx, consumer, data.ID)
+	if err != nil {
+		return err
+	}
+
+	if claimed {
+		s.log.Info(ctx, consumer, "status", "dropped duplicate", "eventID", data.ID)
+		return nil
+	}
+
+	if err := fn(ctx, data); err != nil {
+		return err
+	}
+
+	if _, err := s.outboxBus.Claim(ctx, consumer, data.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// notifyLowStock is executed by the delegate system when a product drops to
+// its reorder level and fans the event out to the configured notifiers.
+func (s *Service) notifyLowStock(ctx context.Context, data delegate.Data) error {
+	var params productbus.ActionLowStockParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	msg := notify.Message{
+		Kind:    data.Domain + "." + data.Action,
+		Subject: fmt.Sprintf("Low stock: %s", params.Name),
+		Body:    fmt.Sprintf("Product %s has %d units left, reorder level is %d.", params.ProductID, params.Quantity, params.ReorderLevel),
+		Data:    params,
+	}
+
+	return s.notifier.Notify(ctx, msg)
 }
@@ -2,15 +2,25 @@ package sales
 
 import (
 	"context"
+	"io"
 	"net/http"
 
 	"encore.dev"
+	"github.com/ardanlabs/encore/app/domain/categoryapp"
+	"github.com/ardanlabs/encore/app/domain/deadletterapp"
 	"github.com/ardanlabs/encore/app/domain/homeapp"
 	"github.com/ardanlabs/encore/app/domain/productapp"
+	"github.com/ardanlabs/encore/app/domain/tagapp"
 	"github.com/ardanlabs/encore/app/domain/tranapp"
 	"github.com/ardanlabs/encore/app/domain/userapp"
 	"github.com/ardanlabs/encore/app/domain/vproductapp"
+	"github.com/ardanlabs/encore/app/domain/webhookapp"
+	"github.com/ardanlabs/encore/app/sdk/bulk"
+	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/app/sdk/sse"
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
 // Fallback is called for the debug enpoints.
@@ -32,19 +42,67 @@ func (s *Service) Fallback(w http.ResponseWriter, r *http.Request) {
 // =============================================================================
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=POST path=/v1/homes tag:metrics tag:authorize tag:as_user_role
+//encore:api auth method=POST path=/v1/categories tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) CategoryCreate(ctx context.Context, app categoryapp.NewCategory) (categoryapp.Category, error) {
+	return s.categoryApp.Create(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=PUT path=/v1/categories/:categoryID tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) CategoryUpdate(ctx context.Context, categoryID string, app categoryapp.UpdateCategory) (categoryapp.Category, error) {
+	return s.categoryApp.Update(ctx, categoryID, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=DELETE path=/v1/categories/:categoryID tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) CategoryDelete(ctx context.Context, categoryID string) error {
+	return s.categoryApp.Delete(ctx, categoryID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/categories tag:metrics tag:authorize tag:as_any_role
+func (s *Service) CategoryQuery(ctx context.Context, qp categoryapp.QueryParams) (query.Result[categoryapp.Category], error) {
+	return s.categoryApp.Query(ctx, qp)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/categories/:categoryID tag:metrics tag:authorize tag:as_any_role
+func (s *Service) CategoryQueryByID(ctx context.Context, categoryID string) (categoryapp.Category, error) {
+	return s.categoryApp.QueryByID(ctx, categoryID)
+}
+
+// =============================================================================
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth raw method=GET path=/v1/events/stream tag:metrics tag:authorize tag:as_any_role
+func (s *Service) EventStream(w http.ResponseWriter, r *http.Request) {
+	s.sseStream(w, r, func(ctx context.Context, sw *sse.Writer) error {
+		return s.eventApp.Stream(ctx, r.Header.Get("Last-Event-ID"), sw)
+	})
+}
+
+// =============================================================================
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/homes tag:transaction_client_errors tag:metrics tag:authorize tag:as_user_role
 func (s *Service) HomeCreate(ctx context.Context, app homeapp.NewHome) (homeapp.Home, error) {
 	return s.homeApp.Create(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=PUT path=/v1/homes/:homeID tag:metrics tag:authorize_home
+//encore:api auth method=PUT path=/v1/homes/:homeID tag:transaction_client_errors tag:metrics tag:authorize_home tag:as_home_editor
 func (s *Service) HomeUpdate(ctx context.Context, homeID string, app homeapp.UpdateHome) (homeapp.Home, error) {
 	return s.homeApp.Update(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=DELETE path=/v1/homes/:homeID tag:metrics tag:authorize_home
+//encore:api auth method=PATCH path=/v1/homes/:homeID tag:transaction_client_errors tag:metrics tag:authorize_home tag:as_home_editor
+func (s *Service) HomePatch(ctx context.Context, homeID string, app homeapp.PatchHome) (homeapp.Home, error) {
+	return s.homeApp.Patch(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=DELETE path=/v1/homes/:homeID tag:transaction_client_errors tag:metrics tag:authorize_home
 func (s *Service) HomeDelete(ctx context.Context, homeID string) error {
 	return s.homeApp.Delete(ctx)
 }
@@ -56,27 +114,81 @@ func (s *Service) HomeQuery(ctx context.Context, qp homeapp.QueryParams) (query.
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=GET path=/v1/homes/:productID tag:metrics tag:authorize_home
-func (s *Service) HomeQueryByID(ctx context.Context, productID string) (homeapp.Home, error) {
+//encore:api auth method=GET path=/v1/homes/:homeID tag:metrics tag:authorize_home tag:as_home_viewer
+func (s *Service) HomeQueryByID(ctx context.Context, homeID string) (homeapp.Home, error) {
 	return s.homeApp.QueryByID(ctx)
 }
 
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/homes:batchGet tag:metrics tag:authorize tag:as_any_role
+func (s *Service) HomeQueryByIDs(ctx context.Context, app query.BatchGet) (query.Batch[homeapp.Home], error) {
+	return s.homeApp.QueryByIDs(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/homes:bulkCreate tag:transaction_client_errors tag:metrics tag:authorize tag:as_user_role
+func (s *Service) HomeCreateMany(ctx context.Context, app homeapp.NewHomes) (bulk.Result, error) {
+	return s.homeApp.CreateMany(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/homes:bulkUpdate tag:transaction_client_errors tag:metrics tag:authorize tag:as_any_role
+func (s *Service) HomeUpdateMany(ctx context.Context, app homeapp.UpdateHomes) (bulk.Result, error) {
+	return s.homeApp.UpdateMany(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/homes/:homeID/members tag:metrics tag:authorize_home
+func (s *Service) HomeMemberAdd(ctx context.Context, homeID string, app homeapp.NewMember) (homeapp.Member, error) {
+	return s.homeApp.AddMember(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=DELETE path=/v1/homes/:homeID/members/:userID tag:metrics tag:authorize_home
+func (s *Service) HomeMemberRemove(ctx context.Context, homeID string, userID string) error {
+	return s.homeApp.RemoveMember(ctx, userID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/homes/:homeID/transfers tag:metrics tag:authorize_home
+func (s *Service) HomeTransferRequest(ctx context.Context, homeID string, app homeapp.NewTransfer) (homeapp.Transfer, error) {
+	return s.homeApp.RequestTransfer(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/hometransfers/:transferID/accept tag:transaction_client_errors tag:metrics tag:authorize tag:as_user_role
+func (s *Service) HomeTransferAccept(ctx context.Context, transferID string) (homeapp.Home, error) {
+	return s.homeApp.AcceptTransfer(ctx, transferID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/homes/:homeID/members tag:metrics tag:authorize_home tag:as_home_viewer
+func (s *Service) HomeMemberQuery(ctx context.Context, homeID string) (homeapp.Members, error) {
+	return s.homeApp.QueryMembers(ctx)
+}
+
 // =============================================================================
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=POST path=/v1/products tag:metrics tag:authorize tag:as_user_role
+//encore:api auth method=POST path=/v1/products tag:transaction_client_errors tag:metrics tag:authorize tag:as_user_role
 func (s *Service) ProductCreate(ctx context.Context, app productapp.NewProduct) (productapp.Product, error) {
 	return s.productApp.Create(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=PUT path=/v1/products/:productID tag:metrics tag:authorize_product
+//encore:api auth method=PUT path=/v1/products/:productID tag:transaction_client_errors tag:metrics tag:authorize_product
 func (s *Service) ProductUpdate(ctx context.Context, productID string, app productapp.UpdateProduct) (productapp.Product, error) {
 	return s.productApp.Update(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=DELETE path=/v1/products/:productID tag:metrics tag:authorize_product
+//encore:api auth method=PATCH path=/v1/products/:productID tag:transaction_client_errors tag:metrics tag:authorize_product
+func (s *Service) ProductPatch(ctx context.Context, productID string, app productapp.PatchProduct) (productapp.Product, error) {
+	return s.productApp.Patch(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=DELETE path=/v1/products/:productID tag:transaction_client_errors tag:metrics tag:authorize_product
 func (s *Service) ProductDelete(ctx context.Context, productID string) error {
 	return s.productApp.Delete(ctx)
 }
@@ -93,6 +205,136 @@ func (s *Service) ProductQueryByID(ctx context.Context, productID string) (produ
 	return s.productApp.QueryByID(ctx)
 }
 
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/products:batchGet tag:metrics tag:authorize tag:as_any_role
+func (s *Service) ProductQueryByIDs(ctx context.Context, app query.BatchGet) (query.Batch[productapp.Product], error) {
+	return s.productApp.QueryByIDs(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth raw method=GET path=/v1/products/export.csv tag:metrics tag:authorize tag:as_any_role
+func (s *Service) ProductExport(w http.ResponseWriter, r *http.Request) {
+	s.csvExport(w, r, "products.csv", func(ctx context.Context, w io.Writer) error {
+		var qp productapp.QueryParams
+		if err := query.Decode(r.URL.Query(), &qp); err != nil {
+			return errs.New(errs.InvalidArgument, err)
+		}
+
+		return s.productApp.Export(ctx, w, qp)
+	})
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth raw method=POST path=/v1/products/import tag:metrics tag:authorize tag:as_user_role
+func (s *Service) ProductImport(w http.ResponseWriter, r *http.Request) {
+	ctx, events := outboxbus.Track(r.Context())
+
+	// Every chunk is committed on its own, so the events of the chunks that
+	// were committed are relayed even when the import fails.
+	rpt, err := s.productApp.Import(ctx, sqldb.NewBeginner(s.db), r.Body)
+	s.relayOutbox(ctx, events())
+
+	s.csvImport(w, rpt, err)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/products:bulkCreate tag:transaction_client_errors tag:metrics tag:authorize tag:as_user_role
+func (s *Service) ProductCreateMany(ctx context.Context, app productapp.NewProducts) (bulk.Result, error) {
+	return s.productApp.CreateMany(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/products:bulkUpdate tag:transaction_client_errors tag:metrics tag:authorize tag:as_any_role
+func (s *Service) ProductUpdateMany(ctx context.Context, app productapp.UpdateProducts) (bulk.Result, error) {
+	return s.productApp.UpdateMany(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/products/:productID/movements tag:transaction_client_errors tag:metrics tag:authorize_product
+func (s *Service) ProductMovementCreate(ctx context.Context, productID string, app productapp.NewMovement) (productapp.Movement, error) {
+	return s.productApp.CreateMovement(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/products/:productID/movements tag:metrics tag:authorize_product
+func (s *Service) ProductMovementQuery(ctx context.Context, productID string, qp productapp.MovementQueryParams) (query.Result[productapp.Movement], error) {
+	return s.productApp.QueryMovements(ctx, qp)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/products/:productID/reconcile tag:metrics tag:authorize_product
+func (s *Service) ProductReconcile(ctx context.Context, productID string) (productapp.Reconciliation, error) {
+	return s.productApp.Reconcile(ctx)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/products/:productID/prices tag:metrics tag:authorize_product
+func (s *Service) ProductPriceSchedule(ctx context.Context, productID string, app productapp.NewPrice) (productapp.Price, error) {
+	return s.productApp.SchedulePrice(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/products/:productID/prices tag:metrics tag:authorize_product
+func (s *Service) ProductPriceQuery(ctx context.Context, productID string, qp productapp.PriceQueryParams) (query.Result[productapp.Price], error) {
+	return s.productApp.QueryPrices(ctx, qp)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=PUT path=/v1/products/:productID/categories tag:metrics tag:authorize_product
+func (s *Service) ProductCategoriesSet(ctx context.Context, productID string, app productapp.ProductCategories) (productapp.ProductCategories, error) {
+	return s.productApp.SetCategories(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/products/:productID/categories tag:metrics tag:authorize_product
+func (s *Service) ProductCategoriesQuery(ctx context.Context, productID string) (productapp.ProductCategories, error) {
+	return s.productApp.QueryCategories(ctx)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=PUT path=/v1/products/:productID/tags tag:metrics tag:authorize_product
+func (s *Service) ProductTagsSet(ctx context.Context, productID string, app productapp.ProductTags) (productapp.ProductTags, error) {
+	return s.productApp.SetTags(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/products/:productID/tags tag:metrics tag:authorize_product
+func (s *Service) ProductTagsQuery(ctx context.Context, productID string) (productapp.ProductTags, error) {
+	return s.productApp.QueryTags(ctx)
+}
+
+// =============================================================================
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/tags tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) TagCreate(ctx context.Context, app tagapp.NewTag) (tagapp.Tag, error) {
+	return s.tagApp.Create(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=PUT path=/v1/tags/:tagID tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) TagUpdate(ctx context.Context, tagID string, app tagapp.UpdateTag) (tagapp.Tag, error) {
+	return s.tagApp.Update(ctx, tagID, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=DELETE path=/v1/tags/:tagID tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) TagDelete(ctx context.Context, tagID string) error {
+	return s.tagApp.Delete(ctx, tagID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/tags tag:metrics tag:authorize tag:as_any_role
+func (s *Service) TagQuery(ctx context.Context, qp tagapp.QueryParams) (query.Result[tagapp.Tag], error) {
+	return s.tagApp.Query(ctx, qp)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/tags/:tagID tag:metrics tag:authorize tag:as_any_role
+func (s *Service) TagQueryByID(ctx context.Context, tagID string) (tagapp.Tag, error) {
+	return s.tagApp.QueryByID(ctx, tagID)
+}
+
 // =============================================================================
 
 //lint:ignore U1000 "called by encore"
@@ -104,25 +346,31 @@ func (s *Service) TranCreate(ctx context.Context, app tranapp.NewTran) (tranapp.
 // =============================================================================
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=POST path=/v1/users tag:metrics tag:authorize tag:as_admin_role
+//encore:api auth method=POST path=/v1/users tag:transaction_client_errors tag:metrics tag:authorize tag:as_admin_role
 func (s *Service) UserCreate(ctx context.Context, app userapp.NewUser) (userapp.User, error) {
 	return s.userApp.Create(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=PUT path=/v1/users/:userID tag:metrics tag:authorize_user
+//encore:api auth method=PUT path=/v1/users/:userID tag:transaction_client_errors tag:metrics tag:authorize_user
 func (s *Service) UserUpdate(ctx context.Context, userID string, app userapp.UpdateUser) (userapp.User, error) {
 	return s.userApp.Update(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=PUT path=/v1/role/:userID tag:metrics tag:authorize_user tag:as_admin_role
+//encore:api auth method=PATCH path=/v1/users/:userID tag:transaction_client_errors tag:metrics tag:authorize_user
+func (s *Service) UserPatch(ctx context.Context, userID string, app userapp.PatchUser) (userapp.User, error) {
+	return s.userApp.Patch(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=PUT path=/v1/role/:userID tag:transaction_client_errors tag:metrics tag:authorize_user tag:as_admin_role
 func (s *Service) UserUpdateRole(ctx context.Context, userID string, app userapp.UpdateUserRole) (userapp.User, error) {
 	return s.userApp.UpdateRole(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=DELETE path=/v1/users/:userID tag:metrics tag:authorize_user
+//encore:api auth method=DELETE path=/v1/users/:userID tag:transaction_client_errors tag:metrics tag:authorize_user
 func (s *Service) UserDelete(ctx context.Context, userID string) error {
 	return s.userApp.Delete(ctx)
 }
@@ -139,6 +387,38 @@ func (s *Service) UserQueryByID(ctx context.Context, userID string) (userapp.Use
 	return s.userApp.QueryByID(ctx)
 }
 
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/users:batchGet tag:metrics tag:authorize tag:as_any_role
+func (s *Service) UserQueryByIDs(ctx context.Context, app query.BatchGet) (query.Batch[userapp.User], error) {
+	return s.userApp.QueryByIDs(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth raw method=GET path=/v1/users/export.csv tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) UserExport(w http.ResponseWriter, r *http.Request) {
+	s.csvExport(w, r, "users.csv", func(ctx context.Context, w io.Writer) error {
+		var qp userapp.QueryParams
+		if err := query.Decode(r.URL.Query(), &qp); err != nil {
+			return errs.New(errs.InvalidArgument, err)
+		}
+
+		return s.userApp.Export(ctx, w, qp)
+	})
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth raw method=POST path=/v1/users/import tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) UserImport(w http.ResponseWriter, r *http.Request) {
+	ctx, events := outboxbus.Track(r.Context())
+
+	// Every chunk is committed on its own, so the events of the chunks that
+	// were committed are relayed even when the import fails.
+	rpt, err := s.userApp.Import(ctx, sqldb.NewBeginner(s.db), r.Body)
+	s.relayOutbox(ctx, events())
+
+	s.csvImport(w, rpt, err)
+}
+
 // =============================================================================
 
 //lint:ignore U1000 "called by encore"
@@ -146,3 +426,65 @@ func (s *Service) UserQueryByID(ctx context.Context, userID string) (userapp.Use
 func (s *Service) VProductQuery(ctx context.Context, qp vproductapp.QueryParams) (query.Result[vproductapp.Product], error) {
 	return s.vproductApp.Query(ctx, qp)
 }
+
+// =============================================================================
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/webhooks tag:metrics tag:authorize tag:as_any_role
+func (s *Service) WebhookCreate(ctx context.Context, app webhookapp.NewWebhook) (webhookapp.Webhook, error) {
+	return s.webhookApp.Create(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=PUT path=/v1/webhooks/:webhookID tag:metrics tag:authorize_webhook
+func (s *Service) WebhookUpdate(ctx context.Context, webhookID string, app webhookapp.UpdateWebhook) (webhookapp.Webhook, error) {
+	return s.webhookApp.Update(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=DELETE path=/v1/webhooks/:webhookID tag:metrics tag:authorize_webhook
+func (s *Service) WebhookDelete(ctx context.Context, webhookID string) error {
+	return s.webhookApp.Delete(ctx)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/webhooks tag:metrics tag:authorize tag:as_any_role
+func (s *Service) WebhookQuery(ctx context.Context) (webhookapp.Webhooks, error) {
+	return s.webhookApp.Query(ctx)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/webhooks/:webhookID tag:metrics tag:authorize_webhook
+func (s *Service) WebhookQueryByID(ctx context.Context, webhookID string) (webhookapp.Webhook, error) {
+	return s.webhookApp.QueryByID(ctx)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/webhooks/:webhookID/deliveries tag:metrics tag:authorize_webhook
+func (s *Service) WebhookDeliveryQuery(ctx context.Context, webhookID string, qp webhookapp.DeliveryQueryParams) (query.Result[webhookapp.Delivery], error) {
+	return s.webhookApp.QueryDeliveries(ctx, qp)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/webhooks/:webhookID/deliveries/:deliveryID/replay tag:metrics tag:authorize_webhook
+func (s *Service) WebhookDeliveryReplay(ctx context.Context, webhookID string, deliveryID string) (webhookapp.Delivery, error) {
+	return s.webhookApp.Replay(ctx, deliveryID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/deadletters tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) DeadLetterQuery(ctx context.Context, qp deadletterapp.QueryParams) (query.Result[deadletterapp.DeadLetter], error) {
+	return s.deadLetterApp.Query(ctx, qp)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/deadletters/:deadLetterID tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) DeadLetterQueryByID(ctx context.Context, deadLetterID string) (deadletterapp.DeadLetter, error) {
+	return s.deadLetterApp.QueryByID(ctx, deadLetterID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/deadletters/:deadLetterID/replay tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) DeadLetterReplay(ctx context.Context, deadLetterID string) (deadletterapp.DeadLetter, error) {
+	return s.deadLetterApp.Replay(ctx, deadLetterID)
+}
@@ -7,27 +7,50 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"time"
 
 	"encore.dev"
 	esqldb "encore.dev/storage/sqldb"
 	"github.com/ardanlabs/conf/v3"
+	"github.com/ardanlabs/encore/app/domain/categoryapp"
+	"github.com/ardanlabs/encore/app/domain/deadletterapp"
+	"github.com/ardanlabs/encore/app/domain/eventapp"
 	"github.com/ardanlabs/encore/app/domain/homeapp"
 	"github.com/ardanlabs/encore/app/domain/productapp"
+	"github.com/ardanlabs/encore/app/domain/tagapp"
 	"github.com/ardanlabs/encore/app/domain/tranapp"
 	"github.com/ardanlabs/encore/app/domain/userapp"
 	"github.com/ardanlabs/encore/app/domain/vproductapp"
+	"github.com/ardanlabs/encore/app/domain/webhookapp"
 	"github.com/ardanlabs/encore/app/sdk/debug"
 	"github.com/ardanlabs/encore/app/sdk/metrics"
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/ardanlabs/encore/business/domain/categorybus/stores/categorydb"
+	"github.com/ardanlabs/encore/business/domain/deadletterbus"
+	"github.com/ardanlabs/encore/business/domain/deadletterbus/stores/deadletterdb"
+	"github.com/ardanlabs/encore/business/domain/eventbus"
+	"github.com/ardanlabs/encore/business/domain/eventbus/stores/eventdb"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/homebus/address"
+	"github.com/ardanlabs/encore/business/domain/homebus/geocode"
 	"github.com/ardanlabs/encore/business/domain/homebus/stores/homedb"
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
+	"github.com/ardanlabs/encore/business/domain/outboxbus/stores/outboxdb"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/productbus/stores/productdb"
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+	"github.com/ardanlabs/encore/business/domain/tagbus/stores/tagdb"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/domain/userbus/stores/userdb"
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
 	"github.com/ardanlabs/encore/business/domain/vproductbus/stores/vproductdb"
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
+	"github.com/ardanlabs/encore/business/domain/webhookbus/sender"
+	"github.com/ardanlabs/encore/business/domain/webhookbus/stores/webhookdb"
 	"github.com/ardanlabs/encore/business/sdk/appdb/migrate"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/notify"
+	bpubsub "github.com/ardanlabs/encore/business/sdk/pubsub"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/foundation/logger"
 	"github.com/jmoiron/sqlx"
@@ -43,42 +66,65 @@ var appDB = esqldb.Named("app")
 //
 //encore:service
 type Service struct {
-	log   *logger.Logger
-	mtrcs *metrics.Values
-	db    *sqlx.DB
-	debug http.Handler
+	log      *logger.Logger
+	mtrcs    *metrics.Values
+	db       *sqlx.DB
+	debug    http.Handler
+	notifier notify.Notifier
 	appDomain
 	busDomain
 }
 
 // NewService is called to create a new encore Service.
-func NewService(log *logger.Logger, db *sqlx.DB) (*Service, error) {
-	delegate := delegate.New(log)
-	userBus := userbus.NewBusiness(log, delegate, userdb.NewStore(log, db))
-	productBus := productbus.NewBusiness(log, userBus, delegate, productdb.NewStore(log, db))
-	homeBus := homebus.NewBusiness(log, userBus, delegate, homedb.NewStore(log, db))
+func NewService(log *logger.Logger, db *sqlx.DB, notifier notify.Notifier, geocoder homebus.Geocoder) (*Service, error) {
+	deadLetterBus := deadletterbus.NewBusiness(log, deadletterdb.NewStore(log, db))
+	dlg := delegate.New(log, bpubsub.Delegate, deadLetterBus)
+	outboxBus := outboxbus.NewBusiness(log, bpubsub.Delegate, outboxdb.NewStore(log, db))
+	userBus := userbus.NewBusiness(log, outboxBus, userdb.NewStore(log, db))
+	productBus := productbus.NewBusiness(log, userBus, dlg, outboxBus, productdb.NewStore(log, db))
+	homeBus := homebus.NewBusiness(log, userBus, dlg, outboxBus, address.NewValidator(), geocoder, homedb.NewStore(log, db))
 	vproductBus := vproductbus.NewBusiness(vproductdb.NewStore(log, db))
+	categoryBus := categorybus.NewBusiness(log, categorydb.NewStore(log, db))
+	tagBus := tagbus.NewBusiness(log, tagdb.NewStore(log, db))
+	eventBus := eventbus.NewBusiness(log, dlg, eventdb.NewStore(log, db), eventbus.DefaultSize)
+	webhookBus := webhookbus.NewBusiness(log, userBus, sender.NewHTTP(5*time.Second), webhookdb.NewStore(log, db))
 
 	s := Service{
-		log:   log,
-		mtrcs: newMetrics(),
-		db:    db,
-		debug: debug.Mux(),
+		log:      log,
+		mtrcs:    newMetrics(),
+		db:       db,
+		debug:    debug.Mux(),
+		notifier: notifier,
 		appDomain: appDomain{
-			userApp:     userapp.NewApp(userBus),
-			productApp:  productapp.NewApp(productBus),
-			homeApp:     homeapp.NewApp(homeBus),
-			tranApp:     tranapp.NewApp(userBus, productBus),
-			vproductApp: vproductapp.NewApp(vproductBus),
+			userApp:       userapp.NewApp(userBus),
+			productApp:    productapp.NewApp(productBus, userBus),
+			homeApp:       homeapp.NewApp(homeBus, userBus),
+			tranApp:       tranapp.NewApp(userBus, productBus),
+			vproductApp:   vproductapp.NewApp(vproductBus),
+			categoryApp:   categoryapp.NewApp(categoryBus),
+			deadLetterApp: deadletterapp.NewApp(deadLetterBus, dlg),
+			eventApp:      eventapp.NewApp(eventBus),
+			tagApp:        tagapp.NewApp(tagBus),
+			webhookApp:    webhookapp.NewApp(webhookBus),
 		},
 		busDomain: busDomain{
-			delegate:   delegate,
-			userBus:    userBus,
-			productBus: productBus,
-			homeBus:    homeBus,
+			delegate:      dlg,
+			userBus:       userBus,
+			productBus:    productBus,
+			homeBus:       homeBus,
+			outboxBus:     outboxBus,
+			categoryBus:   categoryBus,
+			deadLetterBus: deadLetterBus,
+			eventBus:      eventBus,
+			tagBus:        tagBus,
+			webhookBus:    webhookBus,
 		},
 	}
 
+	// Notifiers call out to other systems, so they get their own copy of the
+	// event and don't hold up the other functions.
+	dlg.Register(productbus.DomainName, productbus.ActionLowStock, "sales.notify-low-stock", s.notifyLowStock, delegate.WithMode(delegate.Async))
+
 	return &s, nil
 }
 
@@ -101,15 +147,15 @@ func (s *Service) Shutdown(force context.Context) {
 func initService() (*Service, error) {
 	log := logger.New("sales")
 
-	db, err := startup(log)
+	db, notifier, geocoder, err := startup(log)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewService(log, db)
+	return NewService(log, db, notifier, geocoder)
 }
 
-func startup(log *logger.Logger) (*sqlx.DB, error) {
+func startup(log *logger.Logger) (*sqlx.DB, notify.Notifier, homebus.Geocoder, error) {
 	ctx := context.Background()
 
 	// -------------------------------------------------------------------------
@@ -126,6 +172,14 @@ func startup(log *logger.Logger) (*sqlx.DB, error) {
 			MaxIdleConns int `conf:"default:0"`
 			MaxOpenConns int `conf:"default:0"`
 		}
+		Notify struct {
+			WebhookURL string
+		}
+		Geocode struct {
+			File      string
+			URL       string
+			UserAgent string `conf:"default:ardanlabs-encore-sales"`
+		}
 	}{
 		Version: conf.Version{
 			Build: encore.Meta().Environment.Name,
@@ -138,9 +192,9 @@ func startup(log *logger.Logger) (*sqlx.DB, error) {
 	if err != nil {
 		if errors.Is(err, conf.ErrHelpWanted) {
 			fmt.Println(help)
-			return nil, err
+			return nil, nil, nil, err
 		}
-		return nil, fmt.Errorf("parsing config: %w", err)
+		return nil, nil, nil, fmt.Errorf("parsing config: %w", err)
 	}
 
 	// -------------------------------------------------------------------------
@@ -150,7 +204,7 @@ func startup(log *logger.Logger) (*sqlx.DB, error) {
 
 	out, err := conf.String(&cfg)
 	if err != nil {
-		return nil, fmt.Errorf("generating config for output: %w", err)
+		return nil, nil, nil, fmt.Errorf("generating config for output: %w", err)
 	}
 	log.Info(ctx, "initService", "config", out)
 
@@ -165,12 +219,39 @@ func startup(log *logger.Logger) (*sqlx.DB, error) {
 		MaxOpenConns: cfg.DB.MaxOpenConns,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("connecting to db: %w", err)
+		return nil, nil, nil, fmt.Errorf("connecting to db: %w", err)
 	}
 
 	if err := migrate.Seed(context.Background(), db); err != nil {
-		return nil, fmt.Errorf("seeding the db: %w", err)
+		return nil, nil, nil, fmt.Errorf("seeding the db: %w", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// Notification Support
+
+	notifiers := []notify.Notifier{notify.NewLog(log)}
+	if cfg.Notify.WebhookURL != "" {
+		notifiers = append(notifiers, notify.NewWebhook(cfg.Notify.WebhookURL))
+	}
+
+	// -------------------------------------------------------------------------
+	// Geocoding Support
+
+	// Without a configured geocoder homes are saved without a location and
+	// won't show up in proximity searches.
+	var geocoder homebus.Geocoder = &geocode.File{}
+
+	switch {
+	case cfg.Geocode.File != "":
+		file, err := geocode.LoadFile(cfg.Geocode.File)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("loading geocode file: %w", err)
+		}
+		geocoder = file
+
+	case cfg.Geocode.URL != "":
+		geocoder = geocode.NewNominatim(cfg.Geocode.URL, cfg.Geocode.UserAgent)
 	}
 
-	return db, nil
+	return db, notify.NewFanout(log, notifiers...), geocoder, nil
 }
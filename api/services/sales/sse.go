@@ -0,0 +1,33 @@
+package sales
+
+import (
+	"context"
+	"net/http"
+
+	eerrs "encore.dev/beta/errs"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/sse"
+)
+
+// sseStream sends the events written by stream to the client. An error found
+// before the stream starts goes back to the client, while one found after
+// can only be logged since the response is already on its way.
+func (s *Service) sseStream(w http.ResponseWriter, r *http.Request, stream func(ctx context.Context, sw *sse.Writer) error) {
+	sw, err := sse.NewWriter(w)
+	if err != nil {
+		eerrs.HTTPError(w, errs.Newf(errs.Internal, "sse: %s", err))
+		return
+	}
+
+	if err := stream(r.Context(), sw); err != nil {
+		if !sw.Started() {
+			eerrs.HTTPError(w, err)
+			return
+		}
+
+		// The client going away ends the stream and is not an error.
+		if r.Context().Err() == nil {
+			s.log.Error(r.Context(), "sse stream", "path", r.URL.Path, "msg", err)
+		}
+	}
+}
@@ -25,6 +25,10 @@ func createOk(sd apitest.SeedData) []apitest.Table {
 					State:    "AL",
 					Country:  "US",
 				},
+				Location: &homeapp.Location{
+					Latitude:  34.7784,
+					Longitude: -86.5879,
+				},
 			},
 			ExcFunc: func(ctx context.Context) any {
 				app := homeapp.NewHome{
@@ -70,7 +74,7 @@ func createBad(sd apitest.SeedData) []apitest.Table {
 		{
 			Name:    "missing",
 			Token:   sd.Users[0].Token,
-			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"type\",\"error\":\"type is a required field\"},{\"field\":\"address1\",\"error\":\"address1 is a required field\"},{\"field\":\"zipCode\",\"error\":\"zipCode is a required field\"},{\"field\":\"city\",\"error\":\"city is a required field\"},{\"field\":\"state\",\"error\":\"state is a required field\"},{\"field\":\"country\",\"error\":\"country is a required field\"}]"),
+			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"type\",\"error\":\"type is a required field\"},{\"field\":\"address1\",\"error\":\"address1 is a required field\"},{\"field\":\"zipCode\",\"error\":\"zipCode is a required field\"},{\"field\":\"city\",\"error\":\"city is a required field\"},{\"field\":\"country\",\"error\":\"country is a required field\"}]"),
 			ExcFunc: func(ctx context.Context) any {
 				resp, err := sales.HomeCreate(ctx, homeapp.NewHome{})
 				if err != nil {
@@ -8,6 +8,14 @@ import (
 )
 
 func toAppHome(hme homebus.Home) homeapp.Home {
+	var loc *homeapp.Location
+	if !hme.Location.IsZero() {
+		loc = &homeapp.Location{
+			Latitude:  hme.Location.Latitude,
+			Longitude: hme.Location.Longitude,
+		}
+	}
+
 	return homeapp.Home{
 		ID:     hme.ID.String(),
 		UserID: hme.UserID.String(),
@@ -20,6 +28,14 @@ func toAppHome(hme homebus.Home) homeapp.Home {
 			State:    hme.Address.State,
 			Country:  hme.Address.Country,
 		},
+		Attributes: homeapp.Attributes{
+			Bedrooms:   hme.Attributes.Bedrooms,
+			Bathrooms:  hme.Attributes.Bathrooms,
+			SquareFeet: hme.Attributes.SquareFeet,
+			YearBuilt:  hme.Attributes.YearBuilt,
+			Units:      hme.Attributes.Units,
+		},
+		Location:    loc,
 		DateCreated: hme.DateCreated.Format(time.RFC3339),
 		DateUpdated: hme.DateUpdated.Format(time.RFC3339),
 	}
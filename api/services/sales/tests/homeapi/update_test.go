@@ -29,6 +29,10 @@ func updateOk(sd apitest.SeedData) []apitest.Table {
 					State:    "AL",
 					Country:  "US",
 				},
+				Location: &homeapp.Location{
+					Latitude:  34.7784,
+					Longitude: -86.5879,
+				},
 				DateCreated: sd.Users[0].Homes[0].DateCreated.Format(time.RFC3339),
 				DateUpdated: sd.Users[0].Homes[0].DateCreated.Format(time.RFC3339),
 			},
@@ -68,7 +72,7 @@ func updateBad(sd apitest.SeedData) []apitest.Table {
 		{
 			Name:    "input",
 			Token:   sd.Users[0].Token,
-			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"address1\",\"error\":\"address1 must be at least 1 character in length\"},{\"field\":\"zipCode\",\"error\":\"zipCode must be a valid numeric value\"},{\"field\":\"state\",\"error\":\"state must be at least 1 character in length\"},{\"field\":\"country\",\"error\":\"Key: 'UpdateHome.address.country' Error:Field validation for 'country' failed on the 'iso3166_1_alpha2' tag\"}]"),
+			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"address1\",\"error\":\"address1 must be at least 1 character in length\"},{\"field\":\"zipCode\",\"error\":\"zipCode must be at least 1 character in length\"},{\"field\":\"country\",\"error\":\"Key: 'UpdateHome.address.country' Error:Field validation for 'country' failed on the 'iso3166_1_alpha2' tag\"}]"),
 			ExcFunc: func(ctx context.Context) any {
 				app := homeapp.UpdateHome{
 					Address: &homeapp.UpdateAddress{
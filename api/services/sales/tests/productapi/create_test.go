@@ -6,6 +6,7 @@ import (
 	"github.com/ardanlabs/encore/api/services/sales"
 	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
 	"github.com/ardanlabs/encore/app/domain/productapp"
+	"github.com/ardanlabs/encore/app/sdk/bulk"
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/google/go-cmp/cmp"
 )
@@ -20,6 +21,7 @@ func createOk(sd apitest.SeedData) []apitest.Table {
 				Name:     "Guitar",
 				Cost:     10.34,
 				Quantity: 10,
+				Active:   true,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				app := productapp.NewProduct{
@@ -144,3 +146,53 @@ func createAuth(sd apitest.SeedData) []apitest.Table {
 
 	return table
 }
+
+func createManyOk(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:  "besteffort",
+			Token: sd.Users[0].Token,
+			ExpResp: bulk.Result{
+				Items: []bulk.Item{
+					{Index: 0},
+					{Index: 1, Errors: errs.FieldErrors{{Field: "quantity", Err: "quantity is a required field"}}},
+				},
+				Succeeded: 1,
+				Failed:    1,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				app := productapp.NewProducts{
+					Mode: "bestEffort",
+					Items: []productapp.NewProduct{
+						{Name: "Banjo", Cost: 45, Quantity: 3},
+						{Name: "Cello", Cost: 300},
+					},
+				}
+
+				resp, err := sales.ProductCreateMany(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(bulk.Result)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(bulk.Result)
+
+				if gotResp.Items[0].ID == "" {
+					return "expected an id for the created product"
+				}
+				expResp.Items[0].ID = gotResp.Items[0].ID
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
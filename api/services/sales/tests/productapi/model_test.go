@@ -9,13 +9,16 @@ import (
 
 func toAppProduct(prd productbus.Product) productapp.Product {
 	return productapp.Product{
-		ID:          prd.ID.String(),
-		UserID:      prd.UserID.String(),
-		Name:        prd.Name.String(),
-		Cost:        prd.Cost,
-		Quantity:    prd.Quantity,
-		DateCreated: prd.DateCreated.Format(time.RFC3339),
-		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
+		ID:           prd.ID.String(),
+		UserID:       prd.UserID.String(),
+		Name:         prd.Name.String(),
+		Cost:         prd.Cost,
+		Quantity:     prd.Quantity,
+		ReorderLevel: prd.ReorderLevel,
+		LowStock:     prd.LowStockAlerted,
+		Active:       prd.Active,
+		DateCreated:  prd.DateCreated.Format(time.RFC3339),
+		DateUpdated:  prd.DateUpdated.Format(time.RFC3339),
 	}
 }
 
@@ -0,0 +1,121 @@
+package product_test
+
+import (
+	"context"
+
+	"github.com/ardanlabs/encore/api/services/sales"
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/domain/productapp"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/google/go-cmp/cmp"
+)
+
+func movementOk(sd apitest.SeedData) []apitest.Table {
+	prd := sd.Admins[0].Products[0]
+
+	table := []apitest.Table{
+		{
+			Name:  "receipt",
+			Token: sd.Admins[0].Token,
+			ExpResp: productapp.Movement{
+				ProductID: prd.ID.String(),
+				Type:      "RECEIPT",
+				Quantity:  5,
+				Reason:    "restock",
+			},
+			ExcFunc: func(ctx context.Context) any {
+				app := productapp.NewMovement{
+					Type:     "RECEIPT",
+					Quantity: 5,
+					Reason:   "restock",
+				}
+
+				resp, err := sales.ProductMovementCreate(ctx, prd.ID.String(), app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(productapp.Movement)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(productapp.Movement)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:  "reconcile",
+			Token: sd.Admins[0].Token,
+			ExpResp: productapp.Reconciliation{
+				ProductID:      prd.ID.String(),
+				Quantity:       prd.Quantity + 5,
+				LedgerQuantity: prd.Quantity + 5,
+				Balanced:       true,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.ProductReconcile(ctx, prd.ID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func movementBad(sd apitest.SeedData) []apitest.Table {
+	prd := sd.Admins[0].Products[0]
+
+	table := []apitest.Table{
+		{
+			Name:    "missing",
+			Token:   sd.Admins[0].Token,
+			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"type\",\"error\":\"type is a required field\"},{\"field\":\"quantity\",\"error\":\"quantity is a required field\"},{\"field\":\"reason\",\"error\":\"reason is a required field\"}]"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.ProductMovementCreate(ctx, prd.ID.String(), productapp.NewMovement{})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "insufficient",
+			Token:   sd.Admins[0].Token,
+			ExpResp: errs.Newf(errs.FailedPrecondition, "insufficient stock"),
+			ExcFunc: func(ctx context.Context) any {
+				app := productapp.NewMovement{
+					Type:     "SALE",
+					Quantity: 100000,
+					Reason:   "order 1001",
+				}
+
+				resp, err := sales.ProductMovementCreate(ctx, prd.ID.String(), app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
@@ -20,15 +20,20 @@ func Test_Product(t *testing.T) {
 
 	test.Run(t, queryOk(sd), "query-ok")
 	test.Run(t, queryByIDOk(sd), "querybyid-ok")
+	test.Run(t, queryByIDsOk(sd), "querybyids-ok")
 
 	test.Run(t, createOk(sd), "create-ok")
 	test.Run(t, createBad(sd), "create-bad")
 	test.Run(t, createAuth(sd), "create-auth")
+	test.Run(t, createManyOk(sd), "createmany-ok")
 
 	test.Run(t, updateOk(sd), "update-ok")
 	test.Run(t, updateBad(sd), "update-bad")
 	test.Run(t, updateAuth(sd), "update-auth")
 
+	test.Run(t, movementOk(sd), "movement-ok")
+	test.Run(t, movementBad(sd), "movement-bad")
+
 	test.Run(t, deleteOk(sd), "delete-ok")
 	test.Run(t, deleteAuth(sd), "delete-auth")
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/ardanlabs/encore/app/sdk/query"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 )
 
 func queryOk(sd apitest.SeedData) []apitest.Table {
@@ -77,3 +78,68 @@ func queryByIDOk(sd apitest.SeedData) []apitest.Table {
 
 	return table
 }
+
+func queryByIDsOk(sd apitest.SeedData) []apitest.Table {
+	unknownID := uuid.New()
+
+	table := []apitest.Table{
+		{
+			Name:  "owned",
+			Token: sd.Users[0].Token,
+			ExpResp: query.Batch[productapp.Product]{
+				Items:   []productapp.Product{toAppProduct(sd.Users[0].Products[0])},
+				Missing: []string{sd.Admins[0].Products[0].ID.String(), unknownID.String()},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				app := query.BatchGet{
+					IDs: []string{
+						sd.Users[0].Products[0].ID.String(),
+						sd.Admins[0].Products[0].ID.String(),
+						unknownID.String(),
+					},
+				}
+
+				resp, err := sales.ProductQueryByIDs(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:  "admin",
+			Token: sd.Admins[0].Token,
+			ExpResp: query.Batch[productapp.Product]{
+				Items: []productapp.Product{
+					toAppProduct(sd.Users[0].Products[0]),
+					toAppProduct(sd.Admins[0].Products[0]),
+				},
+				Missing: []string{},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				app := query.BatchGet{
+					IDs: []string{
+						sd.Users[0].Products[0].ID.String(),
+						sd.Admins[0].Products[0].ID.String(),
+					},
+				}
+
+				resp, err := sales.ProductQueryByIDs(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -23,6 +23,7 @@ func updateOk(sd apitest.SeedData) []apitest.Table {
 				Name:        "Guitar",
 				Cost:        10.34,
 				Quantity:    10,
+				Active:      true,
 				DateCreated: sd.Users[0].Products[0].DateCreated.Format(time.RFC3339),
 				DateUpdated: sd.Users[0].Products[0].DateCreated.Format(time.RFC3339),
 			},
@@ -12,6 +12,7 @@ import (
 	"github.com/ardanlabs/encore/app/sdk/auth"
 	"github.com/ardanlabs/encore/app/sdk/mid"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/notify"
 )
 
 func startTest(t *testing.T) *apitest.Test {
@@ -41,7 +42,7 @@ func startTest(t *testing.T) *apitest.Test {
 	}
 	et.MockService("auth", authService)
 
-	salesService, err := salesrv.NewService(db.Log, db.DB)
+	salesService, err := salesrv.NewService(db.Log, db.DB, notify.NewLog(db.Log), db.Geocoder)
 	if err != nil {
 		t.Fatalf("Sales service init error: %s", err)
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
 	"github.com/ardanlabs/encore/app/domain/userapp"
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/patch"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
 	"github.com/google/go-cmp/cmp"
 )
@@ -186,3 +187,66 @@ func updateAuth(sd apitest.SeedData) []apitest.Table {
 
 	return table
 }
+
+func patchOk(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:  "nulldepartment",
+			Token: sd.Users[0].Token,
+			ExpResp: userapp.User{
+				ID:          sd.Users[0].ID.String(),
+				Name:        "Jack Kennedy",
+				Email:       "jack@ardanlabs.com",
+				Roles:       []string{"USER"},
+				Department:  "",
+				Enabled:     true,
+				DateCreated: sd.Users[0].DateCreated.Format(time.RFC3339),
+				DateUpdated: sd.Users[0].DateCreated.Format(time.RFC3339),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				app := userapp.PatchUser{
+					Department: patch.Field[string]{Set: true, Null: true},
+				}
+
+				resp, err := sales.UserPatch(ctx, sd.Users[0].ID.String(), app)
+				if err != nil {
+					return err
+				}
+
+				resp.DateUpdated = resp.DateCreated
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func patchBad(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "nullname",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"name\",\"error\":\"cannot be null\"}]"),
+			ExcFunc: func(ctx context.Context) any {
+				app := userapp.PatchUser{
+					Name: patch.Field[string]{Set: true, Null: true},
+				}
+
+				resp, err := sales.UserPatch(ctx, sd.Users[0].ID.String(), app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
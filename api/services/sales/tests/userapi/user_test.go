@@ -29,6 +29,9 @@ func Test_User(t *testing.T) {
 	test.Run(t, updateAuth(sd), "update-auth")
 	test.Run(t, updateBad(sd), "update-bad")
 
+	test.Run(t, patchOk(sd), "patch-ok")
+	test.Run(t, patchBad(sd), "patch-bad")
+
 	test.Run(t, deleteOk(sd), "delete-ok")
 	test.Run(t, deleteAuth(sd), "delete-auth")
 }
@@ -0,0 +1,159 @@
+// Package categoryapp maintains the app layer api for the category domain.
+package categoryapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
+)
+
+// App manages the set of app layer api functions for the category domain.
+type App struct {
+	categoryBus *categorybus.Business
+}
+
+// NewApp constructs a category app API for use.
+func NewApp(categoryBus *categorybus.Business) *App {
+	return &App{
+		categoryBus: categoryBus,
+	}
+}
+
+// Create adds a new category to the system.
+func (a *App) Create(ctx context.Context, app NewCategory) (Category, error) {
+	nc, err := toBusNewCategory(app)
+	if err != nil {
+		return Category{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	cat, err := a.categoryBus.Create(ctx, nc)
+	if err != nil {
+		switch {
+		case errors.Is(err, categorybus.ErrUniqueName):
+			return Category{}, errs.New(errs.Aborted, categorybus.ErrUniqueName)
+		case errors.Is(err, categorybus.ErrParentNotFound):
+			return Category{}, errs.New(errs.InvalidArgument, categorybus.ErrParentNotFound)
+		}
+		return Category{}, errs.Newf(errs.Internal, "create: cat[%+v]: %s", app, err)
+	}
+
+	return toAppCategory(cat), nil
+}
+
+// Update updates an existing category.
+func (a *App) Update(ctx context.Context, categoryID string, app UpdateCategory) (Category, error) {
+	uc, err := toBusUpdateCategory(app)
+	if err != nil {
+		return Category{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	cat, err := a.queryByID(ctx, categoryID)
+	if err != nil {
+		return Category{}, err
+	}
+
+	updCat, err := a.categoryBus.Update(ctx, cat, uc)
+	if err != nil {
+		switch {
+		case errors.Is(err, categorybus.ErrUniqueName):
+			return Category{}, errs.New(errs.Aborted, categorybus.ErrUniqueName)
+		case errors.Is(err, categorybus.ErrParentNotFound):
+			return Category{}, errs.New(errs.InvalidArgument, categorybus.ErrParentNotFound)
+		case errors.Is(err, categorybus.ErrCycle):
+			return Category{}, errs.New(errs.FailedPrecondition, categorybus.ErrCycle)
+		}
+		return Category{}, errs.Newf(errs.Internal, "update: categoryID[%s] uc[%+v]: %s", cat.ID, uc, err)
+	}
+
+	return toAppCategory(updCat), nil
+}
+
+// Delete removes a category from the system.
+func (a *App) Delete(ctx context.Context, categoryID string) error {
+	cat, err := a.queryByID(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	if err := a.categoryBus.Delete(ctx, cat); err != nil {
+		return errs.Newf(errs.Internal, "delete: categoryID[%s]: %s", cat.ID, err)
+	}
+
+	return nil
+}
+
+// Query returns a list of categories with paging.
+func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Category], error) {
+	page, err := page.ParseQuery(qp.Page, qp.Rows, qp.Cursor)
+	if err != nil {
+		return query.Result[Category]{}, err
+	}
+
+	total, err := query.ParseTotal(qp.Total)
+	if err != nil {
+		return query.Result[Category]{}, errs.NewFieldsError("total", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return query.Result[Category]{}, err
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	if err != nil {
+		return query.Result[Category]{}, err
+	}
+
+	if err := page.ValidateOrder(orderBy); err != nil {
+		return query.Result[Category]{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	cats, err := a.categoryBus.Query(ctx, filter, orderBy, total.Page(page))
+	if err != nil {
+		return query.Result[Category]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	cats, hasMore := query.Trim(page, cats)
+
+	count, err := query.Count(ctx, total, filter, a.categoryBus.Count, a.categoryBus.EstimateCount)
+	if err != nil {
+		return query.Result[Category]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppCategories(cats), count, page).WithTotal(total, hasMore).WithCursors(cursors(page, orderBy, cats)), nil
+}
+
+// QueryByID returns a category by its ID.
+func (a *App) QueryByID(ctx context.Context, categoryID string) (Category, error) {
+	cat, err := a.queryByID(ctx, categoryID)
+	if err != nil {
+		return Category{}, err
+	}
+
+	return toAppCategory(cat), nil
+}
+
+// =============================================================================
+
+func (a *App) queryByID(ctx context.Context, categoryID string) (categorybus.Category, error) {
+	id, err := uuid.Parse(categoryID)
+	if err != nil {
+		return categorybus.Category{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	cat, err := a.categoryBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, categorybus.ErrNotFound) {
+			return categorybus.Category{}, errs.New(errs.NotFound, err)
+		}
+		return categorybus.Category{}, errs.Newf(errs.Internal, "querybyid: categoryID[%s]: %s", id, err)
+	}
+
+	return cat, nil
+}
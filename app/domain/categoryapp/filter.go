@@ -0,0 +1,33 @@
+package categoryapp
+
+import (
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/google/uuid"
+)
+
+func parseFilter(qp QueryParams) (categorybus.QueryFilter, error) {
+	var filter categorybus.QueryFilter
+
+	if qp.ID != "" {
+		id, err := uuid.Parse(qp.ID)
+		if err != nil {
+			return categorybus.QueryFilter{}, errs.NewFieldsError("category_id", err)
+		}
+		filter.ID = &id
+	}
+
+	if qp.ParentID != "" {
+		id, err := uuid.Parse(qp.ParentID)
+		if err != nil {
+			return categorybus.QueryFilter{}, errs.NewFieldsError("parent_id", err)
+		}
+		filter.ParentID = &id
+	}
+
+	if qp.Name != "" {
+		filter.Name = &qp.Name
+	}
+
+	return filter, nil
+}
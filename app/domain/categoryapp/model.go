@@ -0,0 +1,157 @@
+package categoryapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/google/uuid"
+)
+
+// QueryParams represents the set of possible query strings.
+type QueryParams struct {
+	Page     string
+	Rows     string
+	Cursor   string
+	Total    string
+	OrderBy  string
+	ID       string
+	ParentID string
+	Name     string
+}
+
+// =============================================================================
+
+// Category represents information about an individual category. A top level
+// category has an empty parent id.
+type Category struct {
+	ID          string `json:"id"`
+	ParentID    string `json:"parentID"`
+	Name        string `json:"name"`
+	DateCreated string `json:"dateCreated"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+// Encode implments the encoder interface.
+func (app Category) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppCategory(cat categorybus.Category) Category {
+	var parentID string
+	if cat.ParentID != uuid.Nil {
+		parentID = cat.ParentID.String()
+	}
+
+	return Category{
+		ID:          cat.ID.String(),
+		ParentID:    parentID,
+		Name:        cat.Name,
+		DateCreated: cat.DateCreated.Format(time.RFC3339),
+		DateUpdated: cat.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppCategories(cats []categorybus.Category) []Category {
+	app := make([]Category, len(cats))
+	for i, cat := range cats {
+		app[i] = toAppCategory(cat)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewCategory defines the data needed to add a new category.
+type NewCategory struct {
+	ParentID string `json:"parentID" validate:"omitempty,uuid"`
+	Name     string `json:"name" validate:"required,max=100"`
+}
+
+// Decode implments the decoder interface.
+func (app *NewCategory) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewCategory) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewCategory(app NewCategory) (categorybus.NewCategory, error) {
+	parentID, err := parseParentID(app.ParentID)
+	if err != nil {
+		return categorybus.NewCategory{}, err
+	}
+
+	bus := categorybus.NewCategory{
+		ParentID: parentID,
+		Name:     app.Name,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// UpdateCategory defines the data needed to update a category. An empty
+// parent id moves the category to the top of the hierarchy.
+type UpdateCategory struct {
+	ParentID *string `json:"parentID" validate:"omitempty"`
+	Name     *string `json:"name" validate:"omitempty,min=1,max=100"`
+}
+
+// Decode implments the decoder interface.
+func (app *UpdateCategory) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateCategory) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateCategory(app UpdateCategory) (categorybus.UpdateCategory, error) {
+	var parentID *uuid.UUID
+	if app.ParentID != nil {
+		id, err := parseParentID(*app.ParentID)
+		if err != nil {
+			return categorybus.UpdateCategory{}, err
+		}
+		parentID = &id
+	}
+
+	bus := categorybus.UpdateCategory{
+		ParentID: parentID,
+		Name:     app.Name,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+func parseParentID(parentID string) (uuid.UUID, error) {
+	if parentID == "" {
+		return uuid.Nil, nil
+	}
+
+	id, err := uuid.Parse(parentID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("parse parentID: %w", err)
+	}
+
+	return id, nil
+}
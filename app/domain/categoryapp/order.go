@@ -0,0 +1,45 @@
+package categoryapp
+
+import (
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+)
+
+var defaultOrderBy = order.NewBy("name", order.ASC)
+
+var orderByFields = map[string]string{
+	"category_id": categorybus.OrderByID,
+	"name":        categorybus.OrderByName,
+}
+
+// cursors returns the cursors of the pages around the categories that were
+// fetched for the page.
+func cursors(pg page.Page, orderBy order.By, cats []categorybus.Category) (string, string) {
+	keys := orderBy.Keys()
+
+	return pg.Cursors(orderBy, len(cats), func(i int) page.Key {
+		values := make([]string, len(keys))
+		for j, key := range keys {
+			values[j] = cursorValue(cats[i], key.Field)
+		}
+
+		return page.Key{
+			Values: values,
+			ID:     cats[i].ID.String(),
+		}
+	})
+}
+
+// cursorValue returns the value of the field for the category in the text form
+// the database parses back into the type of the column.
+func cursorValue(cat categorybus.Category, field string) string {
+	switch field {
+	case categorybus.OrderByID:
+		return cat.ID.String()
+	case categorybus.OrderByName:
+		return cat.Name
+	}
+
+	return ""
+}
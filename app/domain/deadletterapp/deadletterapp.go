@@ -0,0 +1,103 @@
+// Package deadletterapp maintains the app layer api for the dead letter
+// domain.
+package deadletterapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/business/domain/deadletterbus"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
+)
+
+// App manages the set of app layer api functions for the dead letter domain.
+type App struct {
+	deadLetterBus *deadletterbus.Business
+	delegate      *delegate.Delegate
+}
+
+// NewApp constructs a dead letter app API for use.
+func NewApp(deadLetterBus *deadletterbus.Business, delegate *delegate.Delegate) *App {
+	return &App{
+		deadLetterBus: deadLetterBus,
+		delegate:      delegate,
+	}
+}
+
+// Query returns a list of dead letters, newest first, with paging.
+func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[DeadLetter], error) {
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return query.Result[DeadLetter]{}, err
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return query.Result[DeadLetter]{}, err
+	}
+
+	dls, err := a.deadLetterBus.Query(ctx, filter, page)
+	if err != nil {
+		return query.Result[DeadLetter]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.deadLetterBus.Count(ctx, filter)
+	if err != nil {
+		return query.Result[DeadLetter]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppDeadLetters(dls), total, page), nil
+}
+
+// QueryByID returns a dead letter by its ID.
+func (a *App) QueryByID(ctx context.Context, deadLetterID string) (DeadLetter, error) {
+	dl, err := a.queryByID(ctx, deadLetterID)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+
+	return toAppDeadLetter(dl), nil
+}
+
+// Replay calls the function the event of the dead letter was for once more.
+// A replay that fails is reported through the error of the dead letter
+// returned, so it can be tried again.
+func (a *App) Replay(ctx context.Context, deadLetterID string) (DeadLetter, error) {
+	dl, err := a.queryByID(ctx, deadLetterID)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+
+	rpl, err := a.deadLetterBus.Replay(ctx, dl, a.delegate.Replay)
+	if err != nil {
+		if errors.Is(err, deadletterbus.ErrReplayed) {
+			return DeadLetter{}, errs.New(errs.FailedPrecondition, err)
+		}
+		return DeadLetter{}, errs.Newf(errs.Internal, "replay: deadLetterID[%s]: %s", dl.ID, err)
+	}
+
+	return toAppDeadLetter(rpl), nil
+}
+
+// =============================================================================
+
+func (a *App) queryByID(ctx context.Context, deadLetterID string) (deadletterbus.DeadLetter, error) {
+	id, err := uuid.Parse(deadLetterID)
+	if err != nil {
+		return deadletterbus.DeadLetter{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	dl, err := a.deadLetterBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, deadletterbus.ErrNotFound) {
+			return deadletterbus.DeadLetter{}, errs.New(errs.NotFound, err)
+		}
+		return deadletterbus.DeadLetter{}, errs.Newf(errs.Internal, "querybyid: deadLetterID[%s]: %s", id, err)
+	}
+
+	return dl, nil
+}
@@ -0,0 +1,34 @@
+package deadletterapp
+
+import (
+	"strconv"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/deadletterbus"
+)
+
+func parseFilter(qp QueryParams) (deadletterbus.QueryFilter, error) {
+	var filter deadletterbus.QueryFilter
+
+	if qp.Domain != "" {
+		filter.Domain = &qp.Domain
+	}
+
+	if qp.Action != "" {
+		filter.Action = &qp.Action
+	}
+
+	if qp.Target != "" {
+		filter.Target = &qp.Target
+	}
+
+	if qp.Replayed != "" {
+		replayed, err := strconv.ParseBool(qp.Replayed)
+		if err != nil {
+			return deadletterbus.QueryFilter{}, errs.NewFieldsError("replayed", err)
+		}
+		filter.Replayed = &replayed
+	}
+
+	return filter, nil
+}
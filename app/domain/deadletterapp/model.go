@@ -0,0 +1,71 @@
+package deadletterapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/deadletterbus"
+)
+
+// QueryParams represents the set of possible query strings.
+type QueryParams struct {
+	Page     string
+	Rows     string
+	Domain   string
+	Action   string
+	Target   string
+	Replayed string
+}
+
+// =============================================================================
+
+// DeadLetter represents an event a delegate function failed to handle.
+type DeadLetter struct {
+	ID           string          `json:"id"`
+	EventID      string          `json:"eventID"`
+	Domain       string          `json:"domain"`
+	Action       string          `json:"action"`
+	Params       json.RawMessage `json:"params"`
+	Target       string          `json:"target"`
+	Attempts     int             `json:"attempts"`
+	Error        string          `json:"error,omitempty"`
+	DateCreated  string          `json:"dateCreated"`
+	DateUpdated  string          `json:"dateUpdated"`
+	DateReplayed string          `json:"dateReplayed,omitempty"`
+}
+
+// Encode implments the encoder interface.
+func (app DeadLetter) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppDeadLetter(dl deadletterbus.DeadLetter) DeadLetter {
+	var dateReplayed string
+	if !dl.DateReplayed.IsZero() {
+		dateReplayed = dl.DateReplayed.Format(time.RFC3339)
+	}
+
+	return DeadLetter{
+		ID:           dl.ID.String(),
+		EventID:      dl.EventID.String(),
+		Domain:       dl.Domain,
+		Action:       dl.Action,
+		Params:       dl.RawParams,
+		Target:       dl.Target,
+		Attempts:     dl.Attempts,
+		Error:        dl.Error,
+		DateCreated:  dl.DateCreated.Format(time.RFC3339),
+		DateUpdated:  dl.DateUpdated.Format(time.RFC3339),
+		DateReplayed: dateReplayed,
+	}
+}
+
+func toAppDeadLetters(dls []deadletterbus.DeadLetter) []DeadLetter {
+	app := make([]DeadLetter, len(dls))
+	for i, dl := range dls {
+		app[i] = toAppDeadLetter(dl)
+	}
+
+	return app
+}
@@ -0,0 +1,139 @@
+// Package eventapp maintains the app layer api for the event domain.
+package eventapp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/app/sdk/sse"
+	"github.com/ardanlabs/encore/business/domain/eventbus"
+	"github.com/google/uuid"
+)
+
+// Set of values that pace the stream of events.
+const (
+	batchRows         = 100
+	pollInterval      = 2 * time.Second
+	heartbeatInterval = 15 * time.Second
+	retryInterval     = 3 * time.Second
+)
+
+// App manages the set of app layer api functions for the event domain.
+type App struct {
+	eventBus *eventbus.Business
+}
+
+// NewApp constructs an event app API for use.
+func NewApp(eventBus *eventbus.Business) *App {
+	return &App{
+		eventBus: eventBus,
+	}
+}
+
+// Stream writes the events the caller is authorized to see as they are
+// recorded, until the context is cancelled. Admins see every event while
+// other users only see the events about what they own. The events after
+// lastEventID are sent first, so a client that reconnects picks up where it
+// left off as long as those events are still in the log. Without a
+// lastEventID the stream starts with the next event recorded.
+func (a *App) Stream(ctx context.Context, lastEventID string, sw *sse.Writer) error {
+	claims, err := mid.GetClaims(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "stream: %s", err)
+	}
+
+	var filter eventbus.QueryFilter
+	if !claims.IsAdmin() {
+		userID, err := uuid.Parse(claims.Subject)
+		if err != nil {
+			return errs.Newf(errs.Internal, "stream: parse subject: %s", err)
+		}
+		filter.UserID = &userID
+	}
+
+	afterID, err := a.afterID(ctx, lastEventID)
+	if err != nil {
+		return err
+	}
+
+	if err := sw.Retry(retryInterval); err != nil {
+		return fmt.Errorf("retry: %w", err)
+	}
+
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		// Take the channel before reading the log so an event recorded
+		// while the log is read isn't missed.
+		changed := a.eventBus.Changed()
+
+		for {
+			evts, err := a.eventBus.Query(ctx, filter, afterID, batchRows)
+			if err != nil {
+				return errs.Newf(errs.Internal, "query: afterID[%d]: %s", afterID, err)
+			}
+
+			for _, evt := range evts {
+				app := toAppEvent(evt)
+
+				data, _, err := app.Encode()
+				if err != nil {
+					return errs.Newf(errs.Internal, "encode: eventID[%d]: %s", evt.ID, err)
+				}
+
+				if err := sw.Event(app.ID, eventbus.Type(evt.Domain, evt.Action), data); err != nil {
+					return fmt.Errorf("event: %w", err)
+				}
+
+				afterID = evt.ID
+			}
+
+			if len(evts) < batchRows {
+				break
+			}
+		}
+
+		// Events recorded by other instances of the service are only found
+		// by polling the log.
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-changed:
+		case <-poll.C:
+
+		case <-heartbeat.C:
+			if err := sw.Comment("heartbeat"); err != nil {
+				return fmt.Errorf("comment: %w", err)
+			}
+		}
+	}
+}
+
+// afterID returns the id of the last event the client has seen. A client
+// without one starts after the last event recorded.
+func (a *App) afterID(ctx context.Context, lastEventID string) (int64, error) {
+	if lastEventID == "" {
+		id, err := a.eventBus.QueryLastID(ctx)
+		if err != nil {
+			return 0, errs.Newf(errs.Internal, "querylastid: %s", err)
+		}
+
+		return id, nil
+	}
+
+	id, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errs.Newf(errs.InvalidArgument, "invalid last event id %q", lastEventID)
+	}
+
+	return id, nil
+}
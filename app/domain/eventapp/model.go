@@ -0,0 +1,38 @@
+package eventapp
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/eventbus"
+)
+
+// Event represents a change made to a product, home or user.
+type Event struct {
+	ID          string          `json:"id"`
+	Domain      string          `json:"domain"`
+	Action      string          `json:"action"`
+	EntityID    string          `json:"entityID"`
+	UserID      string          `json:"userID"`
+	Data        json.RawMessage `json:"data"`
+	DateCreated string          `json:"dateCreated"`
+}
+
+// Encode implements the encoder interface.
+func (app Event) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppEvent(evt eventbus.Event) Event {
+	return Event{
+		ID:          strconv.FormatInt(evt.ID, 10),
+		Domain:      evt.Domain,
+		Action:      evt.Action,
+		EntityID:    evt.EntityID.String(),
+		UserID:      evt.UserID.String(),
+		Data:        evt.Data,
+		DateCreated: evt.DateCreated.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,203 @@
+package homeapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/bulk"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/google/uuid"
+)
+
+// newWithTx constructs a new App value with the domain apis using a store
+// transaction that was created via middleware, so the events for the changes
+// made are only sent if they are committed.
+func (a *App) newWithTx(ctx context.Context) (*App, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	homeBus, err := a.homeBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := a.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := App{
+		homeBus: homeBus,
+		userBus: userBus,
+	}
+
+	return &app, nil
+}
+
+// CreateMany adds the homes to the system in a single transaction and
+// reports the result of each of them.
+func (a *App) CreateMany(ctx context.Context, app NewHomes) (bulk.Result, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return bulk.Result{}, errs.New(errs.Internal, err)
+	}
+
+	mode, err := bulk.ParseMode(app.Mode)
+	if err != nil {
+		return bulk.Result{}, errs.NewFieldsError("mode", err)
+	}
+
+	res := bulk.NewResult(len(app.Items))
+
+	var batch bulk.Batch[homebus.NewHome]
+	for i, item := range app.Items {
+		if err := errs.Check(item); err != nil {
+			res.Fail(i, err)
+			continue
+		}
+
+		nh, err := toBusNewHome(ctx, item)
+		if err != nil {
+			res.Fail(i, errs.NewFieldsError("type", err))
+			continue
+		}
+
+		batch.Add(i, nh)
+	}
+
+	for {
+		if mode == bulk.Atomic && res.Failed > 0 {
+			return bulk.Result{}, res.Err()
+		}
+
+		if len(batch.Items) == 0 {
+			return *res, nil
+		}
+
+		hmes, err := a.homeBus.CreateMany(ctx, batch.Items)
+		if err != nil {
+			if batch.Drop(res, err, toItemError) {
+				continue
+			}
+			return bulk.Result{}, errs.Newf(errs.Internal, "createmany: %s", err)
+		}
+
+		for i, hme := range hmes {
+			res.Succeed(batch.Indexes[i], hme.ID.String())
+		}
+
+		return *res, nil
+	}
+}
+
+// UpdateMany updates the homes in a single transaction and reports the
+// result of each of them. Callers that aren't admins can only update the
+// homes they own.
+func (a *App) UpdateMany(ctx context.Context, app UpdateHomes) (bulk.Result, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return bulk.Result{}, errs.New(errs.Internal, err)
+	}
+
+	mode, err := bulk.ParseMode(app.Mode)
+	if err != nil {
+		return bulk.Result{}, errs.NewFieldsError("mode", err)
+	}
+
+	claims, err := mid.GetClaims(ctx)
+	if err != nil {
+		return bulk.Result{}, errs.Newf(errs.Internal, "updatemany: %s", err)
+	}
+
+	res := bulk.NewResult(len(app.Items))
+
+	var batch bulk.Batch[homebus.UpdateHome]
+	var ids []uuid.UUID
+	for i, item := range app.Items {
+		id, err := uuid.Parse(item.ID)
+		if err != nil {
+			res.Fail(i, errs.NewFieldsError("id", err))
+			continue
+		}
+
+		if err := errs.Check(item.UpdateHome); err != nil {
+			res.Fail(i, err)
+			continue
+		}
+
+		uh, err := toBusUpdateHome(item.UpdateHome)
+		if err != nil {
+			res.Fail(i, errs.NewFieldsError("type", err))
+			continue
+		}
+
+		batch.Add(i, uh)
+		ids = append(ids, id)
+	}
+
+	found, err := a.homeBus.QueryByIDs(ctx, ids)
+	if err != nil {
+		return bulk.Result{}, errs.Newf(errs.Internal, "querybyids: %s", err)
+	}
+
+	hmes := make(map[uuid.UUID]homebus.Home, len(found))
+	for _, hme := range found {
+		if claims.IsAdmin() || hme.UserID.String() == claims.Subject {
+			hmes[hme.ID] = hme
+		}
+	}
+
+	for i, uh := range batch.Items {
+		index := batch.Indexes[i]
+
+		hme, exists := hmes[ids[i]]
+		if !exists {
+			res.Fail(index, errs.NewFieldsError("id", homebus.ErrNotFound))
+			continue
+		}
+
+		if mode == bulk.Atomic && res.Failed > 0 {
+			continue
+		}
+
+		// The business checks of an update happen before anything is
+		// written, so a failed item leaves the transaction usable.
+		updHme, err := a.homeBus.Update(ctx, hme, uh)
+		if err != nil {
+			itemErr := toItemError(err)
+			if !errs.IsFieldErrors(itemErr) {
+				return bulk.Result{}, errs.Newf(errs.Internal, "update: homeID[%s] uh[%+v]: %s", hme.ID, uh, err)
+			}
+			res.Fail(index, itemErr)
+			continue
+		}
+
+		res.Succeed(index, updHme.ID.String())
+	}
+
+	if mode == bulk.Atomic && res.Failed > 0 {
+		return bulk.Result{}, res.Err()
+	}
+
+	return *res, nil
+}
+
+// toItemError reports the address and attribute errors of an item against
+// the fields they were caused by.
+func toItemError(err error) error {
+	var ae *homebus.AddressError
+	if errors.As(err, &ae) {
+		return toFieldErrors(ae.Fields, addressFields)
+	}
+
+	var te *homebus.AttributeError
+	if errors.As(err, &te) {
+		return toFieldErrors(te.Fields, attributeFields)
+	}
+
+	return err
+}
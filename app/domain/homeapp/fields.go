@@ -0,0 +1,57 @@
+package homeapp
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
+)
+
+// fieldMappings maps the json names of a home to the fields a query can
+// be limited to.
+var fieldMappings = map[string]string{
+	"id":          homebus.FieldID,
+	"userID":      homebus.FieldUserID,
+	"type":        homebus.FieldType,
+	"address":     homebus.FieldAddress,
+	"attributes":  homebus.FieldAttributes,
+	"location":    homebus.FieldLocation,
+	"dateCreated": homebus.FieldDateCreated,
+	"dateUpdated": homebus.FieldDateUpdated,
+}
+
+// parseFields parses the fields and expansions a client asked for. It
+// returns the fields to read, the json names to return and whether the
+// owning users are expanded. A nil list of names returns every field.
+func parseFields(qp QueryParams) (fieldset.Set, []string, bool, error) {
+	fields, err := fieldset.Parse(fieldMappings, qp.Fields)
+	if err != nil {
+		return fieldset.Set{}, nil, false, errs.NewFieldsError("fields", err)
+	}
+
+	var names []string
+	if !fields.IsAll() {
+		// The id is always returned.
+		names = []string{"id"}
+		for name := range strings.SplitSeq(qp.Fields, ",") {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+
+	switch qp.Expand {
+	case "":
+		return fields, names, false, nil
+
+	case "user":
+		if names != nil {
+			names = append(names, "user")
+		}
+
+		return fields.With(homebus.FieldUserID), names, true, nil
+
+	default:
+		return fieldset.Set{}, nil, false, errs.NewFieldsError("expand", errors.New("only user can be expanded"))
+	}
+}
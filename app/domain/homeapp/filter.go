@@ -1,23 +1,26 @@
 package homeapp
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/sdk/filter"
 	"github.com/google/uuid"
 )
 
 func parseFilter(qp QueryParams) (homebus.QueryFilter, error) {
 	var filter homebus.QueryFilter
 
-	if qp.ID != "" {
-		id, err := uuid.Parse(qp.ID)
-		if err != nil {
-			return homebus.QueryFilter{}, errs.NewFieldsError("home_id", err)
-		}
-		filter.ID = &id
+	ids, err := idField.Conditions(idParams(qp))
+	if err != nil {
+		return homebus.QueryFilter{}, errs.NewFieldsError(idField.Name, err)
 	}
+	filter.ID = ids
 
 	if qp.UserID != "" {
 		id, err := uuid.Parse(qp.UserID)
@@ -51,5 +54,193 @@ func parseFilter(qp QueryParams) (homebus.QueryFilter, error) {
 		filter.EndCreatedDate = &t
 	}
 
+	if filter.Bedrooms, err = bedroomsField.Conditions(bedroomsParams(qp)); err != nil {
+		return homebus.QueryFilter{}, errs.NewFieldsError(bedroomsField.Name, err)
+	}
+
+	if filter.Bathrooms, err = bathroomsField.Conditions(bathroomsParams(qp)); err != nil {
+		return homebus.QueryFilter{}, errs.NewFieldsError(bathroomsField.Name, err)
+	}
+
+	if filter.SquareFeet, err = squareFeetField.Conditions(squareFeetParams(qp)); err != nil {
+		return homebus.QueryFilter{}, errs.NewFieldsError(squareFeetField.Name, err)
+	}
+
+	if filter.YearBuilt, err = yearBuiltField.Conditions(yearBuiltParams(qp)); err != nil {
+		return homebus.QueryFilter{}, errs.NewFieldsError(yearBuiltField.Name, err)
+	}
+
+	if filter.Units, err = unitsField.Conditions(unitsParams(qp)); err != nil {
+		return homebus.QueryFilter{}, errs.NewFieldsError(unitsField.Name, err)
+	}
+
+	switch {
+	case qp.Near != "":
+		area, err := parseNear(qp.Near, qp.RadiusKM)
+		if err != nil {
+			return homebus.QueryFilter{}, err
+		}
+		filter.Near = &area
+
+	case qp.RadiusKM != "":
+		return homebus.QueryFilter{}, errs.NewFieldsError("radius_km", errors.New("requires near"))
+	}
+
 	return filter, nil
 }
+
+// Proximity searches default to a radius of defaultRadiusKM and can't cover
+// more than maxRadiusKM.
+const (
+	defaultRadiusKM = 10
+	maxRadiusKM     = 500
+)
+
+// parseNear parses a "lat,lng" center and an optional radius in kilometers.
+func parseNear(near string, radiusKM string) (homebus.Area, error) {
+	latStr, lngStr, found := strings.Cut(near, ",")
+	if !found {
+		return homebus.Area{}, errs.NewFieldsError("near", errors.New("must be formatted as lat,lng"))
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return homebus.Area{}, errs.NewFieldsError("near", errors.New("latitude must be between -90 and 90"))
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return homebus.Area{}, errs.NewFieldsError("near", errors.New("longitude must be between -180 and 180"))
+	}
+
+	radius := float64(defaultRadiusKM)
+	if radiusKM != "" {
+		radius, err = strconv.ParseFloat(radiusKM, 64)
+		if err != nil || radius <= 0 || radius > maxRadiusKM {
+			return homebus.Area{}, errs.NewFieldsError("radius_km", fmt.Errorf("must be greater than 0 and at most %d", maxRadiusKM))
+		}
+	}
+
+	area := homebus.Area{
+		Center: homebus.Location{
+			Latitude:  lat,
+			Longitude: lng,
+		},
+		RadiusKM: radius,
+	}
+
+	return area, nil
+}
+
+// attributeOperators are the operators the attributes of a home can be
+// compared with.
+var attributeOperators = []filter.Operator{
+	filter.Operators.EQ,
+	filter.Operators.GT,
+	filter.Operators.GTE,
+	filter.Operators.LT,
+	filter.Operators.LTE,
+	filter.Operators.BETWEEN,
+}
+
+// The fields that can be compared with the filter operators.
+var (
+	idField = filter.Field[uuid.UUID]{
+		Name:      "id",
+		Operators: filter.Identity,
+		Parse:     uuid.Parse,
+	}
+
+	bedroomsField = filter.Field[int]{
+		Name:      "bedrooms",
+		Operators: attributeOperators,
+		Parse:     filter.Int,
+	}
+
+	bathroomsField = filter.Field[float64]{
+		Name:      "bathrooms",
+		Operators: attributeOperators,
+		Parse:     filter.Float,
+	}
+
+	squareFeetField = filter.Field[int]{
+		Name:      "square_feet",
+		Operators: attributeOperators,
+		Parse:     filter.Int,
+	}
+
+	yearBuiltField = filter.Field[int]{
+		Name:      "year_built",
+		Operators: attributeOperators,
+		Parse:     filter.Int,
+	}
+
+	unitsField = filter.Field[int]{
+		Name:      "units",
+		Operators: attributeOperators,
+		Parse:     filter.Int,
+	}
+)
+
+func idParams(qp QueryParams) filter.Params {
+	return filter.Params{
+		filter.Operators.EQ: qp.ID,
+		filter.Operators.NE: qp.IDNE,
+		filter.Operators.IN: qp.IDIn,
+	}
+}
+
+func bedroomsParams(qp QueryParams) filter.Params {
+	return filter.Params{
+		filter.Operators.EQ:      qp.Bedrooms,
+		filter.Operators.GT:      qp.BedroomsGT,
+		filter.Operators.GTE:     qp.BedroomsGTE,
+		filter.Operators.LT:      qp.BedroomsLT,
+		filter.Operators.LTE:     qp.BedroomsLTE,
+		filter.Operators.BETWEEN: qp.BedroomsBetween,
+	}
+}
+
+func bathroomsParams(qp QueryParams) filter.Params {
+	return filter.Params{
+		filter.Operators.EQ:      qp.Bathrooms,
+		filter.Operators.GT:      qp.BathroomsGT,
+		filter.Operators.GTE:     qp.BathroomsGTE,
+		filter.Operators.LT:      qp.BathroomsLT,
+		filter.Operators.LTE:     qp.BathroomsLTE,
+		filter.Operators.BETWEEN: qp.BathroomsBetween,
+	}
+}
+
+func squareFeetParams(qp QueryParams) filter.Params {
+	return filter.Params{
+		filter.Operators.EQ:      qp.SquareFeet,
+		filter.Operators.GT:      qp.SquareFeetGT,
+		filter.Operators.GTE:     qp.SquareFeetGTE,
+		filter.Operators.LT:      qp.SquareFeetLT,
+		filter.Operators.LTE:     qp.SquareFeetLTE,
+		filter.Operators.BETWEEN: qp.SquareFeetBetween,
+	}
+}
+
+func yearBuiltParams(qp QueryParams) filter.Params {
+	return filter.Params{
+		filter.Operators.EQ:      qp.YearBuilt,
+		filter.Operators.GT:      qp.YearBuiltGT,
+		filter.Operators.GTE:     qp.YearBuiltGTE,
+		filter.Operators.LT:      qp.YearBuiltLT,
+		filter.Operators.LTE:     qp.YearBuiltLTE,
+		filter.Operators.BETWEEN: qp.YearBuiltBetween,
+	}
+}
+
+func unitsParams(qp QueryParams) filter.Params {
+	return filter.Params{
+		filter.Operators.EQ:      qp.Units,
+		filter.Operators.GT:      qp.UnitsGT,
+		filter.Operators.GTE:     qp.UnitsGTE,
+		filter.Operators.LT:      qp.UnitsLT,
+		filter.Operators.LTE:     qp.UnitsLTE,
+		filter.Operators.BETWEEN: qp.UnitsBetween,
+	}
+}
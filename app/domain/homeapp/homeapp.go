@@ -3,29 +3,41 @@ package homeapp
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/app/sdk/mid"
 	"github.com/ardanlabs/encore/app/sdk/query"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
 )
 
 // App manages the set of app layer api functions for the home domain.
 type App struct {
 	homeBus *homebus.Business
+	userBus *userbus.Business
 }
 
 // NewApp constructs a home domain API for use.
-func NewApp(homeBus *homebus.Business) *App {
+func NewApp(homeBus *homebus.Business, userBus *userbus.Business) *App {
 	return &App{
 		homeBus: homeBus,
+		userBus: userBus,
 	}
 }
 
 // Create adds a new home to the system.
 func (a *App) Create(ctx context.Context, app NewHome) (Home, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Home{}, errs.New(errs.Internal, err)
+	}
+
 	nh, err := toBusNewHome(ctx, app)
 	if err != nil {
 		return Home{}, errs.New(errs.InvalidArgument, err)
@@ -33,6 +45,15 @@ func (a *App) Create(ctx context.Context, app NewHome) (Home, error) {
 
 	hme, err := a.homeBus.Create(ctx, nh)
 	if err != nil {
+		var ae *homebus.AddressError
+		if errors.As(err, &ae) {
+			return Home{}, errs.Newf(errs.InvalidArgument, "validate: %s", toFieldErrors(ae.Fields, addressFields))
+		}
+
+		var te *homebus.AttributeError
+		if errors.As(err, &te) {
+			return Home{}, errs.Newf(errs.InvalidArgument, "validate: %s", toFieldErrors(te.Fields, attributeFields))
+		}
 		return Home{}, errs.Newf(errs.Internal, "create: hme[%+v]: %s", app, err)
 	}
 
@@ -41,6 +62,11 @@ func (a *App) Create(ctx context.Context, app NewHome) (Home, error) {
 
 // Update updates an existing home.
 func (a *App) Update(ctx context.Context, app UpdateHome) (Home, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Home{}, errs.New(errs.Internal, err)
+	}
+
 	uh, err := toBusUpdateHome(app)
 	if err != nil {
 		return Home{}, errs.New(errs.InvalidArgument, err)
@@ -53,14 +79,38 @@ func (a *App) Update(ctx context.Context, app UpdateHome) (Home, error) {
 
 	updUsr, err := a.homeBus.Update(ctx, hme, uh)
 	if err != nil {
+		var ae *homebus.AddressError
+		if errors.As(err, &ae) {
+			return Home{}, errs.Newf(errs.InvalidArgument, "validate: %s", toFieldErrors(ae.Fields, addressFields))
+		}
+
+		var te *homebus.AttributeError
+		if errors.As(err, &te) {
+			return Home{}, errs.Newf(errs.InvalidArgument, "validate: %s", toFieldErrors(te.Fields, attributeFields))
+		}
 		return Home{}, errs.Newf(errs.Internal, "update: homeID[%s] uh[%+v]: %s", hme.ID, uh, err)
 	}
 
 	return toAppHome(updUsr), nil
 }
 
+// Patch applies a merge patch document to a home.
+func (a *App) Patch(ctx context.Context, app PatchHome) (Home, error) {
+	uh, err := toUpdateHome(app)
+	if err != nil {
+		return Home{}, errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return a.Update(ctx, uh)
+}
+
 // Delete removes a home from the system.
 func (a *App) Delete(ctx context.Context) error {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
 	hme, err := mid.GetHome(ctx)
 	if err != nil {
 		return errs.Newf(errs.Internal, "homeID missing in context: %s", err)
@@ -75,32 +125,97 @@ func (a *App) Delete(ctx context.Context) error {
 
 // Query returns a list of homes with paging.
 func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Home], error) {
-	page, err := page.Parse(qp.Page, qp.Rows)
+	page, err := page.ParseQuery(qp.Page, qp.Rows, qp.Cursor)
 	if err != nil {
 		return query.Result[Home]{}, err
 	}
 
+	total, err := query.ParseTotal(qp.Total)
+	if err != nil {
+		return query.Result[Home]{}, errs.NewFieldsError("total", err)
+	}
+
 	filter, err := parseFilter(qp)
 	if err != nil {
 		return query.Result[Home]{}, err
 	}
 
-	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	defaultOrder := defaultOrderBy
+	if filter.Near != nil {
+		defaultOrder = nearOrderBy
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrder)
 	if err != nil {
 		return query.Result[Home]{}, err
 	}
 
-	hmes, err := a.homeBus.Query(ctx, filter, orderBy, page)
+	if err := page.ValidateOrder(orderBy); err != nil {
+		return query.Result[Home]{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	fields, names, expandUser, err := parseFields(qp)
 	if err != nil {
+		return query.Result[Home]{}, err
+	}
+
+	hmes, err := a.homeBus.Query(ctx, filter, fields, orderBy, total.Page(page))
+	if err != nil {
+		if errors.Is(err, homebus.ErrDistanceWithoutNear) {
+			return query.Result[Home]{}, errs.New(errs.InvalidArgument, err)
+		}
 		return query.Result[Home]{}, errs.Newf(errs.Internal, "query: %s", err)
 	}
 
-	total, err := a.homeBus.Count(ctx, filter)
+	hmes, hasMore := query.Trim(page, hmes)
+
+	count, err := query.Count(ctx, total, filter, a.homeBus.Count, a.homeBus.EstimateCount)
 	if err != nil {
 		return query.Result[Home]{}, errs.Newf(errs.Internal, "count: %s", err)
 	}
 
-	return query.NewResult(toAppHomes(hmes), total, page), nil
+	items := toAppHomes(hmes)
+
+	if expandUser {
+		if err := a.expandUsers(ctx, hmes, items); err != nil {
+			return query.Result[Home]{}, errs.Newf(errs.Internal, "expand: %s", err)
+		}
+	}
+
+	return query.NewResult(items, count, page).WithTotal(total, hasMore).WithCursors(cursors(page, orderBy, hmes)).WithFields(names), nil
+}
+
+// expandUsers sets the owner of each home, loading all of the owners with a
+// single query. The items are the app form of the homes.
+func (a *App) expandUsers(ctx context.Context, hmes []homebus.Home, items []Home) error {
+	var ids []uuid.UUID
+	for _, hme := range hmes {
+		if !slices.Contains(ids, hme.UserID) {
+			ids = append(ids, hme.UserID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	usrs, err := a.userBus.QueryByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("querybyids: %w", err)
+	}
+
+	owners := make(map[uuid.UUID]User, len(usrs))
+	for _, usr := range usrs {
+		owners[usr.ID] = toAppUser(usr)
+	}
+
+	for i, hme := range hmes {
+		if owner, exists := owners[hme.UserID]; exists {
+			items[i].User = &owner
+		}
+	}
+
+	return nil
 }
 
 // QueryByID returns a home by its Ia.
@@ -112,3 +227,168 @@ func (a *App) QueryByID(ctx context.Context) (Home, error) {
 
 	return toAppHome(hme), nil
 }
+
+// QueryByIDs returns the homes with the specified IDs. Callers that aren't
+// admins only get back the homes they own, the rest are reported as missing.
+func (a *App) QueryByIDs(ctx context.Context, app query.BatchGet) (query.Batch[Home], error) {
+	ids, err := app.ParseIDs()
+	if err != nil {
+		return query.Batch[Home]{}, errs.NewFieldsError("ids", err)
+	}
+
+	claims, err := mid.GetClaims(ctx)
+	if err != nil {
+		return query.Batch[Home]{}, errs.Newf(errs.Internal, "querybyids: %s", err)
+	}
+
+	hmes, err := a.homeBus.QueryByIDs(ctx, ids)
+	if err != nil {
+		return query.Batch[Home]{}, errs.Newf(errs.Internal, "querybyids: %s", err)
+	}
+
+	if !claims.IsAdmin() {
+		hmes = slices.DeleteFunc(hmes, func(hme homebus.Home) bool {
+			return hme.UserID.String() != claims.Subject
+		})
+	}
+
+	return query.NewBatch(ids, toAppHomes(hmes), func(hme Home) string { return hme.ID }), nil
+}
+
+// AddMember shares a home with another user.
+func (a *App) AddMember(ctx context.Context, app NewMember) (Member, error) {
+	nm, err := toBusNewMember(app)
+	if err != nil {
+		return Member{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	hme, err := mid.GetHome(ctx)
+	if err != nil {
+		return Member{}, errs.Newf(errs.Internal, "home missing in context: %s", err)
+	}
+
+	mem, err := a.homeBus.AddMember(ctx, hme, nm)
+	if err != nil {
+		switch {
+		case errors.Is(err, homebus.ErrMemberExists):
+			return Member{}, errs.New(errs.Aborted, err)
+		case errors.Is(err, homebus.ErrOwnerMember):
+			return Member{}, errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, homebus.ErrUserDisabled):
+			return Member{}, errs.New(errs.FailedPrecondition, err)
+		case errors.Is(err, userbus.ErrNotFound):
+			return Member{}, errs.New(errs.NotFound, err)
+		}
+		return Member{}, errs.Newf(errs.Internal, "addmember: homeID[%s] nm[%+v]: %s", hme.ID, app, err)
+	}
+
+	return toAppMember(mem), nil
+}
+
+// RemoveMember stops sharing a home with a user.
+func (a *App) RemoveMember(ctx context.Context, userID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	hme, err := mid.GetHome(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "home missing in context: %s", err)
+	}
+
+	if err := a.homeBus.RemoveMember(ctx, hme, id); err != nil {
+		switch {
+		case errors.Is(err, homebus.ErrMemberNotFound):
+			return errs.New(errs.NotFound, err)
+		case errors.Is(err, homebus.ErrOwnerMember):
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "removemember: homeID[%s] userID[%s]: %s", hme.ID, id, err)
+	}
+
+	return nil
+}
+
+// QueryMembers returns the owner of a home and the users it is shared with.
+func (a *App) QueryMembers(ctx context.Context) (Members, error) {
+	hme, err := mid.GetHome(ctx)
+	if err != nil {
+		return Members{}, errs.Newf(errs.Internal, "home missing in context: %s", err)
+	}
+
+	mems, err := a.homeBus.QueryMembers(ctx, hme)
+	if err != nil {
+		return Members{}, errs.Newf(errs.Internal, "querymembers: homeID[%s]: %s", hme.ID, err)
+	}
+
+	return toAppMembers(mems), nil
+}
+
+// RequestTransfer nominates a user to become the new owner of a home.
+func (a *App) RequestTransfer(ctx context.Context, app NewTransfer) (Transfer, error) {
+	toUserID, err := uuid.Parse(app.UserID)
+	if err != nil {
+		return Transfer{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	hme, err := mid.GetHome(ctx)
+	if err != nil {
+		return Transfer{}, errs.Newf(errs.Internal, "home missing in context: %s", err)
+	}
+
+	trn, err := a.homeBus.RequestTransfer(ctx, hme, toUserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, homebus.ErrTransferToOwner):
+			return Transfer{}, errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, homebus.ErrUserDisabled):
+			return Transfer{}, errs.New(errs.FailedPrecondition, err)
+		case errors.Is(err, userbus.ErrNotFound):
+			return Transfer{}, errs.New(errs.NotFound, err)
+		}
+		return Transfer{}, errs.Newf(errs.Internal, "requesttransfer: homeID[%s] userID[%s]: %s", hme.ID, toUserID, err)
+	}
+
+	return toAppTransfer(trn), nil
+}
+
+// AcceptTransfer makes the caller the owner of the home being transferred.
+func (a *App) AcceptTransfer(ctx context.Context, transferID string) (Home, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Home{}, errs.New(errs.Internal, err)
+	}
+
+	id, err := uuid.Parse(transferID)
+	if err != nil {
+		return Home{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return Home{}, errs.Newf(errs.Internal, "getuserid: %s", err)
+	}
+
+	trn, err := a.homeBus.QueryTransferByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, homebus.ErrTransferNotFound) {
+			return Home{}, errs.New(errs.NotFound, err)
+		}
+		return Home{}, errs.Newf(errs.Internal, "querytransferbyid: transferID[%s]: %s", id, err)
+	}
+
+	hme, err := a.homeBus.AcceptTransfer(ctx, trn, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, homebus.ErrTransferRecipient):
+			return Home{}, errs.New(errs.PermissionDenied, err)
+		case errors.Is(err, homebus.ErrTransferExpired),
+			errors.Is(err, homebus.ErrTransferNotPending):
+			return Home{}, errs.New(errs.FailedPrecondition, err)
+		}
+		return Home{}, errs.Newf(errs.Internal, "accepttransfer: transferID[%s]: %s", id, err)
+	}
+
+	return toAppHome(hme), nil
+}
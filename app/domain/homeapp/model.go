@@ -6,21 +6,64 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ardanlabs/encore/app/sdk/bulk"
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/app/sdk/patch"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/google/uuid"
 )
 
-// QueryParams represents the set of possible query strings.
+// QueryParams represents the set of possible query strings. Fields that
+// support the filter operators take them in brackets, such as bedrooms[gte].
 type QueryParams struct {
-	Page             string
-	Rows             string
-	OrderBy          string
-	ID               string
-	UserID           string
-	Type             string
-	StartCreatedDate string
-	EndCreatedDate   string
+	Page              string
+	Rows              string
+	Cursor            string
+	Total             string
+	OrderBy           string
+	ID                string
+	IDNE              string `query:"id[ne]"`
+	IDIn              string `query:"id[in]"`
+	UserID            string
+	Type              string
+	StartCreatedDate  string
+	EndCreatedDate    string
+	Near              string
+	RadiusKM          string
+	Bedrooms          string
+	BedroomsGT        string `query:"bedrooms[gt]"`
+	BedroomsGTE       string `query:"bedrooms[gte]"`
+	BedroomsLT        string `query:"bedrooms[lt]"`
+	BedroomsLTE       string `query:"bedrooms[lte]"`
+	BedroomsBetween   string `query:"bedrooms[between]"`
+	Bathrooms         string
+	BathroomsGT       string `query:"bathrooms[gt]"`
+	BathroomsGTE      string `query:"bathrooms[gte]"`
+	BathroomsLT       string `query:"bathrooms[lt]"`
+	BathroomsLTE      string `query:"bathrooms[lte]"`
+	BathroomsBetween  string `query:"bathrooms[between]"`
+	SquareFeet        string
+	SquareFeetGT      string `query:"square_feet[gt]"`
+	SquareFeetGTE     string `query:"square_feet[gte]"`
+	SquareFeetLT      string `query:"square_feet[lt]"`
+	SquareFeetLTE     string `query:"square_feet[lte]"`
+	SquareFeetBetween string `query:"square_feet[between]"`
+	YearBuilt         string
+	YearBuiltGT       string `query:"year_built[gt]"`
+	YearBuiltGTE      string `query:"year_built[gte]"`
+	YearBuiltLT       string `query:"year_built[lt]"`
+	YearBuiltLTE      string `query:"year_built[lte]"`
+	YearBuiltBetween  string `query:"year_built[between]"`
+	Units             string
+	UnitsGT           string `query:"units[gt]"`
+	UnitsGTE          string `query:"units[gte]"`
+	UnitsLT           string `query:"units[lt]"`
+	UnitsLTE          string `query:"units[lte]"`
+	UnitsBetween      string `query:"units[between]"`
+	Fields            string
+	Expand            string
 }
 
 // =============================================================================
@@ -35,14 +78,32 @@ type Address struct {
 	Country  string `json:"country"`
 }
 
+// Attributes represents the physical characteristics of a home.
+type Attributes struct {
+	Bedrooms   int     `json:"bedrooms"`
+	Bathrooms  float64 `json:"bathrooms"`
+	SquareFeet int     `json:"squareFeet"`
+	YearBuilt  int     `json:"yearBuilt"`
+	Units      int     `json:"units"`
+}
+
+// Location represents where a home is in decimal degrees.
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
 // Home represents information about an individual home.
 type Home struct {
-	ID          string  `json:"id"`
-	UserID      string  `json:"userID"`
-	Type        string  `json:"type"`
-	Address     Address `json:"address"`
-	DateCreated string  `json:"dateCreated"`
-	DateUpdated string  `json:"dateUpdated"`
+	ID          string     `json:"id"`
+	UserID      string     `json:"userID"`
+	Type        string     `json:"type"`
+	Address     Address    `json:"address"`
+	Attributes  Attributes `json:"attributes"`
+	Location    *Location  `json:"location,omitempty"`
+	DateCreated string     `json:"dateCreated"`
+	DateUpdated string     `json:"dateUpdated"`
+	User        *User      `json:"user,omitempty"`
 }
 
 // Encode implments the encoder interface.
@@ -52,6 +113,14 @@ func (app Home) Encode() ([]byte, string, error) {
 }
 
 func toAppHome(hme homebus.Home) Home {
+	var loc *Location
+	if !hme.Location.IsZero() {
+		loc = &Location{
+			Latitude:  hme.Location.Latitude,
+			Longitude: hme.Location.Longitude,
+		}
+	}
+
 	return Home{
 		ID:     hme.ID.String(),
 		UserID: hme.UserID.String(),
@@ -64,6 +133,14 @@ func toAppHome(hme homebus.Home) Home {
 			State:    hme.Address.State,
 			Country:  hme.Address.Country,
 		},
+		Attributes: Attributes{
+			Bedrooms:   hme.Attributes.Bedrooms,
+			Bathrooms:  hme.Attributes.Bathrooms,
+			SquareFeet: hme.Attributes.SquareFeet,
+			YearBuilt:  hme.Attributes.YearBuilt,
+			Units:      hme.Attributes.Units,
+		},
+		Location:    loc,
 		DateCreated: hme.DateCreated.Format(time.RFC3339),
 		DateUpdated: hme.DateUpdated.Format(time.RFC3339),
 	}
@@ -78,22 +155,82 @@ func toAppHomes(homes []homebus.Home) []Home {
 	return app
 }
 
+// User represents the owner of a home when a query expands it.
+type User struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	Department string `json:"department"`
+}
+
+func toAppUser(usr userbus.User) User {
+	return User{
+		ID:         usr.ID.String(),
+		Name:       usr.Name.String(),
+		Email:      usr.Email.Address,
+		Department: usr.Department,
+	}
+}
+
+// addressFields maps the fields of a business address to the names used by
+// the api.
+var addressFields = map[string]string{
+	"Address1": "address1",
+	"Address2": "address2",
+	"ZipCode":  "zipCode",
+	"City":     "city",
+	"State":    "state",
+	"Country":  "country",
+}
+
+// attributeFields maps the fields of business attributes to the names used
+// by the api.
+var attributeFields = map[string]string{
+	"Bedrooms":   "bedrooms",
+	"Bathrooms":  "bathrooms",
+	"SquareFeet": "squareFeet",
+	"YearBuilt":  "yearBuilt",
+	"Units":      "units",
+}
+
+func toFieldErrors(flds []homebus.FieldError, names map[string]string) errs.FieldErrors {
+	fields := make(errs.FieldErrors, len(flds))
+	for i, fld := range flds {
+		fields[i] = errs.FieldError{
+			Field: names[fld.Field],
+			Err:   fld.Err,
+		}
+	}
+
+	return fields
+}
+
 // =============================================================================
 
 // NewAddress defines the data needed to add a new address.
 type NewAddress struct {
 	Address1 string `json:"address1" validate:"required,min=1,max=70"`
 	Address2 string `json:"address2" validate:"omitempty,max=70"`
-	ZipCode  string `json:"zipCode" validate:"required,numeric"`
+	ZipCode  string `json:"zipCode" validate:"required,max=10"`
 	City     string `json:"city" validate:"required"`
-	State    string `json:"state" validate:"required,min=1,max=48"`
+	State    string `json:"state" validate:"omitempty,max=48"`
 	Country  string `json:"country" validate:"required,iso3166_1_alpha2"`
 }
 
+// NewAttributes defines the attributes that can be provided for a new home.
+type NewAttributes struct {
+	Bedrooms   int     `json:"bedrooms" validate:"min=0"`
+	Bathrooms  float64 `json:"bathrooms" validate:"min=0"`
+	SquareFeet int     `json:"squareFeet" validate:"min=0"`
+	YearBuilt  int     `json:"yearBuilt" validate:"min=0"`
+	Units      int     `json:"units" validate:"min=0"`
+}
+
 // NewHome defines the data needed to add a new home.
 type NewHome struct {
-	Type    string     `json:"type" validate:"required"`
-	Address NewAddress `json:"address"`
+	Type       string        `json:"type" validate:"required"`
+	Address    NewAddress    `json:"address"`
+	Attributes NewAttributes `json:"attributes"`
 }
 
 // Decode implments the decoder interface.
@@ -110,6 +247,23 @@ func (app NewHome) Validate() error {
 	return nil
 }
 
+// NewHomes defines the data needed to add many homes at once. Mode is
+// either atomic, the default, or bestEffort.
+type NewHomes struct {
+	Mode  string    `json:"mode"`
+	Items []NewHome `json:"items" validate:"required,min=1,max=1000"`
+}
+
+// Validate checks the data in the model is considered clean. The items are
+// checked one at a time so each gets its own result.
+func (app NewHomes) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
 func toBusNewHome(ctx context.Context, app NewHome) (homebus.NewHome, error) {
 	userID, err := mid.GetUserID(ctx)
 	if err != nil {
@@ -132,6 +286,13 @@ func toBusNewHome(ctx context.Context, app NewHome) (homebus.NewHome, error) {
 			State:    app.Address.State,
 			Country:  app.Address.Country,
 		},
+		Attributes: homebus.Attributes{
+			Bedrooms:   app.Attributes.Bedrooms,
+			Bathrooms:  app.Attributes.Bathrooms,
+			SquareFeet: app.Attributes.SquareFeet,
+			YearBuilt:  app.Attributes.YearBuilt,
+			Units:      app.Attributes.Units,
+		},
 	}
 
 	return bus, nil
@@ -143,16 +304,26 @@ func toBusNewHome(ctx context.Context, app NewHome) (homebus.NewHome, error) {
 type UpdateAddress struct {
 	Address1 *string `json:"address1" validate:"omitempty,min=1,max=70"`
 	Address2 *string `json:"address2" validate:"omitempty,max=70"`
-	ZipCode  *string `json:"zipCode" validate:"omitempty,numeric"`
+	ZipCode  *string `json:"zipCode" validate:"omitempty,min=1,max=10"`
 	City     *string `json:"city"`
-	State    *string `json:"state" validate:"omitempty,min=1,max=48"`
+	State    *string `json:"state" validate:"omitempty,max=48"`
 	Country  *string `json:"country" validate:"omitempty,iso3166_1_alpha2"`
 }
 
+// UpdateAttributes defines the data needed to update the attributes of a home.
+type UpdateAttributes struct {
+	Bedrooms   *int     `json:"bedrooms" validate:"omitempty,min=0"`
+	Bathrooms  *float64 `json:"bathrooms" validate:"omitempty,min=0"`
+	SquareFeet *int     `json:"squareFeet" validate:"omitempty,min=0"`
+	YearBuilt  *int     `json:"yearBuilt" validate:"omitempty,min=0"`
+	Units      *int     `json:"units" validate:"omitempty,min=0"`
+}
+
 // UpdateHome defines the data needed to update a home.
 type UpdateHome struct {
-	Type    *string        `json:"type"`
-	Address *UpdateAddress `json:"address"`
+	Type       *string           `json:"type"`
+	Address    *UpdateAddress    `json:"address"`
+	Attributes *UpdateAttributes `json:"attributes"`
 }
 
 // Decode implments the decoder interface.
@@ -169,18 +340,129 @@ func (app UpdateHome) Validate() error {
 	return nil
 }
 
+// UpdateHomeItem defines the data needed to update one of the homes of a
+// bulk update.
+type UpdateHomeItem struct {
+	ID string `json:"id"`
+	UpdateHome
+}
+
+// UpdateHomes defines the data needed to update many homes at once. Mode is
+// either atomic, the default, or bestEffort.
+type UpdateHomes struct {
+	Mode  string           `json:"mode"`
+	Items []UpdateHomeItem `json:"items" validate:"required,min=1,max=1000"`
+}
+
+// Validate checks the data in the model is considered clean. The items are
+// checked one at a time so each gets its own result, but an id given for
+// more than one item fails the whole request.
+func (app UpdateHomes) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	ids := make([]string, len(app.Items))
+	for i, item := range app.Items {
+		ids[i] = item.ID
+	}
+
+	if err := bulk.UniqueIDs(ids); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// PatchAddress defines the data needed to patch an address. Address2 and
+// State are the only members that can be set to null, which clears them.
+type PatchAddress struct {
+	Address1 patch.Field[string] `json:"address1"`
+	Address2 patch.Field[string] `json:"address2"`
+	ZipCode  patch.Field[string] `json:"zipCode"`
+	City     patch.Field[string] `json:"city"`
+	State    patch.Field[string] `json:"state"`
+	Country  patch.Field[string] `json:"country"`
+}
+
+// PatchAttributes defines the data needed to patch the attributes of a home.
+// Setting a member to null resets it to zero.
+type PatchAttributes struct {
+	Bedrooms   patch.Field[int]     `json:"bedrooms"`
+	Bathrooms  patch.Field[float64] `json:"bathrooms"`
+	SquareFeet patch.Field[int]     `json:"squareFeet"`
+	YearBuilt  patch.Field[int]     `json:"yearBuilt"`
+	Units      patch.Field[int]     `json:"units"`
+}
+
+// PatchHome defines the data needed to patch a home with a merge patch
+// document. Members left out of the document are not changed. The address
+// and attributes are patched member by member and can't be set to null.
+type PatchHome struct {
+	Type       patch.Field[string]          `json:"type"`
+	Address    patch.Field[PatchAddress]    `json:"address"`
+	Attributes patch.Field[PatchAttributes] `json:"attributes"`
+}
+
+// Decode implments the decoder interface.
+func (app *PatchHome) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app PatchHome) Validate() error {
+	uh, err := toUpdateHome(app)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return uh.Validate()
+}
+
+func toUpdateHome(app PatchHome) (UpdateHome, error) {
+	var fe errs.FieldErrors
+
+	uh := UpdateHome{
+		Type: patch.Required(&fe, "type", app.Type),
+	}
+
+	if addr := patch.Required(&fe, "address", app.Address); addr != nil {
+		uh.Address = &UpdateAddress{
+			Address1: patch.Required(&fe, "address1", addr.Address1),
+			Address2: addr.Address2.Nullable(),
+			ZipCode:  patch.Required(&fe, "zipCode", addr.ZipCode),
+			City:     patch.Required(&fe, "city", addr.City),
+			State:    addr.State.Nullable(),
+			Country:  patch.Required(&fe, "country", addr.Country),
+		}
+	}
+
+	if attr := patch.Required(&fe, "attributes", app.Attributes); attr != nil {
+		uh.Attributes = &UpdateAttributes{
+			Bedrooms:   attr.Bedrooms.Nullable(),
+			Bathrooms:  attr.Bathrooms.Nullable(),
+			SquareFeet: attr.SquareFeet.Nullable(),
+			YearBuilt:  attr.YearBuilt.Nullable(),
+			Units:      attr.Units.Nullable(),
+		}
+	}
+
+	if len(fe) > 0 {
+		return UpdateHome{}, fe
+	}
+
+	return uh, nil
+}
+
 func toBusUpdateHome(app UpdateHome) (homebus.UpdateHome, error) {
-	var typ homebus.Type
+	var bus homebus.UpdateHome
+
 	if app.Type != nil {
-		var err error
-		typ, err = homebus.ParseType(*app.Type)
+		typ, err := homebus.ParseType(*app.Type)
 		if err != nil {
 			return homebus.UpdateHome{}, fmt.Errorf("parse: %w", err)
 		}
-	}
-
-	bus := homebus.UpdateHome{
-		Type: &typ,
+		bus.Type = &typ
 	}
 
 	if app.Address != nil {
@@ -194,5 +476,156 @@ func toBusUpdateHome(app UpdateHome) (homebus.UpdateHome, error) {
 		}
 	}
 
+	if app.Attributes != nil {
+		bus.Attributes = &homebus.UpdateAttributes{
+			Bedrooms:   app.Attributes.Bedrooms,
+			Bathrooms:  app.Attributes.Bathrooms,
+			SquareFeet: app.Attributes.SquareFeet,
+			YearBuilt:  app.Attributes.YearBuilt,
+			Units:      app.Attributes.Units,
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// Member represents a user a home is shared with.
+type Member struct {
+	HomeID      string `json:"homeID"`
+	UserID      string `json:"userID"`
+	Role        string `json:"role"`
+	DateCreated string `json:"dateCreated"`
+}
+
+// Encode implments the encoder interface.
+func (app Member) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppMember(mem homebus.Member) Member {
+	return Member{
+		HomeID:      mem.HomeID.String(),
+		UserID:      mem.UserID.String(),
+		Role:        mem.Role.String(),
+		DateCreated: mem.DateCreated.Format(time.RFC3339),
+	}
+}
+
+// Members represents the owner of a home and the users it is shared with.
+type Members struct {
+	Members []Member `json:"members"`
+}
+
+// Encode implments the encoder interface.
+func (app Members) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppMembers(mems []homebus.Member) Members {
+	app := make([]Member, len(mems))
+	for i, mem := range mems {
+		app[i] = toAppMember(mem)
+	}
+
+	return Members{
+		Members: app,
+	}
+}
+
+// NewMember defines the data needed to share a home with a user.
+type NewMember struct {
+	UserID string `json:"userID" validate:"required,uuid"`
+	Role   string `json:"role" validate:"required"`
+}
+
+// Decode implments the decoder interface.
+func (app *NewMember) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewMember) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewMember(app NewMember) (homebus.NewMember, error) {
+	userID, err := uuid.Parse(app.UserID)
+	if err != nil {
+		return homebus.NewMember{}, fmt.Errorf("parse userID: %w", err)
+	}
+
+	role, err := homebus.ParseRole(app.Role)
+	if err != nil {
+		return homebus.NewMember{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := homebus.NewMember{
+		UserID: userID,
+		Role:   role,
+	}
+
 	return bus, nil
 }
+
+// =============================================================================
+
+// Transfer represents a request to hand a home over to another user.
+type Transfer struct {
+	ID           string `json:"id"`
+	HomeID       string `json:"homeID"`
+	FromUserID   string `json:"fromUserID"`
+	ToUserID     string `json:"toUserID"`
+	DateCreated  string `json:"dateCreated"`
+	DateExpires  string `json:"dateExpires"`
+	DateAccepted string `json:"dateAccepted,omitempty"`
+}
+
+// Encode implments the encoder interface.
+func (app Transfer) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppTransfer(trn homebus.Transfer) Transfer {
+	app := Transfer{
+		ID:          trn.ID.String(),
+		HomeID:      trn.HomeID.String(),
+		FromUserID:  trn.FromUserID.String(),
+		ToUserID:    trn.ToUserID.String(),
+		DateCreated: trn.DateCreated.Format(time.RFC3339),
+		DateExpires: trn.DateExpires.Format(time.RFC3339),
+	}
+
+	if !trn.DateAccepted.IsZero() {
+		app.DateAccepted = trn.DateAccepted.Format(time.RFC3339)
+	}
+
+	return app
+}
+
+// NewTransfer defines the data needed to request a home transfer.
+type NewTransfer struct {
+	UserID string `json:"userID" validate:"required,uuid"`
+}
+
+// Decode implments the decoder interface.
+func (app *NewTransfer) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewTransfer) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
@@ -1,14 +1,72 @@
 package homeapp
 
 import (
+	"strconv"
+
 	"github.com/ardanlabs/encore/business/domain/homebus"
 	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
 )
 
 var defaultOrderBy = order.NewBy("home_id", order.ASC)
 
+// nearOrderBy is the default for proximity searches so the closest homes come
+// first.
+var nearOrderBy = order.NewBy(homebus.OrderByDistance, order.ASC)
+
 var orderByFields = map[string]string{
-	"home_id": homebus.OrderByID,
-	"type":    homebus.OrderByType,
-	"user_id": homebus.OrderByUserID,
+	"home_id":     homebus.OrderByID,
+	"type":        homebus.OrderByType,
+	"user_id":     homebus.OrderByUserID,
+	"bedrooms":    homebus.OrderByBedrooms,
+	"bathrooms":   homebus.OrderByBathrooms,
+	"square_feet": homebus.OrderBySquareFeet,
+	"year_built":  homebus.OrderByYearBuilt,
+	"units":       homebus.OrderByUnits,
+	"distance":    homebus.OrderByDistance,
+}
+
+// cursors returns the cursors of the pages around the homes that were
+// fetched for the page.
+func cursors(pg page.Page, orderBy order.By, hmes []homebus.Home) (string, string) {
+	keys := orderBy.Keys()
+
+	return pg.Cursors(orderBy, len(hmes), func(i int) page.Key {
+		values := make([]string, len(keys))
+		for j, key := range keys {
+			values[j] = cursorValue(hmes[i], key.Field)
+		}
+
+		return page.Key{
+			Values: values,
+			ID:     hmes[i].ID.String(),
+		}
+	})
+}
+
+// cursorValue returns the value of the field for the home in the text form the
+// database parses back into the type of the column.
+func cursorValue(hme homebus.Home, field string) string {
+	switch field {
+	case homebus.OrderByID:
+		return hme.ID.String()
+	case homebus.OrderByType:
+		return hme.Type.String()
+	case homebus.OrderByUserID:
+		return hme.UserID.String()
+	case homebus.OrderByBedrooms:
+		return strconv.Itoa(hme.Attributes.Bedrooms)
+	case homebus.OrderByBathrooms:
+		return strconv.FormatFloat(hme.Attributes.Bathrooms, 'g', -1, 64)
+	case homebus.OrderBySquareFeet:
+		return strconv.Itoa(hme.Attributes.SquareFeet)
+	case homebus.OrderByYearBuilt:
+		return strconv.Itoa(hme.Attributes.YearBuilt)
+	case homebus.OrderByUnits:
+		return strconv.Itoa(hme.Attributes.Units)
+	case homebus.OrderByDistance:
+		return strconv.FormatFloat(hme.Distance, 'g', -1, 64)
+	}
+
+	return ""
 }
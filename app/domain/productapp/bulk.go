@@ -0,0 +1,163 @@
+package productapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/bulk"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/google/uuid"
+)
+
+// CreateMany adds the products to the system in a single transaction and
+// reports the result of each of them.
+func (a *App) CreateMany(ctx context.Context, app NewProducts) (bulk.Result, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return bulk.Result{}, errs.New(errs.Internal, err)
+	}
+
+	mode, err := bulk.ParseMode(app.Mode)
+	if err != nil {
+		return bulk.Result{}, errs.NewFieldsError("mode", err)
+	}
+
+	res := bulk.NewResult(len(app.Items))
+
+	var batch bulk.Batch[productbus.NewProduct]
+	for i, item := range app.Items {
+		if err := errs.Check(item); err != nil {
+			res.Fail(i, err)
+			continue
+		}
+
+		np, err := toBusNewProduct(ctx, item)
+		if err != nil {
+			res.Fail(i, err)
+			continue
+		}
+
+		batch.Add(i, np)
+	}
+
+	for {
+		if mode == bulk.Atomic && res.Failed > 0 {
+			return bulk.Result{}, res.Err()
+		}
+
+		if len(batch.Items) == 0 {
+			return *res, nil
+		}
+
+		prds, err := a.productBus.CreateMany(ctx, batch.Items)
+		if err != nil {
+			if batch.Drop(res, err, toItemError) {
+				continue
+			}
+			return bulk.Result{}, errs.Newf(errs.Internal, "createmany: %s", err)
+		}
+
+		for i, prd := range prds {
+			res.Succeed(batch.Indexes[i], prd.ID.String())
+		}
+
+		return *res, nil
+	}
+}
+
+// UpdateMany updates the products in a single transaction and reports the
+// result of each of them. Callers that aren't admins can only update the
+// products they own.
+func (a *App) UpdateMany(ctx context.Context, app UpdateProducts) (bulk.Result, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return bulk.Result{}, errs.New(errs.Internal, err)
+	}
+
+	mode, err := bulk.ParseMode(app.Mode)
+	if err != nil {
+		return bulk.Result{}, errs.NewFieldsError("mode", err)
+	}
+
+	claims, err := mid.GetClaims(ctx)
+	if err != nil {
+		return bulk.Result{}, errs.Newf(errs.Internal, "updatemany: %s", err)
+	}
+
+	res := bulk.NewResult(len(app.Items))
+
+	var batch bulk.Batch[productbus.UpdateProduct]
+	var ids []uuid.UUID
+	for i, item := range app.Items {
+		id, err := uuid.Parse(item.ID)
+		if err != nil {
+			res.Fail(i, errs.NewFieldsError("id", err))
+			continue
+		}
+
+		if err := errs.Check(item.UpdateProduct); err != nil {
+			res.Fail(i, err)
+			continue
+		}
+
+		up, err := toBusUpdateProduct(item.UpdateProduct)
+		if err != nil {
+			res.Fail(i, errs.NewFieldsError("name", err))
+			continue
+		}
+
+		batch.Add(i, up)
+		ids = append(ids, id)
+	}
+
+	found, err := a.productBus.QueryByIDs(ctx, ids)
+	if err != nil {
+		return bulk.Result{}, errs.Newf(errs.Internal, "querybyids: %s", err)
+	}
+
+	prds := make(map[uuid.UUID]productbus.Product, len(found))
+	for _, prd := range found {
+		if claims.IsAdmin() || prd.UserID.String() == claims.Subject {
+			prds[prd.ID] = prd
+		}
+	}
+
+	for i, up := range batch.Items {
+		index := batch.Indexes[i]
+
+		prd, exists := prds[ids[i]]
+		if !exists {
+			res.Fail(index, errs.NewFieldsError("id", productbus.ErrNotFound))
+			continue
+		}
+
+		if mode == bulk.Atomic && res.Failed > 0 {
+			continue
+		}
+
+		updPrd, err := a.productBus.Update(ctx, prd, up)
+		if err != nil {
+			return bulk.Result{}, errs.Newf(errs.Internal, "update: productID[%s] up[%+v]: %s", prd.ID, up, err)
+		}
+
+		res.Succeed(index, updPrd.ID.String())
+	}
+
+	if mode == bulk.Atomic && res.Failed > 0 {
+		return bulk.Result{}, res.Err()
+	}
+
+	return *res, nil
+}
+
+// toItemError reports the business errors of an item against the field they
+// were caused by.
+func toItemError(err error) error {
+	if errors.Is(err, productbus.ErrInvalidCost) {
+		return errs.NewFieldsError("cost", err)
+	}
+
+	return err
+}
@@ -0,0 +1,234 @@
+package productapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ardanlabs/encore/app/sdk/bulk"
+	"github.com/ardanlabs/encore/app/sdk/csvio"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+// maxImportRows is the most rows an import can hold.
+const maxImportRows = 10000
+
+// importChunk is the number of products added by each insert of an import.
+const importChunk = 1000
+
+var exportHeader = []string{"id", "userID", "name", "cost", "quantity", "reorderLevel", "lowStock", "active", "dateCreated", "dateUpdated"}
+
+// Export writes every product matching the query params to w as csv. The
+// products are written as they're read, so nothing is written when the
+// params aren't valid.
+func (a *App) Export(ctx context.Context, w io.Writer, qp QueryParams) error {
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err
+	}
+
+	defaultOrder := defaultOrderBy
+	if filter.Search != nil {
+		defaultOrder = searchOrderBy
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrder)
+	if err != nil {
+		return err
+	}
+
+	cw, err := csvio.NewWriter(w, exportHeader)
+	if err != nil {
+		return errs.Newf(errs.Internal, "export: %s", err)
+	}
+
+	err = a.productBus.QueryEach(ctx, filter, orderBy, func(prd productbus.Product) error {
+		app := toAppProduct(prd)
+
+		return cw.Write([]string{
+			app.ID,
+			app.UserID,
+			app.Name,
+			strconv.FormatFloat(app.Cost, 'f', -1, 64),
+			strconv.Itoa(app.Quantity),
+			strconv.Itoa(app.ReorderLevel),
+			strconv.FormatBool(app.LowStock),
+			strconv.FormatBool(app.Active),
+			app.DateCreated,
+			app.DateUpdated,
+		})
+	})
+	if err != nil {
+		return errs.Newf(errs.Internal, "export: %s", err)
+	}
+
+	if err := cw.Flush(); err != nil {
+		return errs.Newf(errs.Internal, "export: %s", err)
+	}
+
+	return nil
+}
+
+// Import adds a product for every row of the csv read from r and reports the
+// errors of the rows that couldn't be added. The file needs name, cost and
+// quantity columns and may have a reorderLevel column. Every chunk of
+// products is added in its own transaction started with bgn, and when a chunk
+// fails the error carries the report of the chunks that were added before it.
+func (a *App) Import(ctx context.Context, bgn sqldb.Beginner, r io.Reader) (csvio.Report, error) {
+	cr, err := csvio.NewReader(r, "name", "cost", "quantity")
+	if err != nil {
+		return csvio.Report{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	var recs []csvio.Record
+	for {
+		rec, err := cr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return csvio.Report{}, errs.New(errs.InvalidArgument, err)
+		}
+
+		if len(recs) == maxImportRows {
+			return csvio.Report{}, errs.Newf(errs.InvalidArgument, "file has more than %d rows", maxImportRows)
+		}
+		recs = append(recs, rec)
+	}
+
+	res := bulk.NewResult(len(recs))
+	rows := make([]int, len(recs))
+
+	var batch bulk.Batch[productbus.NewProduct]
+	for i, rec := range recs {
+		rows[i] = rec.Row
+
+		np, err := toBusNewProductRecord(ctx, rec)
+		if err != nil {
+			res.Fail(i, err)
+			continue
+		}
+
+		batch.Add(i, np)
+	}
+
+	for start := 0; start < len(batch.Items); start += importChunk {
+		end := min(start+importChunk, len(batch.Items))
+
+		chunk := bulk.Batch[productbus.NewProduct]{
+			Items:   batch.Items[start:end],
+			Indexes: batch.Indexes[start:end],
+		}
+
+		if err := a.createChunk(ctx, bgn, res, chunk); err != nil {
+			err = fmt.Errorf("import: rows from %d were not imported: %w", rows[chunk.Indexes[0]], err)
+			return csvio.Report{}, errs.NewDetails(errs.Internal, err, csvio.NewReport(res, rows))
+		}
+	}
+
+	return csvio.NewReport(res, rows), nil
+}
+
+// createChunk adds the products of the chunk, leaving out the ones the
+// business layer rejects. The products are added in a transaction that's
+// only committed once the insert succeeds, and a rejected insert is retried
+// in a new one without the products that caused it.
+func (a *App) createChunk(ctx context.Context, bgn sqldb.Beginner, res *bulk.Result, chunk bulk.Batch[productbus.NewProduct]) error {
+	for len(chunk.Items) > 0 {
+		prds, err := a.createMany(ctx, bgn, chunk.Items)
+		if err != nil {
+			if chunk.Drop(res, err, toItemError) {
+				continue
+			}
+			return err
+		}
+
+		for i, prd := range prds {
+			res.Succeed(chunk.Indexes[i], prd.ID.String())
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// createMany adds the products in a transaction of their own.
+func (a *App) createMany(ctx context.Context, bgn sqldb.Beginner, nps []productbus.NewProduct) ([]productbus.Product, error) {
+	tx, err := bgn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin: %w", err)
+	}
+
+	prds, err := func() ([]productbus.Product, error) {
+		productBus, err := a.productBus.NewWithTx(tx)
+		if err != nil {
+			return nil, fmt.Errorf("newwithtx: %w", err)
+		}
+
+		return productBus.CreateMany(ctx, nps)
+	}()
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return nil, fmt.Errorf("rollback: %w: %w", rbErr, err)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	return prds, nil
+}
+
+// toBusNewProductRecord converts a row of an import into a new product,
+// checking it the same way a new product from the api is checked.
+func toBusNewProductRecord(ctx context.Context, rec csvio.Record) (productbus.NewProduct, error) {
+	var fields errs.FieldErrors
+
+	cost, err := strconv.ParseFloat(rec.Get("cost"), 64)
+	if err != nil {
+		fields = append(fields, errs.FieldError{Field: "cost", Err: fmt.Sprintf("cost %q is not a number", rec.Get("cost"))})
+	}
+
+	quantity, err := strconv.Atoi(rec.Get("quantity"))
+	if err != nil {
+		fields = append(fields, errs.FieldError{Field: "quantity", Err: fmt.Sprintf("quantity %q is not a whole number", rec.Get("quantity"))})
+	}
+
+	var reorderLevel int
+	if v := rec.Get("reorderLevel"); v != "" {
+		reorderLevel, err = strconv.Atoi(v)
+		if err != nil {
+			fields = append(fields, errs.FieldError{Field: "reorderLevel", Err: fmt.Sprintf("reorderLevel %q is not a whole number", v)})
+		}
+	}
+
+	if len(fields) > 0 {
+		return productbus.NewProduct{}, fields
+	}
+
+	app := NewProduct{
+		Name:         rec.Get("name"),
+		Cost:         cost,
+		Quantity:     quantity,
+		ReorderLevel: reorderLevel,
+	}
+
+	if err := errs.Check(app); err != nil {
+		return productbus.NewProduct{}, err
+	}
+
+	np, err := toBusNewProduct(ctx, app)
+	if err != nil {
+		return productbus.NewProduct{}, errs.NewFieldsError("name", err)
+	}
+
+	return np, nil
+}
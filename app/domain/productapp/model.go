@@ -6,33 +6,81 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ardanlabs/encore/app/sdk/bulk"
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/app/sdk/patch"
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/google/uuid"
 )
 
-// QueryParams represents the set of possible query strings.
+// QueryParams represents the set of possible query strings. Fields that
+// support the filter operators take them in brackets, such as cost[gte].
 type QueryParams struct {
-	Page     string
-	Rows     string
-	OrderBy  string
-	ID       string
-	Name     string
-	Cost     string
-	Quantity string
+	Page            string
+	Rows            string
+	Cursor          string
+	Total           string
+	OrderBy         string
+	ID              string
+	IDNE            string `query:"id[ne]"`
+	IDIn            string `query:"id[in]"`
+	Name            string
+	Cost            string
+	CostNE          string `query:"cost[ne]"`
+	CostGT          string `query:"cost[gt]"`
+	CostGTE         string `query:"cost[gte]"`
+	CostLT          string `query:"cost[lt]"`
+	CostLTE         string `query:"cost[lte]"`
+	CostIn          string `query:"cost[in]"`
+	CostBetween     string `query:"cost[between]"`
+	Quantity        string
+	QuantityNE      string `query:"quantity[ne]"`
+	QuantityGT      string `query:"quantity[gt]"`
+	QuantityGTE     string `query:"quantity[gte]"`
+	QuantityLT      string `query:"quantity[lt]"`
+	QuantityLTE     string `query:"quantity[lte]"`
+	QuantityIn      string `query:"quantity[in]"`
+	QuantityBetween string `query:"quantity[between]"`
+	Category        string
+	Tag             string
+	Active          string
+	Q               string
+	Fields          string
+	Expand          string
+}
+
+// PriceQueryParams represents the set of possible query strings when
+// listing the price timeline for a product.
+type PriceQueryParams struct {
+	Page string
+	Rows string
+}
+
+// MovementQueryParams represents the set of possible query strings when
+// listing the stock movements for a product.
+type MovementQueryParams struct {
+	Page string
+	Rows string
 }
 
 // =============================================================================
 
 // Product represents information about an individual product.
 type Product struct {
-	ID          string  `json:"id"`
-	UserID      string  `json:"userID"`
-	Name        string  `json:"name"`
-	Cost        float64 `json:"cost"`
-	Quantity    int     `json:"quantity"`
-	DateCreated string  `json:"dateCreated"`
-	DateUpdated string  `json:"dateUpdated"`
+	ID           string  `json:"id"`
+	UserID       string  `json:"userID"`
+	Name         string  `json:"name"`
+	Cost         float64 `json:"cost"`
+	Quantity     int     `json:"quantity"`
+	ReorderLevel int     `json:"reorderLevel"`
+	LowStock     bool    `json:"lowStock"`
+	Active       bool    `json:"active"`
+	Snippet      string  `json:"snippet,omitempty"`
+	DateCreated  string  `json:"dateCreated"`
+	DateUpdated  string  `json:"dateUpdated"`
+	User         *User   `json:"user,omitempty"`
 }
 
 // Encode implments the encoder interface.
@@ -43,13 +91,17 @@ func (app Product) Encode() ([]byte, string, error) {
 
 func toAppProduct(prd productbus.Product) Product {
 	return Product{
-		ID:          prd.ID.String(),
-		UserID:      prd.UserID.String(),
-		Name:        prd.Name.String(),
-		Cost:        prd.Cost,
-		Quantity:    prd.Quantity,
-		DateCreated: prd.DateCreated.Format(time.RFC3339),
-		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
+		ID:           prd.ID.String(),
+		UserID:       prd.UserID.String(),
+		Name:         prd.Name.String(),
+		Cost:         prd.Cost,
+		Quantity:     prd.Quantity,
+		ReorderLevel: prd.ReorderLevel,
+		LowStock:     prd.LowStockAlerted,
+		Active:       prd.Active,
+		Snippet:      prd.Snippet,
+		DateCreated:  prd.DateCreated.Format(time.RFC3339),
+		DateUpdated:  prd.DateUpdated.Format(time.RFC3339),
 	}
 }
 
@@ -62,13 +114,31 @@ func toAppProducts(prds []productbus.Product) []Product {
 	return app
 }
 
+// User represents the owner of a product when a query expands it.
+type User struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	Department string `json:"department"`
+}
+
+func toAppUser(usr userbus.User) User {
+	return User{
+		ID:         usr.ID.String(),
+		Name:       usr.Name.String(),
+		Email:      usr.Email.Address,
+		Department: usr.Department,
+	}
+}
+
 // =============================================================================
 
 // NewProduct defines the data needed to add a new product.
 type NewProduct struct {
-	Name     string  `json:"name" validate:"required"`
-	Cost     float64 `json:"cost" validate:"required,gte=0"`
-	Quantity int     `json:"quantity" validate:"required,gte=1"`
+	Name         string  `json:"name" validate:"required"`
+	Cost         float64 `json:"cost" validate:"required,gte=0"`
+	Quantity     int     `json:"quantity" validate:"required,gte=1"`
+	ReorderLevel int     `json:"reorderLevel" validate:"gte=0"`
 }
 
 // Decode implments the decoder interface.
@@ -85,6 +155,23 @@ func (app NewProduct) Validate() error {
 	return nil
 }
 
+// NewProducts defines the data needed to add many products at once. Mode
+// is either atomic, the default, or bestEffort.
+type NewProducts struct {
+	Mode  string       `json:"mode"`
+	Items []NewProduct `json:"items" validate:"required,min=1,max=1000"`
+}
+
+// Validate checks the data in the model is considered clean. The items are
+// checked one at a time so each gets its own result.
+func (app NewProducts) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
 func toBusNewProduct(ctx context.Context, app NewProduct) (productbus.NewProduct, error) {
 	userID, err := mid.GetUserID(ctx)
 	if err != nil {
@@ -97,10 +184,11 @@ func toBusNewProduct(ctx context.Context, app NewProduct) (productbus.NewProduct
 	}
 
 	bus := productbus.NewProduct{
-		UserID:   userID,
-		Name:     name,
-		Cost:     app.Cost,
-		Quantity: app.Quantity,
+		UserID:       userID,
+		Name:         name,
+		Cost:         app.Cost,
+		Quantity:     app.Quantity,
+		ReorderLevel: app.ReorderLevel,
 	}
 
 	return bus, nil
@@ -110,9 +198,10 @@ func toBusNewProduct(ctx context.Context, app NewProduct) (productbus.NewProduct
 
 // UpdateProduct defines the data needed to update a product.
 type UpdateProduct struct {
-	Name     *string  `json:"name"`
-	Cost     *float64 `json:"cost" validate:"omitempty,gte=0"`
-	Quantity *int     `json:"quantity" validate:"omitempty,gte=1"`
+	Name         *string  `json:"name"`
+	Cost         *float64 `json:"cost" validate:"omitempty,gte=0"`
+	Quantity     *int     `json:"quantity" validate:"omitempty,gte=1"`
+	ReorderLevel *int     `json:"reorderLevel" validate:"omitempty,gte=0"`
 }
 
 // Decode implments the decoder interface.
@@ -129,6 +218,82 @@ func (app UpdateProduct) Validate() error {
 	return nil
 }
 
+// UpdateProductItem defines the data needed to update one of the products
+// of a bulk update.
+type UpdateProductItem struct {
+	ID string `json:"id"`
+	UpdateProduct
+}
+
+// UpdateProducts defines the data needed to update many products at once.
+// Mode is either atomic, the default, or bestEffort.
+type UpdateProducts struct {
+	Mode  string              `json:"mode"`
+	Items []UpdateProductItem `json:"items" validate:"required,min=1,max=1000"`
+}
+
+// Validate checks the data in the model is considered clean. The items are
+// checked one at a time so each gets its own result, but an id given for
+// more than one item fails the whole request.
+func (app UpdateProducts) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	ids := make([]string, len(app.Items))
+	for i, item := range app.Items {
+		ids[i] = item.ID
+	}
+
+	if err := bulk.UniqueIDs(ids); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// PatchProduct defines the data needed to patch a product with a merge patch
+// document. Members left out of the document are not changed and none of
+// them can be set to null.
+type PatchProduct struct {
+	Name         patch.Field[string]  `json:"name"`
+	Cost         patch.Field[float64] `json:"cost"`
+	Quantity     patch.Field[int]     `json:"quantity"`
+	ReorderLevel patch.Field[int]     `json:"reorderLevel"`
+}
+
+// Decode implments the decoder interface.
+func (app *PatchProduct) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app PatchProduct) Validate() error {
+	up, err := toUpdateProduct(app)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return up.Validate()
+}
+
+func toUpdateProduct(app PatchProduct) (UpdateProduct, error) {
+	var fe errs.FieldErrors
+
+	up := UpdateProduct{
+		Name:         patch.Required(&fe, "name", app.Name),
+		Cost:         patch.Required(&fe, "cost", app.Cost),
+		Quantity:     patch.Required(&fe, "quantity", app.Quantity),
+		ReorderLevel: patch.Required(&fe, "reorderLevel", app.ReorderLevel),
+	}
+
+	if len(fe) > 0 {
+		return UpdateProduct{}, fe
+	}
+
+	return up, nil
+}
+
 func toBusUpdateProduct(app UpdateProduct) (productbus.UpdateProduct, error) {
 	var name *productbus.Name
 	if app.Name != nil {
@@ -140,10 +305,265 @@ func toBusUpdateProduct(app UpdateProduct) (productbus.UpdateProduct, error) {
 	}
 
 	bus := productbus.UpdateProduct{
-		Name:     name,
-		Cost:     app.Cost,
+		Name:         name,
+		Cost:         app.Cost,
+		Quantity:     app.Quantity,
+		ReorderLevel: app.ReorderLevel,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// Movement represents an entry in the stock ledger of a product.
+type Movement struct {
+	ID          string `json:"id"`
+	ProductID   string `json:"productID"`
+	Type        string `json:"type"`
+	Quantity    int    `json:"quantity"`
+	Reason      string `json:"reason"`
+	DateCreated string `json:"dateCreated"`
+}
+
+// Encode implments the encoder interface.
+func (app Movement) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppMovement(mov productbus.Movement) Movement {
+	return Movement{
+		ID:          mov.ID.String(),
+		ProductID:   mov.ProductID.String(),
+		Type:        mov.Type.String(),
+		Quantity:    mov.Quantity,
+		Reason:      mov.Reason,
+		DateCreated: mov.DateCreated.Format(time.RFC3339),
+	}
+}
+
+func toAppMovements(movs []productbus.Movement) []Movement {
+	app := make([]Movement, len(movs))
+	for i, mov := range movs {
+		app[i] = toAppMovement(mov)
+	}
+
+	return app
+}
+
+// NewMovement defines the data needed to record a stock movement.
+type NewMovement struct {
+	Type     string `json:"type" validate:"required"`
+	Quantity int    `json:"quantity" validate:"required"`
+	Reason   string `json:"reason" validate:"required,max=200"`
+}
+
+// Decode implments the decoder interface.
+func (app *NewMovement) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewMovement) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewMovement(app NewMovement) (productbus.NewMovement, error) {
+	typ, err := productbus.ParseMovementType(app.Type)
+	if err != nil {
+		return productbus.NewMovement{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := productbus.NewMovement{
+		Type:     typ,
 		Quantity: app.Quantity,
+		Reason:   app.Reason,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// Price represents an entry in the price timeline of a product. A scheduled
+// price has not been applied yet.
+type Price struct {
+	ID            string  `json:"id"`
+	ProductID     string  `json:"productID"`
+	Cost          float64 `json:"cost"`
+	Scheduled     bool    `json:"scheduled"`
+	DateEffective string  `json:"dateEffective"`
+	DateApplied   string  `json:"dateApplied,omitempty"`
+	DateCreated   string  `json:"dateCreated"`
+}
+
+// Encode implments the encoder interface.
+func (app Price) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppPrice(prc productbus.Price) Price {
+	var dateApplied string
+	if !prc.DateApplied.IsZero() {
+		dateApplied = prc.DateApplied.Format(time.RFC3339)
+	}
+
+	return Price{
+		ID:            prc.ID.String(),
+		ProductID:     prc.ProductID.String(),
+		Cost:          prc.Cost,
+		Scheduled:     prc.DateApplied.IsZero(),
+		DateEffective: prc.DateEffective.Format(time.RFC3339),
+		DateApplied:   dateApplied,
+		DateCreated:   prc.DateCreated.Format(time.RFC3339),
+	}
+}
+
+func toAppPrices(prcs []productbus.Price) []Price {
+	app := make([]Price, len(prcs))
+	for i, prc := range prcs {
+		app[i] = toAppPrice(prc)
+	}
+
+	return app
+}
+
+// NewPrice defines the data needed to schedule a price change.
+type NewPrice struct {
+	Cost          float64 `json:"cost" validate:"gte=0"`
+	DateEffective string  `json:"dateEffective" validate:"required"`
+}
+
+// Decode implments the decoder interface.
+func (app *NewPrice) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewPrice) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewPrice(app NewPrice) (productbus.NewPrice, error) {
+	dateEffective, err := time.Parse(time.RFC3339, app.DateEffective)
+	if err != nil {
+		return productbus.NewPrice{}, fmt.Errorf("parse dateEffective: %w", err)
+	}
+
+	bus := productbus.NewPrice{
+		Cost:          app.Cost,
+		DateEffective: dateEffective,
 	}
 
 	return bus, nil
 }
+
+// =============================================================================
+
+// Reconciliation represents the result of checking a product's quantity
+// against its stock ledger.
+type Reconciliation struct {
+	ProductID      string `json:"productID"`
+	Quantity       int    `json:"quantity"`
+	LedgerQuantity int    `json:"ledgerQuantity"`
+	Balanced       bool   `json:"balanced"`
+}
+
+// Encode implments the encoder interface.
+func (app Reconciliation) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppReconciliation(rec productbus.Reconciliation) Reconciliation {
+	return Reconciliation{
+		ProductID:      rec.ProductID.String(),
+		Quantity:       rec.Quantity,
+		LedgerQuantity: rec.LedgerQuantity,
+		Balanced:       rec.Balanced,
+	}
+}
+
+// =============================================================================
+
+// ProductCategories represents the set of categories a product belongs to.
+type ProductCategories struct {
+	CategoryIDs []string `json:"categoryIDs" validate:"dive,uuid"`
+}
+
+// Encode implments the encoder interface.
+func (app ProductCategories) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implments the decoder interface.
+func (app *ProductCategories) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app ProductCategories) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// ProductTags represents the set of tags attached to a product.
+type ProductTags struct {
+	TagIDs []string `json:"tagIDs" validate:"dive,uuid"`
+}
+
+// Encode implments the encoder interface.
+func (app ProductTags) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implments the decoder interface.
+func (app *ProductTags) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app ProductTags) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toAppIDs(ids []uuid.UUID) []string {
+	app := make([]string, len(ids))
+	for i, id := range ids {
+		app[i] = id.String()
+	}
+
+	return app
+}
+
+func toBusIDs(app []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(app))
+	for i, s := range app {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("parse id[%s]: %w", s, err)
+		}
+		ids[i] = id
+	}
+
+	return ids, nil
+}
@@ -1,16 +1,62 @@
 package productapp
 
 import (
+	"strconv"
+
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
 )
 
 var defaultOrderBy = order.NewBy("product_id", order.ASC)
 
+// searchOrderBy is the default when searching so the best matches come first.
+var searchOrderBy = order.NewBy(productbus.OrderByRank, order.DESC)
+
 var orderByFields = map[string]string{
 	"product_id": productbus.OrderByProductID,
 	"name":       productbus.OrderByName,
 	"cost":       productbus.OrderByCost,
 	"quantity":   productbus.OrderByQuantity,
 	"user_id":    productbus.OrderByUserID,
+	"rank":       productbus.OrderByRank,
+}
+
+// cursors returns the cursors of the pages around the products that were
+// fetched for the page.
+func cursors(pg page.Page, orderBy order.By, prds []productbus.Product) (string, string) {
+	keys := orderBy.Keys()
+
+	return pg.Cursors(orderBy, len(prds), func(i int) page.Key {
+		values := make([]string, len(keys))
+		for j, key := range keys {
+			values[j] = cursorValue(prds[i], key.Field)
+		}
+
+		return page.Key{
+			Values: values,
+			ID:     prds[i].ID.String(),
+		}
+	})
+}
+
+// cursorValue returns the value of the field for the product in the text form
+// the database parses back into the type of the column.
+func cursorValue(prd productbus.Product, field string) string {
+	switch field {
+	case productbus.OrderByProductID:
+		return prd.ID.String()
+	case productbus.OrderByUserID:
+		return prd.UserID.String()
+	case productbus.OrderByName:
+		return prd.Name.String()
+	case productbus.OrderByCost:
+		return strconv.FormatFloat(prd.Cost, 'g', -1, 64)
+	case productbus.OrderByQuantity:
+		return strconv.Itoa(prd.Quantity)
+	case productbus.OrderByRank:
+		return strconv.FormatFloat(prd.Rank, 'g', -1, 64)
+	}
+
+	return ""
 }
@@ -3,29 +3,68 @@ package productapp
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/app/sdk/mid"
 	"github.com/ardanlabs/encore/app/sdk/query"
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
 )
 
 // App manages the set of app layer api functions for the product domain.
 type App struct {
 	productBus *productbus.Business
+	userBus    *userbus.Business
 }
 
 // NewApp constructs a product app API for use.
-func NewApp(productBus *productbus.Business) *App {
+func NewApp(productBus *productbus.Business, userBus *userbus.Business) *App {
 	return &App{
 		productBus: productBus,
+		userBus:    userBus,
 	}
 }
 
+// newWithTx constructs a new App value with the domain apis using a store
+// transaction that was created via middleware, so a product and its stock
+// ledger and price history are written together.
+func (a *App) newWithTx(ctx context.Context) (*App, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	productBus, err := a.productBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := a.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := App{
+		productBus: productBus,
+		userBus:    userBus,
+	}
+
+	return &app, nil
+}
+
 // Create adds a new product to the system.
 func (a *App) Create(ctx context.Context, app NewProduct) (Product, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Product{}, errs.New(errs.Internal, err)
+	}
+
 	np, err := toBusNewProduct(ctx, app)
 	if err != nil {
 		return Product{}, errs.New(errs.InvalidArgument, err)
@@ -41,6 +80,11 @@ func (a *App) Create(ctx context.Context, app NewProduct) (Product, error) {
 
 // Update updates an existing product.
 func (a *App) Update(ctx context.Context, app UpdateProduct) (Product, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Product{}, errs.New(errs.Internal, err)
+	}
+
 	up, err := toBusUpdateProduct(app)
 	if err != nil {
 		return Product{}, errs.New(errs.InvalidArgument, err)
@@ -53,14 +97,32 @@ func (a *App) Update(ctx context.Context, app UpdateProduct) (Product, error) {
 
 	updPrd, err := a.productBus.Update(ctx, prd, up)
 	if err != nil {
+		if errors.Is(err, productbus.ErrNotFound) {
+			return Product{}, errs.New(errs.NotFound, err)
+		}
 		return Product{}, errs.Newf(errs.Internal, "update: productID[%s] up[%+v]: %s", prd.ID, app, err)
 	}
 
 	return toAppProduct(updPrd), nil
 }
 
+// Patch applies a merge patch document to a product.
+func (a *App) Patch(ctx context.Context, app PatchProduct) (Product, error) {
+	up, err := toUpdateProduct(app)
+	if err != nil {
+		return Product{}, errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return a.Update(ctx, up)
+}
+
 // Delete removes a product from the system.
 func (a *App) Delete(ctx context.Context) error {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
 	prd, err := mid.GetProduct(ctx)
 	if err != nil {
 		return errs.Newf(errs.Internal, "productID missing in context: %s", err)
@@ -75,32 +137,94 @@ func (a *App) Delete(ctx context.Context) error {
 
 // Query returns a list of products with paging.
 func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Product], error) {
-	page, err := page.Parse(qp.Page, qp.Rows)
+	page, err := page.ParseQuery(qp.Page, qp.Rows, qp.Cursor)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
 
+	total, err := query.ParseTotal(qp.Total)
+	if err != nil {
+		return query.Result[Product]{}, errs.NewFieldsError("total", err)
+	}
+
 	filter, err := parseFilter(qp)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
 
-	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	defaultOrder := defaultOrderBy
+	if filter.Search != nil {
+		defaultOrder = searchOrderBy
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrder)
+	if err != nil {
+		return query.Result[Product]{}, err
+	}
+
+	if err := page.ValidateOrder(orderBy); err != nil {
+		return query.Result[Product]{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	fields, names, expandUser, err := parseFields(qp)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
 
-	prds, err := a.productBus.Query(ctx, filter, orderBy, page)
+	prds, err := a.productBus.Query(ctx, filter, fields, orderBy, total.Page(page))
 	if err != nil {
 		return query.Result[Product]{}, errs.Newf(errs.Internal, "query: %s", err)
 	}
 
-	total, err := a.productBus.Count(ctx, filter)
+	prds, hasMore := query.Trim(page, prds)
+
+	count, err := query.Count(ctx, total, filter, a.productBus.Count, a.productBus.EstimateCount)
 	if err != nil {
 		return query.Result[Product]{}, errs.Newf(errs.Internal, "count: %s", err)
 	}
 
-	return query.NewResult(toAppProducts(prds), total, page), nil
+	items := toAppProducts(prds)
+
+	if expandUser {
+		if err := a.expandUsers(ctx, prds, items); err != nil {
+			return query.Result[Product]{}, errs.Newf(errs.Internal, "expand: %s", err)
+		}
+	}
+
+	return query.NewResult(items, count, page).WithTotal(total, hasMore).WithCursors(cursors(page, orderBy, prds)).WithFields(names), nil
+}
+
+// expandUsers sets the owner of each product, loading all of the owners with
+// a single query. The items are the app form of the products.
+func (a *App) expandUsers(ctx context.Context, prds []productbus.Product, items []Product) error {
+	var ids []uuid.UUID
+	for _, prd := range prds {
+		if !slices.Contains(ids, prd.UserID) {
+			ids = append(ids, prd.UserID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	usrs, err := a.userBus.QueryByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("querybyids: %w", err)
+	}
+
+	owners := make(map[uuid.UUID]User, len(usrs))
+	for _, usr := range usrs {
+		owners[usr.ID] = toAppUser(usr)
+	}
+
+	for i, prd := range prds {
+		if owner, exists := owners[prd.UserID]; exists {
+			items[i].User = &owner
+		}
+	}
+
+	return nil
 }
 
 // QueryByID returns a product by its Ia.
@@ -112,3 +236,225 @@ func (a *App) QueryByID(ctx context.Context) (Product, error) {
 
 	return toAppProduct(prd), nil
 }
+
+// QueryByIDs returns the products with the specified IDs. Callers that aren't
+// admins only get back the products they own, the rest are reported as missing.
+func (a *App) QueryByIDs(ctx context.Context, app query.BatchGet) (query.Batch[Product], error) {
+	ids, err := app.ParseIDs()
+	if err != nil {
+		return query.Batch[Product]{}, errs.NewFieldsError("ids", err)
+	}
+
+	claims, err := mid.GetClaims(ctx)
+	if err != nil {
+		return query.Batch[Product]{}, errs.Newf(errs.Internal, "querybyids: %s", err)
+	}
+
+	prds, err := a.productBus.QueryByIDs(ctx, ids)
+	if err != nil {
+		return query.Batch[Product]{}, errs.Newf(errs.Internal, "querybyids: %s", err)
+	}
+
+	if !claims.IsAdmin() {
+		prds = slices.DeleteFunc(prds, func(prd productbus.Product) bool {
+			return prd.UserID.String() != claims.Subject
+		})
+	}
+
+	return query.NewBatch(ids, toAppProducts(prds), func(prd Product) string { return prd.ID }), nil
+}
+
+// CreateMovement records a stock movement against a product.
+func (a *App) CreateMovement(ctx context.Context, app NewMovement) (Movement, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Movement{}, errs.New(errs.Internal, err)
+	}
+
+	nm, err := toBusNewMovement(app)
+	if err != nil {
+		return Movement{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	prd, err := mid.GetProduct(ctx)
+	if err != nil {
+		return Movement{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	_, mov, err := a.productBus.CreateMovement(ctx, prd, nm)
+	if err != nil {
+		switch {
+		case errors.Is(err, productbus.ErrInvalidMovement):
+			return Movement{}, errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, productbus.ErrInsufficientStock):
+			return Movement{}, errs.New(errs.FailedPrecondition, err)
+		case errors.Is(err, productbus.ErrNotFound):
+			return Movement{}, errs.New(errs.NotFound, err)
+		}
+		return Movement{}, errs.Newf(errs.Internal, "createmovement: productID[%s] nm[%+v]: %s", prd.ID, app, err)
+	}
+
+	return toAppMovement(mov), nil
+}
+
+// QueryMovements returns the stock ledger of a product with paging.
+func (a *App) QueryMovements(ctx context.Context, qp MovementQueryParams) (query.Result[Movement], error) {
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return query.Result[Movement]{}, err
+	}
+
+	prd, err := mid.GetProduct(ctx)
+	if err != nil {
+		return query.Result[Movement]{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	movs, err := a.productBus.QueryMovements(ctx, prd.ID, page)
+	if err != nil {
+		return query.Result[Movement]{}, errs.Newf(errs.Internal, "querymovements: %s", err)
+	}
+
+	total, err := a.productBus.CountMovements(ctx, prd.ID)
+	if err != nil {
+		return query.Result[Movement]{}, errs.Newf(errs.Internal, "countmovements: %s", err)
+	}
+
+	return query.NewResult(toAppMovements(movs), total, page), nil
+}
+
+// Reconcile checks the quantity of a product against its stock ledger.
+func (a *App) Reconcile(ctx context.Context) (Reconciliation, error) {
+	prd, err := mid.GetProduct(ctx)
+	if err != nil {
+		return Reconciliation{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	rec, err := a.productBus.Reconcile(ctx, prd)
+	if err != nil {
+		return Reconciliation{}, errs.Newf(errs.Internal, "reconcile: productID[%s]: %s", prd.ID, err)
+	}
+
+	return toAppReconciliation(rec), nil
+}
+
+// SchedulePrice schedules a future price change for a product.
+func (a *App) SchedulePrice(ctx context.Context, app NewPrice) (Price, error) {
+	np, err := toBusNewPrice(app)
+	if err != nil {
+		return Price{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	prd, err := mid.GetProduct(ctx)
+	if err != nil {
+		return Price{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	prc, err := a.productBus.SchedulePrice(ctx, prd, np)
+	if err != nil {
+		if errors.Is(err, productbus.ErrInvalidPrice) {
+			return Price{}, errs.New(errs.InvalidArgument, err)
+		}
+		return Price{}, errs.Newf(errs.Internal, "scheduleprice: productID[%s] np[%+v]: %s", prd.ID, app, err)
+	}
+
+	return toAppPrice(prc), nil
+}
+
+// QueryPrices returns the price timeline of a product with paging.
+func (a *App) QueryPrices(ctx context.Context, qp PriceQueryParams) (query.Result[Price], error) {
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return query.Result[Price]{}, err
+	}
+
+	prd, err := mid.GetProduct(ctx)
+	if err != nil {
+		return query.Result[Price]{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	prcs, err := a.productBus.QueryPrices(ctx, prd.ID, page)
+	if err != nil {
+		return query.Result[Price]{}, errs.Newf(errs.Internal, "queryprices: %s", err)
+	}
+
+	total, err := a.productBus.CountPrices(ctx, prd.ID)
+	if err != nil {
+		return query.Result[Price]{}, errs.Newf(errs.Internal, "countprices: %s", err)
+	}
+
+	return query.NewResult(toAppPrices(prcs), total, page), nil
+}
+
+// SetCategories replaces the categories a product belongs to.
+func (a *App) SetCategories(ctx context.Context, app ProductCategories) (ProductCategories, error) {
+	ids, err := toBusIDs(app.CategoryIDs)
+	if err != nil {
+		return ProductCategories{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	prd, err := mid.GetProduct(ctx)
+	if err != nil {
+		return ProductCategories{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	if err := a.productBus.SetCategories(ctx, prd, ids); err != nil {
+		if errors.Is(err, productbus.ErrUnknownCategory) {
+			return ProductCategories{}, errs.New(errs.InvalidArgument, productbus.ErrUnknownCategory)
+		}
+		return ProductCategories{}, errs.Newf(errs.Internal, "setcategories: productID[%s]: %s", prd.ID, err)
+	}
+
+	return a.QueryCategories(ctx)
+}
+
+// QueryCategories returns the categories a product belongs to.
+func (a *App) QueryCategories(ctx context.Context) (ProductCategories, error) {
+	prd, err := mid.GetProduct(ctx)
+	if err != nil {
+		return ProductCategories{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	ids, err := a.productBus.QueryCategoryIDs(ctx, prd.ID)
+	if err != nil {
+		return ProductCategories{}, errs.Newf(errs.Internal, "querycategoryids: productID[%s]: %s", prd.ID, err)
+	}
+
+	return ProductCategories{CategoryIDs: toAppIDs(ids)}, nil
+}
+
+// SetTags replaces the tags attached to a product.
+func (a *App) SetTags(ctx context.Context, app ProductTags) (ProductTags, error) {
+	ids, err := toBusIDs(app.TagIDs)
+	if err != nil {
+		return ProductTags{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	prd, err := mid.GetProduct(ctx)
+	if err != nil {
+		return ProductTags{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	if err := a.productBus.SetTags(ctx, prd, ids); err != nil {
+		if errors.Is(err, productbus.ErrUnknownTag) {
+			return ProductTags{}, errs.New(errs.InvalidArgument, productbus.ErrUnknownTag)
+		}
+		return ProductTags{}, errs.Newf(errs.Internal, "settags: productID[%s]: %s", prd.ID, err)
+	}
+
+	return a.QueryTags(ctx)
+}
+
+// QueryTags returns the tags attached to a product.
+func (a *App) QueryTags(ctx context.Context) (ProductTags, error) {
+	prd, err := mid.GetProduct(ctx)
+	if err != nil {
+		return ProductTags{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	ids, err := a.productBus.QueryTagIDs(ctx, prd.ID)
+	if err != nil {
+		return ProductTags{}, errs.Newf(errs.Internal, "querytagids: productID[%s]: %s", prd.ID, err)
+	}
+
+	return ProductTags{TagIDs: toAppIDs(ids)}, nil
+}
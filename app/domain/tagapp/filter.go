@@ -0,0 +1,25 @@
+package tagapp
+
+import (
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+	"github.com/google/uuid"
+)
+
+func parseFilter(qp QueryParams) (tagbus.QueryFilter, error) {
+	var filter tagbus.QueryFilter
+
+	if qp.ID != "" {
+		id, err := uuid.Parse(qp.ID)
+		if err != nil {
+			return tagbus.QueryFilter{}, errs.NewFieldsError("tag_id", err)
+		}
+		filter.ID = &id
+	}
+
+	if qp.Name != "" {
+		filter.Name = &qp.Name
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,108 @@
+package tagapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+)
+
+// QueryParams represents the set of possible query strings.
+type QueryParams struct {
+	Page    string
+	Rows    string
+	Cursor  string
+	Total   string
+	OrderBy string
+	ID      string
+	Name    string
+}
+
+// =============================================================================
+
+// Tag represents information about an individual tag.
+type Tag struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DateCreated string `json:"dateCreated"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+// Encode implments the encoder interface.
+func (app Tag) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppTag(tag tagbus.Tag) Tag {
+	return Tag{
+		ID:          tag.ID.String(),
+		Name:        tag.Name,
+		DateCreated: tag.DateCreated.Format(time.RFC3339),
+		DateUpdated: tag.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppTags(tags []tagbus.Tag) []Tag {
+	app := make([]Tag, len(tags))
+	for i, tag := range tags {
+		app[i] = toAppTag(tag)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewTag defines the data needed to add a new tag.
+type NewTag struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// Decode implments the decoder interface.
+func (app *NewTag) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewTag) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewTag(app NewTag) tagbus.NewTag {
+	return tagbus.NewTag{
+		Name: app.Name,
+	}
+}
+
+// =============================================================================
+
+// UpdateTag defines the data needed to update a tag.
+type UpdateTag struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=50"`
+}
+
+// Decode implments the decoder interface.
+func (app *UpdateTag) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateTag) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateTag(app UpdateTag) tagbus.UpdateTag {
+	return tagbus.UpdateTag{
+		Name: app.Name,
+	}
+}
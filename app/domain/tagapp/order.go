@@ -0,0 +1,45 @@
+package tagapp
+
+import (
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+)
+
+var defaultOrderBy = order.NewBy("name", order.ASC)
+
+var orderByFields = map[string]string{
+	"tag_id": tagbus.OrderByID,
+	"name":   tagbus.OrderByName,
+}
+
+// cursors returns the cursors of the pages around the tags that were
+// fetched for the page.
+func cursors(pg page.Page, orderBy order.By, tags []tagbus.Tag) (string, string) {
+	keys := orderBy.Keys()
+
+	return pg.Cursors(orderBy, len(tags), func(i int) page.Key {
+		values := make([]string, len(keys))
+		for j, key := range keys {
+			values[j] = cursorValue(tags[i], key.Field)
+		}
+
+		return page.Key{
+			Values: values,
+			ID:     tags[i].ID.String(),
+		}
+	})
+}
+
+// cursorValue returns the value of the field for the tag in the text form the
+// database parses back into the type of the column.
+func cursorValue(tag tagbus.Tag, field string) string {
+	switch field {
+	case tagbus.OrderByID:
+		return tag.ID.String()
+	case tagbus.OrderByName:
+		return tag.Name
+	}
+
+	return ""
+}
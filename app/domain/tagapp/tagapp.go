@@ -0,0 +1,143 @@
+// Package tagapp maintains the app layer api for the tag domain.
+package tagapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
+)
+
+// App manages the set of app layer api functions for the tag domain.
+type App struct {
+	tagBus *tagbus.Business
+}
+
+// NewApp constructs a tag app API for use.
+func NewApp(tagBus *tagbus.Business) *App {
+	return &App{
+		tagBus: tagBus,
+	}
+}
+
+// Create adds a new tag to the system.
+func (a *App) Create(ctx context.Context, app NewTag) (Tag, error) {
+	tag, err := a.tagBus.Create(ctx, toBusNewTag(app))
+	if err != nil {
+		if errors.Is(err, tagbus.ErrUniqueName) {
+			return Tag{}, errs.New(errs.Aborted, tagbus.ErrUniqueName)
+		}
+		return Tag{}, errs.Newf(errs.Internal, "create: tag[%+v]: %s", app, err)
+	}
+
+	return toAppTag(tag), nil
+}
+
+// Update updates an existing tag.
+func (a *App) Update(ctx context.Context, tagID string, app UpdateTag) (Tag, error) {
+	tag, err := a.queryByID(ctx, tagID)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	ut := toBusUpdateTag(app)
+
+	updTag, err := a.tagBus.Update(ctx, tag, ut)
+	if err != nil {
+		if errors.Is(err, tagbus.ErrUniqueName) {
+			return Tag{}, errs.New(errs.Aborted, tagbus.ErrUniqueName)
+		}
+		return Tag{}, errs.Newf(errs.Internal, "update: tagID[%s] ut[%+v]: %s", tag.ID, ut, err)
+	}
+
+	return toAppTag(updTag), nil
+}
+
+// Delete removes a tag from the system.
+func (a *App) Delete(ctx context.Context, tagID string) error {
+	tag, err := a.queryByID(ctx, tagID)
+	if err != nil {
+		return err
+	}
+
+	if err := a.tagBus.Delete(ctx, tag); err != nil {
+		return errs.Newf(errs.Internal, "delete: tagID[%s]: %s", tag.ID, err)
+	}
+
+	return nil
+}
+
+// Query returns a list of tags with paging.
+func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Tag], error) {
+	page, err := page.ParseQuery(qp.Page, qp.Rows, qp.Cursor)
+	if err != nil {
+		return query.Result[Tag]{}, err
+	}
+
+	total, err := query.ParseTotal(qp.Total)
+	if err != nil {
+		return query.Result[Tag]{}, errs.NewFieldsError("total", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return query.Result[Tag]{}, err
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	if err != nil {
+		return query.Result[Tag]{}, err
+	}
+
+	if err := page.ValidateOrder(orderBy); err != nil {
+		return query.Result[Tag]{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	tags, err := a.tagBus.Query(ctx, filter, orderBy, total.Page(page))
+	if err != nil {
+		return query.Result[Tag]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	tags, hasMore := query.Trim(page, tags)
+
+	count, err := query.Count(ctx, total, filter, a.tagBus.Count, a.tagBus.EstimateCount)
+	if err != nil {
+		return query.Result[Tag]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppTags(tags), count, page).WithTotal(total, hasMore).WithCursors(cursors(page, orderBy, tags)), nil
+}
+
+// QueryByID returns a tag by its ID.
+func (a *App) QueryByID(ctx context.Context, tagID string) (Tag, error) {
+	tag, err := a.queryByID(ctx, tagID)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	return toAppTag(tag), nil
+}
+
+// =============================================================================
+
+func (a *App) queryByID(ctx context.Context, tagID string) (tagbus.Tag, error) {
+	id, err := uuid.Parse(tagID)
+	if err != nil {
+		return tagbus.Tag{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	tag, err := a.tagBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, tagbus.ErrNotFound) {
+			return tagbus.Tag{}, errs.New(errs.NotFound, err)
+		}
+		return tagbus.Tag{}, errs.Newf(errs.Internal, "querybyid: tagID[%s]: %s", id, err)
+	}
+
+	return tag, nil
+}
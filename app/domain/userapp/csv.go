@@ -0,0 +1,254 @@
+package userapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ardanlabs/encore/app/sdk/bulk"
+	"github.com/ardanlabs/encore/app/sdk/csvio"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	bbulk "github.com/ardanlabs/encore/business/sdk/bulk"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+// maxImportRows is the most rows an import can hold.
+const maxImportRows = 10000
+
+// importChunk is the number of users added by each transaction of an import.
+// Every user's password is hashed as it's added, so the chunks are kept
+// small.
+const importChunk = 100
+
+// roleSeparator separates the roles held in a single csv value.
+const roleSeparator = ";"
+
+var exportHeader = []string{"id", "name", "email", "roles", "department", "enabled", "dateCreated", "dateUpdated"}
+
+// Export writes every user matching the query params to w as csv. The users
+// are written as they're read, so nothing is written when the params aren't
+// valid.
+func (a *App) Export(ctx context.Context, w io.Writer, qp QueryParams) error {
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	if err != nil {
+		return err
+	}
+
+	cw, err := csvio.NewWriter(w, exportHeader)
+	if err != nil {
+		return errs.Newf(errs.Internal, "export: %s", err)
+	}
+
+	err = a.userBus.QueryEach(ctx, filter, orderBy, func(usr userbus.User) error {
+		app := toAppUser(usr)
+
+		return cw.Write([]string{
+			app.ID,
+			app.Name,
+			app.Email,
+			strings.Join(app.Roles, roleSeparator),
+			app.Department,
+			strconv.FormatBool(app.Enabled),
+			app.DateCreated,
+			app.DateUpdated,
+		})
+	})
+	if err != nil {
+		return errs.Newf(errs.Internal, "export: %s", err)
+	}
+
+	if err := cw.Flush(); err != nil {
+		return errs.Newf(errs.Internal, "export: %s", err)
+	}
+
+	return nil
+}
+
+// Import adds a user for every row of the csv read from r and reports the
+// errors of the rows that couldn't be added. The file needs name, email,
+// roles and password columns and may have department and passwordConfirm
+// columns. Roles are separated by a semicolon. Every chunk of users is added
+// in its own transaction started with bgn, along with the events of its
+// users, and when a chunk fails the error carries the report of the chunks
+// that were added before it.
+func (a *App) Import(ctx context.Context, bgn sqldb.Beginner, r io.Reader) (csvio.Report, error) {
+	cr, err := csvio.NewReader(r, "name", "email", "roles", "password")
+	if err != nil {
+		return csvio.Report{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	var recs []csvio.Record
+	for {
+		rec, err := cr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return csvio.Report{}, errs.New(errs.InvalidArgument, err)
+		}
+
+		if len(recs) == maxImportRows {
+			return csvio.Report{}, errs.Newf(errs.InvalidArgument, "file has more than %d rows", maxImportRows)
+		}
+		recs = append(recs, rec)
+	}
+
+	res := bulk.NewResult(len(recs))
+	rows := make([]int, len(recs))
+
+	var batch bulk.Batch[userbus.NewUser]
+	for i, rec := range recs {
+		rows[i] = rec.Row
+
+		nu, err := toBusNewUserRecord(rec)
+		if err != nil {
+			res.Fail(i, err)
+			continue
+		}
+
+		batch.Add(i, nu)
+	}
+
+	for start := 0; start < len(batch.Items); start += importChunk {
+		end := min(start+importChunk, len(batch.Items))
+
+		chunk := bulk.Batch[userbus.NewUser]{
+			Items:   batch.Items[start:end],
+			Indexes: batch.Indexes[start:end],
+		}
+
+		if err := a.createChunk(ctx, bgn, res, chunk); err != nil {
+			err = fmt.Errorf("import: rows from %d were not imported: %w", rows[chunk.Indexes[0]], err)
+			return csvio.Report{}, errs.NewDetails(errs.Internal, err, csvio.NewReport(res, rows))
+		}
+	}
+
+	return csvio.NewReport(res, rows), nil
+}
+
+// createChunk adds the users of the chunk, leaving out the ones whose email
+// is already taken. The users are added in a transaction that's only
+// committed once every insert succeeds, and a rejected insert is retried in a
+// new one without the user that caused it.
+func (a *App) createChunk(ctx context.Context, bgn sqldb.Beginner, res *bulk.Result, chunk bulk.Batch[userbus.NewUser]) error {
+	for len(chunk.Items) > 0 {
+		usrs, err := a.createMany(ctx, bgn, chunk.Items)
+		if err != nil {
+			if chunk.Drop(res, err, toItemError) {
+				continue
+			}
+			return err
+		}
+
+		for i, usr := range usrs {
+			res.Succeed(chunk.Indexes[i], usr.ID.String())
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// createMany adds the users in a transaction of their own. A user whose
+// email is taken aborts the transaction, so it's reported as the error of
+// its item for the caller to retry without it.
+func (a *App) createMany(ctx context.Context, bgn sqldb.Beginner, nus []userbus.NewUser) ([]userbus.User, error) {
+	tx, err := bgn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin: %w", err)
+	}
+
+	usrs, err := func() ([]userbus.User, error) {
+		userBus, err := a.userBus.NewWithTx(tx)
+		if err != nil {
+			return nil, fmt.Errorf("newwithtx: %w", err)
+		}
+
+		usrs := make([]userbus.User, len(nus))
+		for i, nu := range nus {
+			usr, err := userBus.Create(ctx, nu)
+			if err != nil {
+				if errors.Is(err, userbus.ErrUniqueEmail) {
+					var itemErrs bbulk.Errors
+					itemErrs.Add(i, userbus.ErrUniqueEmail)
+					return nil, itemErrs
+				}
+				return nil, fmt.Errorf("create: item[%d]: %w", i, err)
+			}
+
+			usrs[i] = usr
+		}
+
+		return usrs, nil
+	}()
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return nil, fmt.Errorf("rollback: %w: %w", rbErr, err)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	return usrs, nil
+}
+
+// toItemError reports the business errors of an item against the field they
+// were caused by.
+func toItemError(err error) error {
+	if errors.Is(err, userbus.ErrUniqueEmail) {
+		return errs.NewFieldsError("email", err)
+	}
+
+	return err
+}
+
+// toBusNewUserRecord converts a row of an import into a new user, checking
+// it the same way a new user from the api is checked.
+func toBusNewUserRecord(rec csvio.Record) (userbus.NewUser, error) {
+	var roles []string
+	if v := rec.Get("roles"); v != "" {
+		roles = strings.Split(v, roleSeparator)
+		for i := range roles {
+			roles[i] = strings.TrimSpace(roles[i])
+		}
+	}
+
+	passwordConfirm := rec.Get("passwordConfirm")
+	if passwordConfirm == "" {
+		passwordConfirm = rec.Get("password")
+	}
+
+	app := NewUser{
+		Name:            rec.Get("name"),
+		Email:           rec.Get("email"),
+		Roles:           roles,
+		Department:      rec.Get("department"),
+		Password:        rec.Get("password"),
+		PasswordConfirm: passwordConfirm,
+	}
+
+	if err := errs.Check(app); err != nil {
+		return userbus.NewUser{}, err
+	}
+
+	nu, err := toBusNewUser(app)
+	if err != nil {
+		return userbus.NewUser{}, err
+	}
+
+	return nu, nil
+}
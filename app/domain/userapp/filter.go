@@ -6,19 +6,18 @@ import (
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/filter"
 	"github.com/google/uuid"
 )
 
 func parseFilter(qp QueryParams) (userbus.QueryFilter, error) {
 	var filter userbus.QueryFilter
 
-	if qp.ID != "" {
-		id, err := uuid.Parse(qp.ID)
-		if err != nil {
-			return userbus.QueryFilter{}, errs.NewFieldsError("user_id", err)
-		}
-		filter.ID = &id
+	ids, err := idField.Conditions(idParams(qp))
+	if err != nil {
+		return userbus.QueryFilter{}, errs.NewFieldsError(idField.Name, err)
 	}
+	filter.ID = ids
 
 	if qp.Name != "" {
 		name, err := userbus.ParseName(qp.Name)
@@ -54,3 +53,18 @@ func parseFilter(qp QueryParams) (userbus.QueryFilter, error) {
 
 	return filter, nil
 }
+
+// The fields that can be compared with the filter operators.
+var idField = filter.Field[uuid.UUID]{
+	Name:      "id",
+	Operators: filter.Identity,
+	Parse:     uuid.Parse,
+}
+
+func idParams(qp QueryParams) filter.Params {
+	return filter.Params{
+		filter.Operators.EQ: qp.ID,
+		filter.Operators.NE: qp.IDNE,
+		filter.Operators.IN: qp.IDIn,
+	}
+}
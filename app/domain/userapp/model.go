@@ -6,15 +6,21 @@ import (
 	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/patch"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 )
 
-// QueryParams represents the set of possible query strings.
+// QueryParams represents the set of possible query strings. Fields that
+// support the filter operators take them in brackets, such as id[in].
 type QueryParams struct {
 	Page             string
 	Rows             string
+	Cursor           string
+	Total            string
 	OrderBy          string
 	ID               string
+	IDNE             string `query:"id[ne]"`
+	IDIn             string `query:"id[in]"`
 	Name             string
 	Email            string
 	StartCreatedDate string
@@ -173,6 +179,47 @@ func (app UpdateUser) Validate() error {
 	return nil
 }
 
+// PatchUser defines the data needed to patch a user with a merge patch
+// document. Members left out of the document are not changed. Department is
+// the only member that can be set to null, which clears it.
+type PatchUser struct {
+	Name            patch.Field[string] `json:"name"`
+	Email           patch.Field[string] `json:"email"`
+	Department      patch.Field[string] `json:"department"`
+	Password        patch.Field[string] `json:"password"`
+	PasswordConfirm patch.Field[string] `json:"passwordConfirm"`
+	Enabled         patch.Field[bool]   `json:"enabled"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app PatchUser) Validate() error {
+	uu, err := toUpdateUser(app)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return uu.Validate()
+}
+
+func toUpdateUser(app PatchUser) (UpdateUser, error) {
+	var fe errs.FieldErrors
+
+	uu := UpdateUser{
+		Name:            patch.Required(&fe, "name", app.Name),
+		Email:           patch.Required(&fe, "email", app.Email),
+		Department:      app.Department.Nullable(),
+		Password:        patch.Required(&fe, "password", app.Password),
+		PasswordConfirm: patch.Required(&fe, "passwordConfirm", app.PasswordConfirm),
+		Enabled:         patch.Required(&fe, "enabled", app.Enabled),
+	}
+
+	if len(fe) > 0 {
+		return UpdateUser{}, fe
+	}
+
+	return uu, nil
+}
+
 func toBusUpdateUser(app UpdateUser) (userbus.UpdateUser, error) {
 	var addr *mail.Address
 	if app.Email != nil {
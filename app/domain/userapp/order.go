@@ -1,8 +1,12 @@
 package userapp
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
 )
 
 var defaultOrderBy = order.NewBy("user_id", order.ASC)
@@ -14,3 +18,50 @@ var orderByFields = map[string]string{
 	"roles":   userbus.OrderByRoles,
 	"enabled": userbus.OrderByEnabled,
 }
+
+// cursors returns the cursors of the pages around the users that were
+// fetched for the page.
+func cursors(pg page.Page, orderBy order.By, usrs []userbus.User) (string, string) {
+	keys := orderBy.Keys()
+
+	return pg.Cursors(orderBy, len(usrs), func(i int) page.Key {
+		values := make([]string, len(keys))
+		for j, key := range keys {
+			values[j] = cursorValue(usrs[i], key.Field)
+		}
+
+		return page.Key{
+			Values: values,
+			ID:     usrs[i].ID.String(),
+		}
+	})
+}
+
+// cursorValue returns the value of the field for the user in the text form the
+// database parses back into the type of the column.
+func cursorValue(usr userbus.User, field string) string {
+	switch field {
+	case userbus.OrderByID:
+		return usr.ID.String()
+	case userbus.OrderByName:
+		return usr.Name.String()
+	case userbus.OrderByEmail:
+		return usr.Email.Address
+	case userbus.OrderByRoles:
+		return rolesArray(usr.Roles)
+	case userbus.OrderByEnabled:
+		return strconv.FormatBool(usr.Enabled)
+	}
+
+	return ""
+}
+
+// rolesArray formats the roles as a postgres array literal.
+func rolesArray(roles []userbus.Role) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+
+	return "{" + strings.Join(names, ",") + "}"
+}
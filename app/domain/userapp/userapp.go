@@ -4,6 +4,7 @@ package userapp
 import (
 	"context"
 	"errors"
+	"slices"
 
 	"github.com/ardanlabs/encore/app/sdk/auth"
 	"github.com/ardanlabs/encore/app/sdk/errs"
@@ -35,8 +36,35 @@ func NewAppWithAuth(userBus *userbus.Business, ath *auth.Auth) *App {
 	}
 }
 
+// newWithTx constructs a new App value with the domain apis using a store
+// transaction that was created via middleware, so the events for the changes
+// made are only sent if they are committed.
+func (a *App) newWithTx(ctx context.Context) (*App, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := a.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := App{
+		userBus: userBus,
+		auth:    a.auth,
+	}
+
+	return &app, nil
+}
+
 // Create adds a new user to the system.
 func (a *App) Create(ctx context.Context, app NewUser) (User, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return User{}, errs.New(errs.Internal, err)
+	}
+
 	nc, err := toBusNewUser(app)
 	if err != nil {
 		return User{}, errs.New(errs.InvalidArgument, err)
@@ -55,6 +83,11 @@ func (a *App) Create(ctx context.Context, app NewUser) (User, error) {
 
 // Update updates an existing user.
 func (a *App) Update(ctx context.Context, app UpdateUser) (User, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return User{}, errs.New(errs.Internal, err)
+	}
+
 	uu, err := toBusUpdateUser(app)
 	if err != nil {
 		return User{}, errs.New(errs.InvalidArgument, err)
@@ -73,8 +106,23 @@ func (a *App) Update(ctx context.Context, app UpdateUser) (User, error) {
 	return toAppUser(updUsr), nil
 }
 
+// Patch applies a merge patch document to a user.
+func (a *App) Patch(ctx context.Context, app PatchUser) (User, error) {
+	uu, err := toUpdateUser(app)
+	if err != nil {
+		return User{}, errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return a.Update(ctx, uu)
+}
+
 // UpdateRole updates an existing user's role.
 func (a *App) UpdateRole(ctx context.Context, app UpdateUserRole) (User, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return User{}, errs.New(errs.Internal, err)
+	}
+
 	uu, err := toBusUpdateUserRole(app)
 	if err != nil {
 		return User{}, errs.New(errs.InvalidArgument, err)
@@ -95,6 +143,11 @@ func (a *App) UpdateRole(ctx context.Context, app UpdateUserRole) (User, error)
 
 // Delete removes a user from the system.
 func (a *App) Delete(ctx context.Context) error {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
 	usr, err := mid.GetUser(ctx)
 	if err != nil {
 		return errs.Newf(errs.Internal, "userID missing in context: %s", err)
@@ -109,11 +162,16 @@ func (a *App) Delete(ctx context.Context) error {
 
 // Query returns a list of users with paging.
 func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[User], error) {
-	page, err := page.Parse(qp.Page, qp.Rows)
+	page, err := page.ParseQuery(qp.Page, qp.Rows, qp.Cursor)
 	if err != nil {
 		return query.Result[User]{}, err
 	}
 
+	total, err := query.ParseTotal(qp.Total)
+	if err != nil {
+		return query.Result[User]{}, errs.NewFieldsError("total", err)
+	}
+
 	filter, err := parseFilter(qp)
 	if err != nil {
 		return query.Result[User]{}, err
@@ -124,17 +182,23 @@ func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[User], er
 		return query.Result[User]{}, err
 	}
 
-	usrs, err := a.userBus.Query(ctx, filter, orderBy, page)
+	if err := page.ValidateOrder(orderBy); err != nil {
+		return query.Result[User]{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	usrs, err := a.userBus.Query(ctx, filter, orderBy, total.Page(page))
 	if err != nil {
 		return query.Result[User]{}, errs.Newf(errs.Internal, "query: %s", err)
 	}
 
-	total, err := a.userBus.Count(ctx, filter)
+	usrs, hasMore := query.Trim(page, usrs)
+
+	count, err := query.Count(ctx, total, filter, a.userBus.Count, a.userBus.EstimateCount)
 	if err != nil {
 		return query.Result[User]{}, errs.Newf(errs.Internal, "count: %s", err)
 	}
 
-	return query.NewResult(toAppUsers(usrs), total, page), nil
+	return query.NewResult(toAppUsers(usrs), count, page).WithTotal(total, hasMore).WithCursors(cursors(page, orderBy, usrs)), nil
 }
 
 // QueryByID returns a user by its Ia.
@@ -146,3 +210,30 @@ func (a *App) QueryByID(ctx context.Context) (User, error) {
 
 	return toAppUser(usr), nil
 }
+
+// QueryByIDs returns the users with the specified IDs. Callers that aren't
+// admins only get back the users are theirs, the rest are reported as missing.
+func (a *App) QueryByIDs(ctx context.Context, app query.BatchGet) (query.Batch[User], error) {
+	ids, err := app.ParseIDs()
+	if err != nil {
+		return query.Batch[User]{}, errs.NewFieldsError("ids", err)
+	}
+
+	claims, err := mid.GetClaims(ctx)
+	if err != nil {
+		return query.Batch[User]{}, errs.Newf(errs.Internal, "querybyids: %s", err)
+	}
+
+	usrs, err := a.userBus.QueryByIDs(ctx, ids)
+	if err != nil {
+		return query.Batch[User]{}, errs.Newf(errs.Internal, "querybyids: %s", err)
+	}
+
+	if !claims.IsAdmin() {
+		usrs = slices.DeleteFunc(usrs, func(usr userbus.User) bool {
+			return usr.ID.String() != claims.Subject
+		})
+	}
+
+	return query.NewBatch(ids, toAppUsers(usrs), func(usr User) string { return usr.ID }), nil
+}
@@ -2,24 +2,24 @@ package vproductapp
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
+	"github.com/ardanlabs/encore/business/sdk/filter"
 	"github.com/google/uuid"
 )
 
 func parseFilter(qp QueryParams) (vproductbus.QueryFilter, error) {
 	var filter vproductbus.QueryFilter
 
-	if qp.ID != "" {
-		id, err := uuid.Parse(qp.ID)
-		if err != nil {
-			return vproductbus.QueryFilter{}, errs.NewFieldsError("product_id", err)
-		}
-		filter.ID = &id
+	ids, err := idField.Conditions(idParams(qp))
+	if err != nil {
+		return vproductbus.QueryFilter{}, errs.NewFieldsError(idField.Name, err)
 	}
+	filter.ID = ids
 
 	if qp.Name != "" {
 		name, err := productbus.ParseName(qp.Name)
@@ -29,22 +29,17 @@ func parseFilter(qp QueryParams) (vproductbus.QueryFilter, error) {
 		filter.Name = &name
 	}
 
-	if qp.Cost != "" {
-		cst, err := strconv.ParseFloat(qp.Cost, 64)
-		if err != nil {
-			return vproductbus.QueryFilter{}, errs.NewFieldsError("cost", err)
-		}
-		filter.Cost = &cst
+	costs, err := costField.Conditions(costParams(qp))
+	if err != nil {
+		return vproductbus.QueryFilter{}, errs.NewFieldsError(costField.Name, err)
 	}
+	filter.Cost = costs
 
-	if qp.Quantity != "" {
-		qua, err := strconv.ParseInt(qp.Quantity, 10, 64)
-		if err != nil {
-			return vproductbus.QueryFilter{}, errs.NewFieldsError("quantity", err)
-		}
-		i := int(qua)
-		filter.Quantity = &i
+	quantities, err := quantityField.Conditions(quantityParams(qp))
+	if err != nil {
+		return vproductbus.QueryFilter{}, errs.NewFieldsError(quantityField.Name, err)
 	}
+	filter.Quantity = quantities
 
 	if qp.Name != "" {
 		name, err := userbus.ParseName(qp.Name)
@@ -54,5 +49,81 @@ func parseFilter(qp QueryParams) (vproductbus.QueryFilter, error) {
 		filter.UserName = &name
 	}
 
+	// Inactive products are left out unless they are asked for. A value of
+	// "all" returns products regardless of their status.
+	switch qp.Active {
+	case "":
+		active := true
+		filter.Active = &active
+
+	case "all":
+
+	default:
+		active, err := strconv.ParseBool(qp.Active)
+		if err != nil {
+			return vproductbus.QueryFilter{}, errs.NewFieldsError("active", err)
+		}
+		filter.Active = &active
+	}
+
+	if q := strings.TrimSpace(qp.Q); q != "" {
+		filter.Search = &q
+	}
+
 	return filter, nil
 }
+
+// The fields that can be compared with the filter operators.
+var (
+	idField = filter.Field[uuid.UUID]{
+		Name:      "id",
+		Operators: filter.Identity,
+		Parse:     uuid.Parse,
+	}
+
+	costField = filter.Field[float64]{
+		Name:      "cost",
+		Operators: filter.Ordered,
+		Parse:     filter.Float,
+	}
+
+	quantityField = filter.Field[int]{
+		Name:      "quantity",
+		Operators: filter.Ordered,
+		Parse:     filter.Int,
+	}
+)
+
+func idParams(qp QueryParams) filter.Params {
+	return filter.Params{
+		filter.Operators.EQ: qp.ID,
+		filter.Operators.NE: qp.IDNE,
+		filter.Operators.IN: qp.IDIn,
+	}
+}
+
+func costParams(qp QueryParams) filter.Params {
+	return filter.Params{
+		filter.Operators.EQ:      qp.Cost,
+		filter.Operators.NE:      qp.CostNE,
+		filter.Operators.GT:      qp.CostGT,
+		filter.Operators.GTE:     qp.CostGTE,
+		filter.Operators.LT:      qp.CostLT,
+		filter.Operators.LTE:     qp.CostLTE,
+		filter.Operators.IN:      qp.CostIn,
+		filter.Operators.BETWEEN: qp.CostBetween,
+	}
+}
+
+func quantityParams(qp QueryParams) filter.Params {
+	return filter.Params{
+		filter.Operators.EQ:      qp.Quantity,
+		filter.Operators.NE:      qp.QuantityNE,
+		filter.Operators.GT:      qp.QuantityGT,
+		filter.Operators.GTE:     qp.QuantityGTE,
+		filter.Operators.LT:      qp.QuantityLT,
+		filter.Operators.LTE:     qp.QuantityLTE,
+		filter.Operators.IN:      qp.QuantityIn,
+		filter.Operators.BETWEEN: qp.QuantityBetween,
+	}
+}
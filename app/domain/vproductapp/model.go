@@ -7,16 +7,37 @@ import (
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
 )
 
-// QueryParams represents the set of possible query strings.
+// QueryParams represents the set of possible query strings. Fields that
+// support the filter operators take them in brackets, such as cost[gte].
 type QueryParams struct {
-	Page     string
-	Rows     string
-	OrderBy  string
-	ID       string
-	Name     string
-	Cost     string
-	Quantity string
-	UserName string
+	Page            string
+	Rows            string
+	Cursor          string
+	Total           string
+	OrderBy         string
+	ID              string
+	IDNE            string `query:"id[ne]"`
+	IDIn            string `query:"id[in]"`
+	Name            string
+	Cost            string
+	CostNE          string `query:"cost[ne]"`
+	CostGT          string `query:"cost[gt]"`
+	CostGTE         string `query:"cost[gte]"`
+	CostLT          string `query:"cost[lt]"`
+	CostLTE         string `query:"cost[lte]"`
+	CostIn          string `query:"cost[in]"`
+	CostBetween     string `query:"cost[between]"`
+	Quantity        string
+	QuantityNE      string `query:"quantity[ne]"`
+	QuantityGT      string `query:"quantity[gt]"`
+	QuantityGTE     string `query:"quantity[gte]"`
+	QuantityLT      string `query:"quantity[lt]"`
+	QuantityLTE     string `query:"quantity[lte]"`
+	QuantityIn      string `query:"quantity[in]"`
+	QuantityBetween string `query:"quantity[between]"`
+	UserName        string
+	Active          string
+	Q               string
 }
 
 // =============================================================================
@@ -32,6 +53,7 @@ type Product struct {
 	DateCreated string  `json:"dateCreated"`
 	DateUpdated string  `json:"dateUpdated"`
 	UserName    string  `json:"userName"`
+	Snippet     string  `json:"snippet,omitempty"`
 }
 
 // Encode implments the encoder interface.
@@ -50,6 +72,7 @@ func toAppProduct(prd vproductbus.Product) Product {
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
 		UserName:    prd.UserName.String(),
+		Snippet:     prd.Snippet,
 	}
 }
 
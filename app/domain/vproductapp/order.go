@@ -1,12 +1,18 @@
 package vproductapp
 
 import (
+	"strconv"
+
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
 )
 
 var defaultOrderBy = order.NewBy("product_id", order.ASC)
 
+// searchOrderBy is the default when searching so the best matches come first.
+var searchOrderBy = order.NewBy(vproductbus.OrderByRank, order.DESC)
+
 var orderByFields = map[string]string{
 	"product_id": vproductbus.OrderByProductID,
 	"user_id":    vproductbus.OrderByUserID,
@@ -14,4 +20,46 @@ var orderByFields = map[string]string{
 	"cost":       vproductbus.OrderByCost,
 	"quantity":   vproductbus.OrderByQuantity,
 	"user_name":  vproductbus.OrderByUserName,
+	"rank":       vproductbus.OrderByRank,
+}
+
+// cursors returns the cursors of the pages around the products that were
+// fetched for the page.
+func cursors(pg page.Page, orderBy order.By, prds []vproductbus.Product) (string, string) {
+	keys := orderBy.Keys()
+
+	return pg.Cursors(orderBy, len(prds), func(i int) page.Key {
+		values := make([]string, len(keys))
+		for j, key := range keys {
+			values[j] = cursorValue(prds[i], key.Field)
+		}
+
+		return page.Key{
+			Values: values,
+			ID:     prds[i].ID.String(),
+		}
+	})
+}
+
+// cursorValue returns the value of the field for the product in the text form
+// the database parses back into the type of the column.
+func cursorValue(prd vproductbus.Product, field string) string {
+	switch field {
+	case vproductbus.OrderByProductID:
+		return prd.ID.String()
+	case vproductbus.OrderByUserID:
+		return prd.UserID.String()
+	case vproductbus.OrderByName:
+		return prd.Name.String()
+	case vproductbus.OrderByCost:
+		return strconv.FormatFloat(prd.Cost, 'g', -1, 64)
+	case vproductbus.OrderByQuantity:
+		return strconv.Itoa(prd.Quantity)
+	case vproductbus.OrderByUserName:
+		return prd.UserName.String()
+	case vproductbus.OrderByRank:
+		return strconv.FormatFloat(prd.Rank, 'g', -1, 64)
+	}
+
+	return ""
 }
@@ -25,30 +25,46 @@ func NewApp(vproductBus *vproductbus.Business) *App {
 
 // Query returns a list of products with paging.
 func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Product], error) {
-	page, err := page.Parse(qp.Page, qp.Rows)
+	page, err := page.ParseQuery(qp.Page, qp.Rows, qp.Cursor)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
 
+	total, err := query.ParseTotal(qp.Total)
+	if err != nil {
+		return query.Result[Product]{}, errs.NewFieldsError("total", err)
+	}
+
 	filter, err := parseFilter(qp)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
 
-	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	defaultOrder := defaultOrderBy
+	if filter.Search != nil {
+		defaultOrder = searchOrderBy
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrder)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
 
-	prds, err := a.vproductBus.Query(ctx, filter, orderBy, page)
+	if err := page.ValidateOrder(orderBy); err != nil {
+		return query.Result[Product]{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	prds, err := a.vproductBus.Query(ctx, filter, orderBy, total.Page(page))
 	if err != nil {
 		return query.Result[Product]{}, errs.Newf(errs.Internal, "query: %s", err)
 	}
 
-	total, err := a.vproductBus.Count(ctx, filter)
+	prds, hasMore := query.Trim(page, prds)
+
+	count, err := query.Count(ctx, total, filter, a.vproductBus.Count, a.vproductBus.EstimateCount)
 	if err != nil {
 		return query.Result[Product]{}, errs.Newf(errs.Internal, "count: %s", err)
 	}
 
-	return query.NewResult(toAppProducts(prds), total, page), nil
+	return query.NewResult(toAppProducts(prds), count, page).WithTotal(total, hasMore).WithCursors(cursors(page, orderBy, prds)), nil
 }
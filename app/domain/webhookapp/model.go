@@ -0,0 +1,199 @@
+package webhookapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
+	"github.com/ardanlabs/encore/business/domain/webhookbus/sender"
+	"github.com/google/uuid"
+)
+
+// DeliveryQueryParams represents the set of possible query strings when
+// listing the deliveries made to a webhook.
+type DeliveryQueryParams struct {
+	Page string
+	Rows string
+}
+
+// =============================================================================
+
+// Webhook represents information about an individual webhook. The secret is
+// only returned when the webhook is created.
+type Webhook struct {
+	ID          string   `json:"id"`
+	UserID      string   `json:"userID"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret,omitempty"`
+	Events      []string `json:"events"`
+	Enabled     bool     `json:"enabled"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+// Encode implments the encoder interface.
+func (app Webhook) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppWebhook(wh webhookbus.Webhook) Webhook {
+	return Webhook{
+		ID:          wh.ID.String(),
+		UserID:      wh.UserID.String(),
+		URL:         wh.URL,
+		Events:      wh.Events,
+		Enabled:     wh.Enabled,
+		DateCreated: wh.DateCreated.Format(time.RFC3339),
+		DateUpdated: wh.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppWebhooks(whs []webhookbus.Webhook) []Webhook {
+	app := make([]Webhook, len(whs))
+	for i, wh := range whs {
+		app[i] = toAppWebhook(wh)
+	}
+
+	return app
+}
+
+// Webhooks represents the webhooks registered by a user.
+type Webhooks struct {
+	Items []Webhook `json:"items"`
+}
+
+// Encode implments the encoder interface.
+func (app Webhooks) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// =============================================================================
+
+// NewWebhook defines the data needed to add a new webhook.
+type NewWebhook struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Events []string `json:"events" validate:"required,min=1"`
+}
+
+// Decode implments the decoder interface.
+func (app *NewWebhook) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean. The url has to
+// be one deliveries can be sent to.
+func (app NewWebhook) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	if err := sender.CheckURL(app.URL); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: url: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewWebhook(app NewWebhook, userID uuid.UUID) webhookbus.NewWebhook {
+	return webhookbus.NewWebhook{
+		UserID: userID,
+		URL:    app.URL,
+		Events: app.Events,
+	}
+}
+
+// =============================================================================
+
+// UpdateWebhook defines the data needed to update a webhook.
+type UpdateWebhook struct {
+	URL     *string  `json:"url" validate:"omitempty,url"`
+	Events  []string `json:"events" validate:"omitempty,min=1"`
+	Enabled *bool    `json:"enabled"`
+}
+
+// Decode implments the decoder interface.
+func (app *UpdateWebhook) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks the data in the model is considered clean. A new url has
+// to be one deliveries can be sent to.
+func (app UpdateWebhook) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	if app.URL != nil {
+		if err := sender.CheckURL(*app.URL); err != nil {
+			return errs.Newf(errs.InvalidArgument, "validate: url: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func toBusUpdateWebhook(app UpdateWebhook) webhookbus.UpdateWebhook {
+	return webhookbus.UpdateWebhook{
+		URL:     app.URL,
+		Events:  app.Events,
+		Enabled: app.Enabled,
+	}
+}
+
+// =============================================================================
+
+// Delivery represents the sending of an event to a webhook.
+type Delivery struct {
+	ID          string          `json:"id"`
+	WebhookID   string          `json:"webhookID"`
+	EventID     string          `json:"eventID"`
+	EventType   string          `json:"eventType"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	StatusCode  int             `json:"statusCode,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	DateNext    string          `json:"dateNext,omitempty"`
+	DateCreated string          `json:"dateCreated"`
+	DateUpdated string          `json:"dateUpdated"`
+}
+
+// Encode implments the encoder interface.
+func (app Delivery) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppDelivery(dlv webhookbus.Delivery) Delivery {
+	var dateNext string
+	if !dlv.DateNext.IsZero() {
+		dateNext = dlv.DateNext.Format(time.RFC3339)
+	}
+
+	return Delivery{
+		ID:          dlv.ID.String(),
+		WebhookID:   dlv.WebhookID.String(),
+		EventID:     dlv.EventID.String(),
+		EventType:   dlv.EventType,
+		Payload:     dlv.Payload,
+		Status:      dlv.Status.String(),
+		Attempts:    dlv.Attempts,
+		StatusCode:  dlv.StatusCode,
+		Error:       dlv.Error,
+		DateNext:    dateNext,
+		DateCreated: dlv.DateCreated.Format(time.RFC3339),
+		DateUpdated: dlv.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppDeliveries(dlvs []webhookbus.Delivery) []Delivery {
+	app := make([]Delivery, len(dlvs))
+	for i, dlv := range dlvs {
+		app[i] = toAppDelivery(dlv)
+	}
+
+	return app
+}
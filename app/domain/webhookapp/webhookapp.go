@@ -0,0 +1,169 @@
+// Package webhookapp maintains the app layer api for the webhook domain.
+package webhookapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
+)
+
+// App manages the set of app layer api functions for the webhook domain.
+type App struct {
+	webhookBus *webhookbus.Business
+}
+
+// NewApp constructs a webhook app API for use.
+func NewApp(webhookBus *webhookbus.Business) *App {
+	return &App{
+		webhookBus: webhookBus,
+	}
+}
+
+// Create registers a new webhook for the caller. The response holds the
+// secret used to sign the deliveries, which is never returned again.
+func (a *App) Create(ctx context.Context, app NewWebhook) (Webhook, error) {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return Webhook{}, errs.Newf(errs.Internal, "getuserid: %s", err)
+	}
+
+	wh, err := a.webhookBus.Create(ctx, toBusNewWebhook(app, userID))
+	if err != nil {
+		if errors.Is(err, webhookbus.ErrInvalidEvent) {
+			return Webhook{}, errs.New(errs.InvalidArgument, err)
+		}
+		return Webhook{}, errs.Newf(errs.Internal, "create: wh[%+v]: %s", app, err)
+	}
+
+	resp := toAppWebhook(wh)
+	resp.Secret = wh.Secret
+
+	return resp, nil
+}
+
+// Update updates an existing webhook.
+func (a *App) Update(ctx context.Context, app UpdateWebhook) (Webhook, error) {
+	wh, err := mid.GetWebhook(ctx)
+	if err != nil {
+		return Webhook{}, errs.Newf(errs.Internal, "webhook missing in context: %s", err)
+	}
+
+	updWh, err := a.webhookBus.Update(ctx, wh, toBusUpdateWebhook(app))
+	if err != nil {
+		if errors.Is(err, webhookbus.ErrInvalidEvent) {
+			return Webhook{}, errs.New(errs.InvalidArgument, err)
+		}
+		return Webhook{}, errs.Newf(errs.Internal, "update: webhookID[%s] uw[%+v]: %s", wh.ID, app, err)
+	}
+
+	return toAppWebhook(updWh), nil
+}
+
+// Delete removes a webhook along with its deliveries.
+func (a *App) Delete(ctx context.Context) error {
+	wh, err := mid.GetWebhook(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "webhook missing in context: %s", err)
+	}
+
+	if err := a.webhookBus.Delete(ctx, wh); err != nil {
+		return errs.Newf(errs.Internal, "delete: webhookID[%s]: %s", wh.ID, err)
+	}
+
+	return nil
+}
+
+// Query returns the webhooks registered by the caller.
+func (a *App) Query(ctx context.Context) (Webhooks, error) {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return Webhooks{}, errs.Newf(errs.Internal, "getuserid: %s", err)
+	}
+
+	whs, err := a.webhookBus.QueryByUserID(ctx, userID)
+	if err != nil {
+		return Webhooks{}, errs.Newf(errs.Internal, "querybyuserid: userID[%s]: %s", userID, err)
+	}
+
+	return Webhooks{Items: toAppWebhooks(whs)}, nil
+}
+
+// QueryByID returns a webhook by its ID.
+func (a *App) QueryByID(ctx context.Context) (Webhook, error) {
+	wh, err := mid.GetWebhook(ctx)
+	if err != nil {
+		return Webhook{}, errs.Newf(errs.Internal, "querybyid: %s", err)
+	}
+
+	return toAppWebhook(wh), nil
+}
+
+// QueryDeliveries returns the delivery log of a webhook, newest first, with
+// paging.
+func (a *App) QueryDeliveries(ctx context.Context, qp DeliveryQueryParams) (query.Result[Delivery], error) {
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return query.Result[Delivery]{}, err
+	}
+
+	wh, err := mid.GetWebhook(ctx)
+	if err != nil {
+		return query.Result[Delivery]{}, errs.Newf(errs.Internal, "webhook missing in context: %s", err)
+	}
+
+	dlvs, err := a.webhookBus.QueryDeliveries(ctx, wh.ID, page)
+	if err != nil {
+		return query.Result[Delivery]{}, errs.Newf(errs.Internal, "querydeliveries: %s", err)
+	}
+
+	total, err := a.webhookBus.CountDeliveries(ctx, wh.ID)
+	if err != nil {
+		return query.Result[Delivery]{}, errs.Newf(errs.Internal, "countdeliveries: %s", err)
+	}
+
+	return query.NewResult(toAppDeliveries(dlvs), total, page), nil
+}
+
+// Replay sends a failed delivery to the webhook again and returns the new
+// delivery.
+func (a *App) Replay(ctx context.Context, deliveryID string) (Delivery, error) {
+	wh, err := mid.GetWebhook(ctx)
+	if err != nil {
+		return Delivery{}, errs.Newf(errs.Internal, "webhook missing in context: %s", err)
+	}
+
+	id, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return Delivery{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	dlv, err := a.webhookBus.QueryDeliveryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, webhookbus.ErrDeliveryNotFound) {
+			return Delivery{}, errs.New(errs.NotFound, webhookbus.ErrDeliveryNotFound)
+		}
+		return Delivery{}, errs.Newf(errs.Internal, "querydeliverybyid: deliveryID[%s]: %s", id, err)
+	}
+
+	// A delivery of another webhook is reported as missing so the route
+	// can't be used to find out about the webhooks of other users.
+	if dlv.WebhookID != wh.ID {
+		return Delivery{}, errs.New(errs.NotFound, webhookbus.ErrDeliveryNotFound)
+	}
+
+	rpl, err := a.webhookBus.Replay(ctx, dlv)
+	if err != nil {
+		if errors.Is(err, webhookbus.ErrNotFailed) {
+			return Delivery{}, errs.New(errs.FailedPrecondition, err)
+		}
+		return Delivery{}, errs.Newf(errs.Internal, "replay: deliveryID[%s]: %s", dlv.ID, err)
+	}
+
+	return toAppDelivery(rpl), nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -29,6 +30,11 @@ type Claims struct {
 	Roles []string `json:"roles"`
 }
 
+// IsAdmin reports whether the claims hold the admin role.
+func (c Claims) IsAdmin() bool {
+	return slices.Contains(c.Roles, userbus.Roles.Admin.String())
+}
+
 // KeyLookup declares a method set of behavior for looking up
 // private and public keys for JWT use. The return could be a
 // PEM encoded string or a JWS based key.
@@ -156,10 +162,18 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 // none of the input roles are within the user's claims, we return an error
 // otherwise the user is authorized.
 func (a *Auth) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, rule string) error {
+	return a.AuthorizeMember(ctx, claims, userID, "", rule)
+}
+
+// AuthorizeMember works like Authorize but also takes the role the user holds
+// as a member of the resource being accessed, such as a shared home. An empty
+// role means the user is not a member.
+func (a *Auth) AuthorizeMember(ctx context.Context, claims Claims, userID uuid.UUID, role string, rule string) error {
 	input := map[string]any{
 		"Roles":   claims.Roles,
 		"Subject": claims.Subject,
 		"UserID":  userID,
+		"Role":    role,
 	}
 
 	if err := a.opaPolicyEvaluation(ctx, regoAuthorization, rule, input); err != nil {
@@ -154,6 +154,26 @@ func test3(ath *auth.Auth) func(t *testing.T) {
 		if err == nil {
 			t.Error("Should NOT be able to authorize the RuleAdminOrSubject claim with Roles.User only and different userID")
 		}
+
+		err = ath.AuthorizeMember(context.Background(), parsedClaims, userID, "VIEWER", auth.RuleAdminOrViewer)
+		if err != nil {
+			t.Errorf("Should be able to authorize the RuleAdminOrViewer claim with Roles.User only and a viewer role : %s", err)
+		}
+
+		err = ath.AuthorizeMember(context.Background(), parsedClaims, userID, "VIEWER", auth.RuleAdminOrEditor)
+		if err == nil {
+			t.Error("Should NOT be able to authorize the RuleAdminOrEditor claim with Roles.User only and a viewer role")
+		}
+
+		err = ath.AuthorizeMember(context.Background(), parsedClaims, userID, "EDITOR", auth.RuleAdminOrEditor)
+		if err != nil {
+			t.Errorf("Should be able to authorize the RuleAdminOrEditor claim with Roles.User only and an editor role : %s", err)
+		}
+
+		err = ath.Authorize(context.Background(), parsedClaims, userID, auth.RuleAdminOrViewer)
+		if err == nil {
+			t.Error("Should NOT be able to authorize the RuleAdminOrViewer claim with Roles.User only and no membership")
+		}
 	}
 
 	return f
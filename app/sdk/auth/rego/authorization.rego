@@ -10,12 +10,20 @@ default rule_user_only := false
 
 default rule_admin_or_subject := false
 
+default rule_admin_or_editor := false
+
+default rule_admin_or_viewer := false
+
 role_user := "USER"
 
 role_admin := "ADMIN"
 
 role_all := {role_admin, role_user}
 
+member_editor := {"OWNER", "EDITOR"}
+
+member_viewer := {"OWNER", "EDITOR", "VIEWER"}
+
 rule_any if {
 	claim_roles := {role | some role in input.Roles}
 	input_roles := role_all & claim_roles
@@ -44,3 +52,21 @@ rule_admin_or_subject if {
 	count(input_user) > 0
 	input.UserID == input.Subject
 }
+
+rule_admin_or_editor if {
+	rule_admin_or_subject
+} else if {
+	claim_roles := {role | some role in input.Roles}
+	input_user := {role_user} & claim_roles
+	count(input_user) > 0
+	input.Role in member_editor
+}
+
+rule_admin_or_viewer if {
+	rule_admin_or_subject
+} else if {
+	claim_roles := {role | some role in input.Roles}
+	input_user := {role_user} & claim_roles
+	count(input_user) > 0
+	input.Role in member_viewer
+}
@@ -11,6 +11,8 @@ const (
 	RuleAdminOnly      = "rule_admin_only"
 	RuleUserOnly       = "rule_user_only"
 	RuleAdminOrSubject = "rule_admin_or_subject"
+	RuleAdminOrEditor  = "rule_admin_or_editor"
+	RuleAdminOrViewer  = "rule_admin_or_viewer"
 )
 
 // Package name of our rego code.
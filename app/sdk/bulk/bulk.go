@@ -0,0 +1,151 @@
+// Package bulk provides support for requests that work on many items.
+package bulk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/sdk/bulk"
+)
+
+// Mode selects what happens to a bulk request when some of its items fail.
+type Mode string
+
+// Set of known modes. An atomic request writes nothing when any item fails,
+// while a best effort request writes every item that doesn't.
+const (
+	Atomic     Mode = "atomic"
+	BestEffort Mode = "bestEffort"
+)
+
+// ParseMode parses the string into a mode. An empty string asks for an
+// atomic request.
+func ParseMode(value string) (Mode, error) {
+	switch Mode(value) {
+	case "", Atomic:
+		return Atomic, nil
+	case BestEffort:
+		return BestEffort, nil
+	}
+
+	return "", fmt.Errorf("unknown mode %q, must be atomic or bestEffort", value)
+}
+
+// =============================================================================
+
+// Item is the result of a single item of a bulk request. It holds the id of
+// the item that was written or the errors that kept it from being written.
+type Item struct {
+	Index  int              `json:"index"`
+	ID     string           `json:"id,omitempty"`
+	Errors errs.FieldErrors `json:"errors,omitempty"`
+}
+
+// Result is the data model used when returning a bulk request, with the
+// result of every item in the order of the request.
+type Result struct {
+	Items     []Item `json:"items"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+}
+
+// NewResult constructs a result for a request of the specified number of
+// items.
+func NewResult(items int) *Result {
+	r := Result{
+		Items: make([]Item, items),
+	}
+
+	for i := range r.Items {
+		r.Items[i].Index = i
+	}
+
+	return &r
+}
+
+// ErrDetails implements the encore ErrDetails interface so the result can
+// go back with the error of an atomic request that failed.
+func (r *Result) ErrDetails() {}
+
+// Succeed records the id written for the item at the index.
+func (r *Result) Succeed(index int, id string) {
+	r.Items[index].ID = id
+	r.Succeeded++
+}
+
+// Fail records the error of the item at the index. Errors that aren't
+// field errors are reported against the item as a whole.
+func (r *Result) Fail(index int, err error) {
+	fe := errs.GetFieldErrors(err)
+	if fe == nil {
+		fe = errs.FieldErrors{{Field: "item", Err: err.Error()}}
+	}
+
+	if r.Items[index].Errors == nil {
+		r.Failed++
+	}
+	r.Items[index].Errors = append(r.Items[index].Errors, fe...)
+}
+
+// Err returns the error of an atomic request that had items fail. It carries
+// the result so the client can see the error of each item.
+func (r *Result) Err() error {
+	return errs.NewDetails(errs.InvalidArgument, fmt.Errorf("%d of %d items failed, nothing was written", r.Failed, len(r.Items)), r)
+}
+
+// UniqueIDs checks that no id is given for more than one item of an update.
+// Every item is applied to the value as it was before the request, so the
+// updates of the same value would overwrite each other.
+func UniqueIDs(ids []string) error {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		key := strings.ToLower(id)
+		if seen[key] {
+			return fmt.Errorf("id %q is given for more than one item", id)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// Batch holds the items of a bulk request that are passed on to the business
+// layer, along with their position in the request.
+type Batch[T any] struct {
+	Items   []T
+	Indexes []int
+}
+
+// Add appends the item found at the index of the request.
+func (b *Batch[T]) Add(index int, item T) {
+	b.Items = append(b.Items, item)
+	b.Indexes = append(b.Indexes, index)
+}
+
+// Drop removes the items the business layer reported errors for and records
+// those errors on the result, after passing them through the convert
+// function. It reports whether err held any item errors.
+func (b *Batch[T]) Drop(r *Result, err error, convert func(error) error) bool {
+	itemErrs, ok := bulk.AsErrors(err)
+	if !ok {
+		return false
+	}
+
+	failed := itemErrs.Indexes()
+	for _, ie := range itemErrs {
+		r.Fail(b.Indexes[ie.Index], convert(ie.Err))
+	}
+
+	var keep Batch[T]
+	for i, item := range b.Items {
+		if !failed[i] {
+			keep.Add(b.Indexes[i], item)
+		}
+	}
+	*b = keep
+
+	return true
+}
@@ -0,0 +1,127 @@
+// Package csvio provides support for reading and writing csv files.
+package csvio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Writer writes records to a csv file that starts with a header.
+type Writer struct {
+	w *csv.Writer
+}
+
+// NewWriter constructs a writer and writes the header of the file.
+func NewWriter(w io.Writer, header []string) (*Writer, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return nil, fmt.Errorf("write header: %w", err)
+	}
+
+	return &Writer{w: cw}, nil
+}
+
+// Write writes the record to the file. Records are buffered and written to
+// the underlying writer as the buffer fills, so a file of any size can be
+// written.
+func (w *Writer) Write(record []string) error {
+	return w.w.Write(record)
+}
+
+// Flush writes any buffered records to the underlying writer.
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// =============================================================================
+
+// Record is a single row of a csv file.
+type Record struct {
+	Row     int
+	values  []string
+	columns map[string]int
+}
+
+// Get returns the value of the column, which is empty when the file doesn't
+// have the column.
+func (r Record) Get(column string) string {
+	i, exists := r.columns[column]
+	if !exists {
+		return ""
+	}
+
+	return strings.TrimSpace(r.values[i])
+}
+
+// Reader reads the records of a csv file by the names in its header.
+type Reader struct {
+	r       *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+// NewReader constructs a reader and reads the header of the file, which must
+// name every required column.
+func NewReader(r io.Reader, required ...string) (*Reader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("file is empty")
+		}
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for _, name := range required {
+		if _, exists := columns[name]; !exists {
+			return nil, fmt.Errorf("header is missing column %q", name)
+		}
+	}
+
+	rdr := Reader{
+		r:       cr,
+		columns: columns,
+		row:     1,
+	}
+
+	return &rdr, nil
+}
+
+// Read reads the next record of the file. It returns io.EOF when there are
+// no more records. Rows are numbered as they appear in the file, so the
+// first record is row 2.
+func (r *Reader) Read() (Record, error) {
+	values, err := r.r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Record{}, io.EOF
+		}
+		return Record{}, fmt.Errorf("read row %d: %w", r.row+1, err)
+	}
+	r.row++
+
+	if len(values) > len(r.columns) {
+		return Record{}, fmt.Errorf("read row %d: has %d values for %d columns", r.row, len(values), len(r.columns))
+	}
+
+	values = append(values, make([]string, len(r.columns)-len(values))...)
+
+	rec := Record{
+		Row:     r.row,
+		values:  values,
+		columns: r.columns,
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,41 @@
+package csvio
+
+import (
+	"github.com/ardanlabs/encore/app/sdk/bulk"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+)
+
+// RowError holds the errors that kept a row of an import from being added.
+type RowError struct {
+	Row    int              `json:"row"`
+	Errors errs.FieldErrors `json:"errors"`
+}
+
+// Report is the data model used when returning the result of an import.
+type Report struct {
+	Imported int        `json:"imported"`
+	Failed   int        `json:"failed"`
+	Errors   []RowError `json:"errors"`
+}
+
+// ErrDetails implements the encore ErrDetails interface so the report of the
+// rows added before an import failed can go back with its error.
+func (r Report) ErrDetails() {}
+
+// NewReport constructs the report of an import from the result of its rows.
+// Rows holds the row number of each item of the result.
+func NewReport(res *bulk.Result, rows []int) Report {
+	rpt := Report{
+		Imported: res.Succeeded,
+		Failed:   res.Failed,
+		Errors:   []RowError{},
+	}
+
+	for i, item := range res.Items {
+		if item.Errors != nil {
+			rpt.Errors = append(rpt.Errors, RowError{Row: rows[i], Errors: item.Errors})
+		}
+	}
+
+	return rpt
+}
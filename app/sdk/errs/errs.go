@@ -26,6 +26,16 @@ func Newf(code errs.ErrCode, format string, v ...any) *errs.Error {
 	}
 }
 
+// NewDetails constructs an encore error based on an app error that carries
+// details for the client.
+func NewDetails(code errs.ErrCode, err error, details errs.ErrDetails) *errs.Error {
+	return &errs.Error{
+		Code:    code,
+		Message: err.Error(),
+		Details: details,
+	}
+}
+
 // NewResponse constructs an encore middleware response with a Go error.
 func NewResponse(code errs.ErrCode, err error) middleware.Response {
 	return middleware.Response{
@@ -1,6 +1,7 @@
 package mid
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/ardanlabs/encore/business/domain/homebus"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
 	"github.com/google/uuid"
 )
 
@@ -133,12 +135,26 @@ func AuthorizeProduct(productBus *productbus.Business, req middleware.Request) (
 }
 
 // AuthorizeHome checks the user making the call has specified a home id on
-// the route that matches the claims.
+// the route that matches the claims. Routes tagged as_home_editor or
+// as_home_viewer are also open to the members the home is shared with.
 func AuthorizeHome(homeBus *homebus.Business, req middleware.Request) (AuthInfo, middleware.Request, error) {
 	ctx := req.Context()
+	claims := eauth.Data().(*auth.Claims)
+
 	var userID uuid.UUID
+	var role string
 
-	if len(req.Data().PathParams) == 1 {
+	rule := auth.RuleAdminOrSubject
+	for _, tag := range req.Data().API.Tags {
+		switch tag {
+		case "as_home_editor":
+			rule = auth.RuleAdminOrEditor
+		case "as_home_viewer":
+			rule = auth.RuleAdminOrViewer
+		}
+	}
+
+	if len(req.Data().PathParams) > 0 {
 		id := req.Data().PathParams[0]
 
 		homeID, err := uuid.Parse(id.Value)
@@ -157,10 +173,75 @@ func AuthorizeHome(homeBus *homebus.Business, req middleware.Request) (AuthInfo,
 			}
 		}
 
+		if rule != auth.RuleAdminOrSubject {
+			role, err = homeRole(ctx, homeBus, hme, claims.Subject)
+			if err != nil {
+				return AuthInfo{}, req, err
+			}
+		}
+
 		userID = hme.UserID
 		req = setHome(req, hme)
 	}
 
+	authInfo := AuthInfo{
+		Claims: *claims,
+		UserID: userID,
+		Rule:   rule,
+		Role:   role,
+	}
+
+	return authInfo, req, nil
+}
+
+// homeRole returns the role the caller has on the home or an empty string
+// when the home isn't shared with them.
+func homeRole(ctx context.Context, homeBus *homebus.Business, hme homebus.Home, subject string) (string, error) {
+	callerID, err := uuid.Parse(subject)
+	if err != nil {
+		return "", ErrInvalidID
+	}
+
+	role, err := homeBus.QueryRole(ctx, hme, callerID)
+	if err != nil {
+		if errors.Is(err, homebus.ErrMemberNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("queryrole: homeID[%s]: %s", hme.ID, err)
+	}
+
+	return role.String(), nil
+}
+
+// AuthorizeWebhook checks the user making the call has specified a webhook id
+// on the route that matches the claims.
+func AuthorizeWebhook(webhookBus *webhookbus.Business, req middleware.Request) (AuthInfo, middleware.Request, error) {
+	ctx := req.Context()
+	var userID uuid.UUID
+
+	if len(req.Data().PathParams) > 0 {
+		id := req.Data().PathParams[0]
+
+		webhookID, err := uuid.Parse(id.Value)
+		if err != nil {
+			return AuthInfo{}, req, ErrInvalidID
+		}
+
+		wh, err := webhookBus.QueryByID(ctx, webhookID)
+		if err != nil {
+			switch {
+			case errors.Is(err, webhookbus.ErrNotFound):
+				return AuthInfo{}, req, err
+
+			default:
+				return AuthInfo{}, req, fmt.Errorf("querybyid: webhookID[%s]: %s", webhookID, err)
+			}
+		}
+
+		userID = wh.UserID
+		req = setWebhook(req, wh)
+	}
+
 	claims := eauth.Data().(*auth.Claims)
 
 	authInfo := AuthInfo{
@@ -11,15 +11,18 @@ import (
 	"github.com/ardanlabs/encore/business/domain/homebus"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/google/uuid"
 )
 
 // AuthInfo defines the information required to perform an authorization.
+// Role holds the role the caller has as a member of the resource, if any.
 type AuthInfo struct {
 	Claims auth.Claims
 	UserID uuid.UUID
 	Rule   string
+	Role   string
 }
 
 // =============================================================================
@@ -31,6 +34,7 @@ const (
 	userKey
 	productKey
 	homeKey
+	webhookKey
 	trKey
 )
 
@@ -54,6 +58,16 @@ func GetUserID(ctx context.Context) (uuid.UUID, error) {
 	return v, nil
 }
 
+// GetClaims extracts the claims of the caller from the context.
+func GetClaims(ctx context.Context) (auth.Claims, error) {
+	claims, ok := eauth.Data().(*auth.Claims)
+	if !ok {
+		return auth.Claims{}, errors.New("claims not found")
+	}
+
+	return *claims, nil
+}
+
 // GetUser extracts the user from the context.
 func GetUser(ctx context.Context) (userbus.User, error) {
 	v, ok := ctx.Value(userKey).(userbus.User)
@@ -94,6 +108,21 @@ func GetHome(ctx context.Context) (homebus.Home, error) {
 	return v, nil
 }
 
+func setWebhook(req middleware.Request, wh webhookbus.Webhook) middleware.Request {
+	ctx := context.WithValue(req.Context(), webhookKey, wh)
+	return req.WithContext(ctx)
+}
+
+// GetWebhook returns the webhook from the context.
+func GetWebhook(ctx context.Context) (webhookbus.Webhook, error) {
+	v, ok := ctx.Value(webhookKey).(webhookbus.Webhook)
+	if !ok {
+		return webhookbus.Webhook{}, errors.New("webhook not found in context")
+	}
+
+	return v, nil
+}
+
 func setTran(req middleware.Request, tx sqldb.CommitRollbacker) middleware.Request {
 	ctx := context.WithValue(req.Context(), trKey, tx)
 	return req.WithContext(ctx)
@@ -5,14 +5,28 @@ import (
 	"database/sql"
 	"errors"
 
+	eerrs "encore.dev/beta/errs"
 	"encore.dev/middleware"
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/foundation/logger"
 )
 
-// BeginCommitRollback starts a transaction for the domain call.
+// BeginCommitRollback starts a transaction for the domain call. An error from
+// the call is returned as an internal error.
 func BeginCommitRollback(log *logger.Logger, bgn sqldb.Beginner, req middleware.Request, next middleware.Next) middleware.Response {
+	return beginCommitRollback(log, bgn, req, next, false)
+}
+
+// BeginCommitRollbackClientErrors starts a transaction for the domain call
+// like BeginCommitRollback, except an error the call already classified as
+// the client's fault keeps its code and details so the client can see what
+// to fix. It's for the calls that report errors in the data they were sent.
+func BeginCommitRollbackClientErrors(log *logger.Logger, bgn sqldb.Beginner, req middleware.Request, next middleware.Next) middleware.Response {
+	return beginCommitRollback(log, bgn, req, next, true)
+}
+
+func beginCommitRollback(log *logger.Logger, bgn sqldb.Beginner, req middleware.Request, next middleware.Next, clientErrors bool) middleware.Response {
 	ctx := context.Background()
 
 	hasCommitted := false
@@ -40,6 +54,11 @@ func BeginCommitRollback(log *logger.Logger, bgn sqldb.Beginner, req middleware.
 
 	resp := next(req)
 	if resp.Err != nil {
+		var callErr *eerrs.Error
+		if clientErrors && errors.As(resp.Err, &callErr) && callErr.Code != errs.Internal {
+			return resp
+		}
+
 		return errs.NewResponsef(errs.Internal, "EXECUTE TRANSACTION: %s", resp.Err)
 	}
 
@@ -0,0 +1,68 @@
+// Package patch provides support for RFC 7396 merge patch documents.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+)
+
+// ErrNull is reported when a member that can't be cleared is set to null.
+var ErrNull = errors.New("cannot be null")
+
+// Field represents a member of a merge patch document. A member that is
+// absent from the document leaves Set false, a member that is null sets
+// both Set and Null.
+type Field[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It is only called
+// for members present in the document, null included.
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		f.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &f.Value)
+}
+
+// Required returns a pointer to the value or nil when the member is absent.
+// The field can't be cleared, so a null member is added to fe as ErrNull
+// against the named field.
+func Required[T any](fe *errs.FieldErrors, field string, f Field[T]) *T {
+	if !f.Set {
+		return nil
+	}
+
+	if f.Null {
+		*fe = append(*fe, errs.FieldError{Field: field, Err: ErrNull.Error()})
+		return nil
+	}
+
+	v := f.Value
+	return &v
+}
+
+// Nullable returns a pointer to the value or nil when the member is absent.
+// A null member returns a pointer to the zero value, which clears the field.
+func (f Field[T]) Nullable() *T {
+	if !f.Set {
+		return nil
+	}
+
+	if f.Null {
+		var zero T
+		return &zero
+	}
+
+	v := f.Value
+	return &v
+}
@@ -0,0 +1,50 @@
+package patch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/patch"
+)
+
+func Test_Field(t *testing.T) {
+	type doc struct {
+		Name       patch.Field[string]  `json:"name"`
+		Department patch.Field[string]  `json:"department"`
+		Cost       patch.Field[float64] `json:"cost"`
+	}
+
+	var d doc
+	if err := json.Unmarshal([]byte(`{"name":"Bill","department":null}`), &d); err != nil {
+		t.Fatalf("Should be able to unmarshal the document: %s", err)
+	}
+
+	var fe errs.FieldErrors
+
+	name := patch.Required(&fe, "name", d.Name)
+	if name == nil || *name != "Bill" {
+		t.Errorf("Should get the name Bill: got %v", name)
+	}
+
+	dept := d.Department.Nullable()
+	if dept == nil || *dept != "" {
+		t.Errorf("Should get an empty department for a null member: got %v", dept)
+	}
+
+	if cost := patch.Required(&fe, "cost", d.Cost); cost != nil {
+		t.Errorf("Should get nil for an absent member: got %v", *cost)
+	}
+
+	if len(fe) != 0 {
+		t.Fatalf("Should not get any field errors: got %s", fe)
+	}
+
+	if dept := patch.Required(&fe, "department", d.Department); dept != nil {
+		t.Errorf("Should get nil for a null required member: got %v", *dept)
+	}
+
+	if fe.Fields()["department"] != patch.ErrNull.Error() {
+		t.Errorf("Should get ErrNull for a null required member: got %s", fe)
+	}
+}
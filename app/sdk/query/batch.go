@@ -0,0 +1,78 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/google/uuid"
+)
+
+// BatchGet represents the ids asked for by a batch get. No more than 100 ids
+// can be asked for at once.
+type BatchGet struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app BatchGet) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// ParseIDs parses the ids, dropping any that are asked for more than once.
+func (app BatchGet) ParseIDs() ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(app.IDs))
+	seen := make(map[uuid.UUID]bool, len(app.IDs))
+
+	for _, s := range app.IDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("parse id %q: %w", s, err)
+		}
+
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Batch is the data model used when returning a batch get. Items are in the
+// order their ids were asked for and Missing holds the ids that didn't match
+// anything the caller is allowed to see.
+type Batch[T any] struct {
+	Items   []T      `json:"items"`
+	Missing []string `json:"missing"`
+}
+
+// NewBatch constructs a batch value from the items found for the ids. The id
+// function returns the id of an item.
+func NewBatch[T any](ids []uuid.UUID, items []T, id func(T) string) Batch[T] {
+	found := make(map[string]T, len(items))
+	for _, item := range items {
+		found[id(item)] = item
+	}
+
+	b := Batch[T]{
+		Items:   make([]T, 0, len(items)),
+		Missing: []string{},
+	}
+
+	for _, id := range ids {
+		item, ok := found[id.String()]
+		if !ok {
+			b.Missing = append(b.Missing, id.String())
+			continue
+		}
+		b.Items = append(b.Items, item)
+	}
+
+	return b
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Decode copies the query strings held by values into the string fields of
+// the struct dest points to. It names the fields the way the framework does
+// for typed endpoints, so raw endpoints accept the same query strings: the
+// query tag when there is one and the snake case of the field name when
+// there isn't.
+func Decode(values url.Values, dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode: %T is not a pointer to a struct", dest)
+	}
+	v = v.Elem()
+
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() || field.Type.Kind() != reflect.String {
+			continue
+		}
+
+		name := field.Tag.Get("query")
+		if name == "" {
+			name = snakeCase(field.Name)
+		}
+
+		if value := values.Get(name); value != "" {
+			v.Field(i).SetString(value)
+		}
+	}
+
+	return nil
+}
+
+// snakeCase converts a Go field name like UserID to user_id.
+func snakeCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package query_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Decode(t *testing.T) {
+	type params struct {
+		Page             string
+		OrderBy          string
+		UserID           string
+		IDNE             string `query:"id[ne]"`
+		StartCreatedDate string
+		Rows             int
+	}
+
+	values := url.Values{
+		"page":               {"2"},
+		"order_by":           {"name,DESC"},
+		"user_id":            {"5cf37266-3473-4006-984f-9325122678b7"},
+		"id[ne]":             {"45b5fbd3-755f-4379-8f07-a58d4a30fa2f"},
+		"start_created_date": {"2024-01-01T00:00:00Z"},
+		"rows":               {"10"},
+	}
+
+	var got params
+	if err := query.Decode(values, &got); err != nil {
+		t.Fatalf("Should be able to decode the values: %s", err)
+	}
+
+	exp := params{
+		Page:             "2",
+		OrderBy:          "name,DESC",
+		UserID:           "5cf37266-3473-4006-984f-9325122678b7",
+		IDNE:             "45b5fbd3-755f-4379-8f07-a58d4a30fa2f",
+		StartCreatedDate: "2024-01-01T00:00:00Z",
+	}
+
+	if diff := cmp.Diff(got, exp); diff != "" {
+		t.Errorf("Should get back the expected params:\n%s", diff)
+	}
+}
@@ -2,15 +2,31 @@
 package query
 
 import (
+	"encoding/json"
+	"slices"
+
 	"github.com/ardanlabs/encore/business/sdk/page"
 )
 
-// Result is the data model used when returning a query result.
+// Result is the data model used when returning a query result. The cursors
+// are empty when there is no page in that direction.
+//
+// Fields holds the json names of the item fields the client asked for. The
+// items are encoded with only those fields, while an empty list encodes
+// every field.
+//
+// Estimated is set when the total is the planner's estimate. HasMore is only
+// set when the total wasn't counted, in which case the total is zero.
 type Result[T any] struct {
-	Items       []T `json:"items"`
-	Total       int `json:"total"`
-	Page        int `json:"page"`
-	RowsPerPage int `json:"rowsPerPage"`
+	Items       []T      `json:"items"`
+	Total       int      `json:"total"`
+	Estimated   bool     `json:"estimated,omitempty"`
+	HasMore     *bool    `json:"hasMore,omitempty"`
+	Page        int      `json:"page"`
+	RowsPerPage int      `json:"rowsPerPage"`
+	NextCursor  string   `json:"nextCursor,omitempty"`
+	PrevCursor  string   `json:"prevCursor,omitempty"`
+	Fields      []string `json:"-"`
 }
 
 // NewResult constructs a result value to return query results.
@@ -22,3 +38,70 @@ func NewResult[T any](items []T, total int, page page.Page) Result[T] {
 		RowsPerPage: page.RowsPerPage(),
 	}
 }
+
+// WithCursors returns the result with the cursors of the pages before and
+// after it.
+func (r Result[T]) WithCursors(prev string, next string) Result[T] {
+	r.PrevCursor = prev
+	r.NextCursor = next
+	return r
+}
+
+// WithTotal returns the result marked with how its total was found. HasMore
+// reports whether there are rows past the page when the total wasn't counted.
+func (r Result[T]) WithTotal(mode Total, hasMore bool) Result[T] {
+	r.Estimated = mode == TotalEstimate
+	if mode == TotalNone {
+		r.HasMore = &hasMore
+	}
+	return r
+}
+
+// WithFields returns the result with its items limited to the fields.
+func (r Result[T]) WithFields(fields []string) Result[T] {
+	r.Fields = fields
+	return r
+}
+
+// MarshalJSON implements the json.Marshaler interface so the items only hold
+// the fields the client asked for.
+func (r Result[T]) MarshalJSON() ([]byte, error) {
+	if len(r.Fields) == 0 {
+		return json.Marshal(result[T](r))
+	}
+
+	items := make([]map[string]json.RawMessage, len(r.Items))
+	for i, item := range r.Items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(data, &items[i]); err != nil {
+			return nil, err
+		}
+
+		for name := range items[i] {
+			if !slices.Contains(r.Fields, name) {
+				delete(items[i], name)
+			}
+		}
+	}
+
+	sparse := result[map[string]json.RawMessage]{
+		Items:       items,
+		Total:       r.Total,
+		Estimated:   r.Estimated,
+		HasMore:     r.HasMore,
+		Page:        r.Page,
+		RowsPerPage: r.RowsPerPage,
+		NextCursor:  r.NextCursor,
+		PrevCursor:  r.PrevCursor,
+	}
+
+	return json.Marshal(sparse)
+}
+
+// result has the fields of Result without its methods, so it's encoded the
+// standard way.
+type result[T any] Result[T]
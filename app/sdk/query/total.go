@@ -0,0 +1,69 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/sdk/page"
+)
+
+// Total selects how the total of a query result is found.
+type Total string
+
+// Set of known ways to find the total.
+const (
+	TotalExact    Total = "exact"
+	TotalEstimate Total = "estimate"
+	TotalNone     Total = "none"
+)
+
+// ParseTotal parses the string into a total mode. An empty string asks for
+// the exact total.
+func ParseTotal(value string) (Total, error) {
+	switch Total(value) {
+	case "", TotalExact:
+		return TotalExact, nil
+	case TotalEstimate, TotalNone:
+		return Total(value), nil
+	}
+
+	return "", fmt.Errorf("unknown total %q, must be exact, estimate or none", value)
+}
+
+// Page returns the page set to look ahead when the total isn't counted, so
+// the result can still report if there are more rows.
+func (t Total) Page(pg page.Page) page.Page {
+	if t == TotalNone {
+		return pg.WithLookahead()
+	}
+
+	return pg
+}
+
+// Count finds the total for the filter the way the mode asks for, using
+// the exact and estimate functions of the business layer.
+func Count[F any](ctx context.Context, mode Total, filter F, exact, estimate func(context.Context, F) (int, error)) (int, error) {
+	switch mode {
+	case TotalEstimate:
+		return estimate(ctx, filter)
+	case TotalNone:
+		return 0, nil
+	}
+
+	return exact(ctx, filter)
+}
+
+// Trim drops the extra row fetched by a page that looks ahead and reports
+// whether there was one. A backward page is read in reverse, so its extra
+// row is the first one.
+func Trim[T any](pg page.Page, items []T) ([]T, bool) {
+	if len(items) <= pg.RowsPerPage() {
+		return items, false
+	}
+
+	if pg.IsBackward() {
+		return items[1:], true
+	}
+
+	return items[:pg.RowsPerPage()], true
+}
@@ -0,0 +1,90 @@
+// Package sse provides support for writing a stream of server-sent events.
+package sse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Writer writes server-sent events to a response, flushing each one so it
+// reaches the client straight away.
+type Writer struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	started bool
+}
+
+// NewWriter constructs a writer for the response, which must support
+// flushing.
+func NewWriter(w http.ResponseWriter) (*Writer, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("response does not support flushing")
+	}
+
+	sw := Writer{
+		w:       w,
+		flusher: flusher,
+	}
+
+	return &sw, nil
+}
+
+// Started reports whether anything has been written to the response.
+func (sw *Writer) Started() bool {
+	return sw.started
+}
+
+// Event writes an event with the specified id, name and data. A data value
+// holding more than one line is sent as one data field per line.
+func (sw *Writer) Event(id string, name string, data []byte) error {
+	var buf bytes.Buffer
+
+	if id != "" {
+		fmt.Fprintf(&buf, "id: %s\n", id)
+	}
+
+	if name != "" {
+		fmt.Fprintf(&buf, "event: %s\n", name)
+	}
+
+	for line := range bytes.Lines(data) {
+		fmt.Fprintf(&buf, "data: %s\n", bytes.TrimRight(line, "\r\n"))
+	}
+	buf.WriteString("\n")
+
+	return sw.write(buf.Bytes())
+}
+
+// Comment writes a comment, which clients ignore. It's used to keep idle
+// connections open.
+func (sw *Writer) Comment(text string) error {
+	return sw.write(fmt.Appendf(nil, ": %s\n\n", text))
+}
+
+// Retry tells the client how long to wait before reconnecting when the
+// connection is lost.
+func (sw *Writer) Retry(d time.Duration) error {
+	return sw.write(fmt.Appendf(nil, "retry: %d\n\n", d.Milliseconds()))
+}
+
+func (sw *Writer) write(p []byte) error {
+	if !sw.started {
+		h := sw.w.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		h.Set("Connection", "keep-alive")
+		h.Set("X-Accel-Buffering", "no")
+		sw.started = true
+	}
+
+	if _, err := sw.w.Write(p); err != nil {
+		return err
+	}
+	sw.flusher.Flush()
+
+	return nil
+}
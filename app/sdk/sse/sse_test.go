@@ -0,0 +1,44 @@
+package sse_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/sse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Writer(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	sw, err := sse.NewWriter(rec)
+	if err != nil {
+		t.Fatalf("Should be able to construct the writer: %s", err)
+	}
+
+	if err := sw.Retry(3 * time.Second); err != nil {
+		t.Fatalf("Should be able to write the retry: %s", err)
+	}
+
+	if err := sw.Event("42", "product.created", []byte("{\"a\":1}\n{\"b\":2}")); err != nil {
+		t.Fatalf("Should be able to write the event: %s", err)
+	}
+
+	if err := sw.Comment("ping"); err != nil {
+		t.Fatalf("Should be able to write the comment: %s", err)
+	}
+
+	exp := "retry: 3000\n\nid: 42\nevent: product.created\ndata: {\"a\":1}\ndata: {\"b\":2}\n\n: ping\n\n"
+	if diff := cmp.Diff(rec.Body.String(), exp); diff != "" {
+		t.Errorf("Should get the expected stream:\n%s", diff)
+	}
+
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Should get the event stream content type: got %q", got)
+	}
+
+	if !rec.Flushed {
+		t.Error("Should flush the events")
+	}
+}
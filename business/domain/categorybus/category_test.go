@@ -0,0 +1,453 @@
+package categorybus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Category(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, query(db.BusDomain, sd), "query")
+	unitest.Run(t, create(db.BusDomain, sd), "create")
+	unitest.Run(t, update(db.BusDomain, sd), "update")
+	unitest.Run(t, product(db.BusDomain, sd), "product")
+	unitest.Run(t, delete(db.BusDomain, sd), "delete")
+}
+
+// =============================================================================
+
+// insertSeedData builds a three level hierarchy of categories where each
+// category is the parent of the next one.
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	cats := make([]categorybus.Category, 0, 3)
+
+	parentID := uuid.Nil
+	for range 3 {
+		seeded, err := categorybus.TestGenerateSeedCategories(ctx, 1, busDomain.Category, parentID)
+		if err != nil {
+			return unitest.SeedData{}, fmt.Errorf("seeding categories : %w", err)
+		}
+
+		cats = append(cats, seeded[0])
+		parentID = seeded[0].ID
+	}
+
+	// -------------------------------------------------------------------------
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.Admin, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err := productbus.TestGenerateSeedProducts(ctx, 2, busDomain.Product, usrs[0].ID)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	tu1 := unitest.User{
+		User:     usrs[0],
+		Products: prds,
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := unitest.SeedData{
+		Admins:     []unitest.User{tu1},
+		Categories: cats,
+	}
+
+	return sd, nil
+}
+
+// cursorIDs returns the ids seen when paging through the categories ordered
+// by name one row at a time, first forward and then back to the start.
+func cursorIDs(cats []categorybus.Category) []uuid.UUID {
+	cats = slices.Clone(cats)
+	sort.Slice(cats, func(i, j int) bool {
+		if cats[i].Name == cats[j].Name {
+			return cats[i].ID.String() < cats[j].ID.String()
+		}
+		return cats[i].Name < cats[j].Name
+	})
+
+	ids := make([]uuid.UUID, 0, 2*len(cats))
+	for _, cat := range cats {
+		ids = append(ids, cat.ID)
+	}
+
+	for i := len(cats) - 2; i >= 0; i-- {
+		ids = append(ids, cats[i].ID)
+	}
+
+	return ids
+}
+
+// =============================================================================
+
+func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "children",
+			ExpResp: []categorybus.Category{sd.Categories[1]},
+			ExcFunc: func(ctx context.Context) any {
+				filter := categorybus.QueryFilter{
+					ParentID: &sd.Categories[0].ID,
+				}
+
+				resp, err := busDomain.Category.Query(ctx, filter, categorybus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]categorybus.Category)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]categorybus.Category)
+
+				for i := range gotResp {
+					if gotResp[i].DateCreated.Format(time.RFC3339) == expResp[i].DateCreated.Format(time.RFC3339) {
+						expResp[i].DateCreated = gotResp[i].DateCreated
+					}
+
+					if gotResp[i].DateUpdated.Format(time.RFC3339) == expResp[i].DateUpdated.Format(time.RFC3339) {
+						expResp[i].DateUpdated = gotResp[i].DateUpdated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "cursor",
+			ExpResp: cursorIDs(sd.Categories),
+			ExcFunc: func(ctx context.Context) any {
+				orderBy := categorybus.DefaultOrderBy
+
+				query := func(pg page.Page) ([]categorybus.Category, string, string, error) {
+					cats, err := busDomain.Category.Query(ctx, categorybus.QueryFilter{}, orderBy, pg)
+					if err != nil {
+						return nil, "", "", err
+					}
+
+					prev, next := pg.Cursors(orderBy, len(cats), func(i int) page.Key {
+						return page.Key{Values: []string{cats[i].Name}, ID: cats[i].ID.String()}
+					})
+
+					return cats, prev, next, nil
+				}
+
+				var ids []uuid.UUID
+
+				// Walk forward a row at a time until the end, then back from
+				// the last row to the start.
+				pg := page.MustParse("1", "1")
+				var back string
+				for {
+					cats, prev, next, err := query(pg)
+					if err != nil {
+						return err
+					}
+
+					if len(cats) == 0 {
+						break
+					}
+
+					ids = append(ids, cats[0].ID)
+					back = prev
+
+					if next == "" {
+						break
+					}
+					pg = page.MustParseCursor(next, "1")
+				}
+
+				for back != "" {
+					cats, prev, _, err := query(page.MustParseCursor(back, "1"))
+					if err != nil {
+						return err
+					}
+
+					for _, cat := range cats {
+						ids = append(ids, cat.ID)
+					}
+					back = prev
+				}
+
+				return ids
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "byid",
+			ExpResp: sd.Categories[2],
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Category.QueryByID(ctx, sd.Categories[2].ID)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(categorybus.Category)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(categorybus.Category)
+
+				if gotResp.DateCreated.Format(time.RFC3339) == expResp.DateCreated.Format(time.RFC3339) {
+					expResp.DateCreated = gotResp.DateCreated
+				}
+
+				if gotResp.DateUpdated.Format(time.RFC3339) == expResp.DateUpdated.Format(time.RFC3339) {
+					expResp.DateUpdated = gotResp.DateUpdated
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: categorybus.Category{
+				ParentID: sd.Categories[0].ID,
+				Name:     "Guitars",
+			},
+			ExcFunc: func(ctx context.Context) any {
+				nc := categorybus.NewCategory{
+					ParentID: sd.Categories[0].ID,
+					Name:     "Guitars",
+				}
+
+				resp, err := busDomain.Category.Create(ctx, nc)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(categorybus.Category)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(categorybus.Category)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "parent",
+			ExpResp: categorybus.ErrParentNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				nc := categorybus.NewCategory{
+					ParentID: uuid.New(),
+					Name:     "Orphan",
+				}
+
+				_, err := busDomain.Category.Create(ctx, nc)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: categorybus.Category{
+				ID:          sd.Categories[1].ID,
+				ParentID:    sd.Categories[1].ParentID,
+				Name:        "Instruments",
+				DateCreated: sd.Categories[1].DateCreated,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				uc := categorybus.UpdateCategory{
+					Name: dbtest.StringPointer("Instruments"),
+				}
+
+				resp, err := busDomain.Category.Update(ctx, sd.Categories[1], uc)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(categorybus.Category)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(categorybus.Category)
+
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "cycle",
+			ExpResp: categorybus.ErrCycle,
+			ExcFunc: func(ctx context.Context) any {
+				uc := categorybus.UpdateCategory{
+					ParentID: &sd.Categories[2].ID,
+				}
+
+				_, err := busDomain.Category.Update(ctx, sd.Categories[0], uc)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func product(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	prd := sd.Admins[0].Products[0]
+
+	table := []unitest.Table{
+		{
+			Name:    "descendants",
+			ExpResp: []uuid.UUID{prd.ID},
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Product.SetCategories(ctx, prd, []uuid.UUID{sd.Categories[2].ID}); err != nil {
+					return err
+				}
+
+				filter := productbus.QueryFilter{
+					CategoryID: &sd.Categories[0].ID,
+				}
+
+				prds, err := busDomain.Product.Query(ctx, filter, fieldset.Set{}, productbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				ids := make([]uuid.UUID, len(prds))
+				for i, prd := range prds {
+					ids[i] = prd.ID
+				}
+
+				return ids
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "unknown",
+			ExpResp: productbus.ErrUnknownCategory,
+			ExcFunc: func(ctx context.Context) any {
+				return busDomain.Product.SetCategories(ctx, prd, []uuid.UUID{uuid.New()})
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "children",
+			ExpResp: uuid.Nil,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Category.Delete(ctx, sd.Categories[1]); err != nil {
+					return err
+				}
+
+				cat, err := busDomain.Category.QueryByID(ctx, sd.Categories[2].ID)
+				if err != nil {
+					return err
+				}
+
+				return cat.ParentID
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,184 @@
+// Package categorybus provides business access to category domain.
+package categorybus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound       = errors.New("category not found")
+	ErrUniqueName     = errors.New("category name is not unique under its parent")
+	ErrParentNotFound = errors.New("parent category not found")
+	ErrCycle          = errors.New("category cannot be moved under itself")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, cat Category) error
+	Update(ctx context.Context, cat Category) error
+	Delete(ctx context.Context, cat Category) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Category, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	EstimateCount(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, categoryID uuid.UUID) (Category, error)
+	QueryDescendantIDs(ctx context.Context, categoryID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// Business manages the set of APIs for category access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs a category business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new category to the system.
+func (b *Business) Create(ctx context.Context, nc NewCategory) (Category, error) {
+	if err := b.checkParent(ctx, nc.ParentID); err != nil {
+		return Category{}, err
+	}
+
+	now := time.Now()
+
+	cat := Category{
+		ID:          uuid.New(),
+		ParentID:    nc.ParentID,
+		Name:        nc.Name,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, cat); err != nil {
+		return Category{}, fmt.Errorf("create: %w", err)
+	}
+
+	return cat, nil
+}
+
+// Update modifies information about a category. Moving a category under
+// itself or one of its descendants is rejected.
+func (b *Business) Update(ctx context.Context, cat Category, uc UpdateCategory) (Category, error) {
+	if uc.ParentID != nil {
+		if *uc.ParentID != uuid.Nil {
+			ids, err := b.storer.QueryDescendantIDs(ctx, cat.ID)
+			if err != nil {
+				return Category{}, fmt.Errorf("querydescendantids: %w", err)
+			}
+
+			if *uc.ParentID == cat.ID || slices.Contains(ids, *uc.ParentID) {
+				return Category{}, ErrCycle
+			}
+
+			if err := b.checkParent(ctx, *uc.ParentID); err != nil {
+				return Category{}, err
+			}
+		}
+
+		cat.ParentID = *uc.ParentID
+	}
+
+	if uc.Name != nil {
+		cat.Name = *uc.Name
+	}
+
+	cat.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, cat); err != nil {
+		return Category{}, fmt.Errorf("update: %w", err)
+	}
+
+	return cat, nil
+}
+
+// Delete removes the specified category. Any child categories are moved to
+// the top of the hierarchy and products lose their association with it.
+func (b *Business) Delete(ctx context.Context, cat Category) error {
+	if err := b.storer.Delete(ctx, cat); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing categories.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Category, error) {
+	cats, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return cats, nil
+}
+
+// Count returns the total number of categories.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.Count(ctx, filter)
+}
+
+// EstimateCount returns an estimate of the total number of categories, which is
+// far cheaper than counting them.
+func (b *Business) EstimateCount(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.EstimateCount(ctx, filter)
+}
+
+// QueryByID finds the category by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, categoryID uuid.UUID) (Category, error) {
+	cat, err := b.storer.QueryByID(ctx, categoryID)
+	if err != nil {
+		return Category{}, fmt.Errorf("query: categoryID[%s]: %w", categoryID, err)
+	}
+
+	return cat, nil
+}
+
+// =============================================================================
+
+func (b *Business) checkParent(ctx context.Context, parentID uuid.UUID) error {
+	if parentID == uuid.Nil {
+		return nil
+	}
+
+	if _, err := b.storer.QueryByID(ctx, parentID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrParentNotFound
+		}
+		return fmt.Errorf("query: parentID[%s]: %w", parentID, err)
+	}
+
+	return nil
+}
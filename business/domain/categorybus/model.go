@@ -0,0 +1,32 @@
+package categorybus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category represents a node in the product category hierarchy. A category
+// without a parent has a ParentID of uuid.Nil.
+type Category struct {
+	ID          uuid.UUID
+	ParentID    uuid.UUID
+	Name        string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewCategory is what we require from clients when adding a Category.
+type NewCategory struct {
+	ParentID uuid.UUID
+	Name     string
+}
+
+// UpdateCategory defines what information may be provided to modify an
+// existing Category. All fields are optional so clients can send only the
+// fields they want changed. Setting ParentID to uuid.Nil moves the category
+// to the top of the hierarchy.
+type UpdateCategory struct {
+	ParentID *uuid.UUID
+	Name     *string
+}
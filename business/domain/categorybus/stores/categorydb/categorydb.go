@@ -0,0 +1,220 @@
+// Package categorydb contains category related CRUD functionality.
+package categorydb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for category database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (categorybus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new category into the database.
+func (s *Store) Create(ctx context.Context, cat categorybus.Category) error {
+	const q = `
+	INSERT INTO categories
+		(category_id, parent_id, name, date_created, date_updated)
+	VALUES
+		(:category_id, :parent_id, :name, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBCategory(cat)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", categorybus.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a category document in the database.
+func (s *Store) Update(ctx context.Context, cat categorybus.Category) error {
+	const q = `
+	UPDATE
+		categories
+	SET
+		"parent_id"    = :parent_id,
+		"name"         = :name,
+		"date_updated" = :date_updated
+	WHERE
+		category_id = :category_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBCategory(cat)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", categorybus.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a category from the database.
+func (s *Store) Delete(ctx context.Context, cat categorybus.Category) error {
+	data := struct {
+		ID string `db:"category_id"`
+	}{
+		ID: cat.ID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		categories
+	WHERE
+		category_id = :category_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing categories from the database.
+func (s *Store) Query(ctx context.Context, filter categorybus.QueryFilter, orderBy order.By, page page.Page) ([]categorybus.Category, error) {
+	qb := sqldb.NewBuilder("categories", "category_id, parent_id, name, date_created, date_updated")
+	s.applyFilter(filter, qb)
+
+	if err := qb.Page(orderByFields, orderBy, "category_id", page); err != nil {
+		return nil, err
+	}
+
+	var dbCats []category
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, qb.String(), qb.Data(), &dbCats); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	if page.IsBackward() {
+		slices.Reverse(dbCats)
+	}
+
+	return toBusCategories(dbCats), nil
+}
+
+// Count returns the total number of categories in the DB.
+func (s *Store) Count(ctx context.Context, filter categorybus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("categories", "count(1)")
+	s.applyFilter(filter, qb)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, qb.String(), qb.Data(), &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// EstimateCount returns the planner's estimate of the number of categories in
+// the DB.
+func (s *Store) EstimateCount(ctx context.Context, filter categorybus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("categories", "1")
+	s.applyFilter(filter, qb)
+
+	count, err := sqldb.EstimateCount(ctx, s.log, s.db, qb.String(), qb.Data())
+	if err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count, nil
+}
+
+// QueryByID gets the specified category from the database.
+func (s *Store) QueryByID(ctx context.Context, categoryID uuid.UUID) (categorybus.Category, error) {
+	data := struct {
+		ID string `db:"category_id"`
+	}{
+		ID: categoryID.String(),
+	}
+
+	const q = `
+	SELECT
+		category_id, parent_id, name, date_created, date_updated
+	FROM
+		categories
+	WHERE
+		category_id = :category_id`
+
+	var dbCat category
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbCat); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return categorybus.Category{}, fmt.Errorf("db: %w", categorybus.ErrNotFound)
+		}
+		return categorybus.Category{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusCategory(dbCat), nil
+}
+
+// QueryDescendantIDs walks the hierarchy below the specified category and
+// returns the ids of every category found.
+func (s *Store) QueryDescendantIDs(ctx context.Context, categoryID uuid.UUID) ([]uuid.UUID, error) {
+	data := struct {
+		ID string `db:"category_id"`
+	}{
+		ID: categoryID.String(),
+	}
+
+	const q = `
+	WITH RECURSIVE tree AS (
+		SELECT category_id FROM categories WHERE parent_id = :category_id
+		UNION
+		SELECT c.category_id FROM categories AS c JOIN tree AS t ON c.parent_id = t.category_id
+	)
+	SELECT
+		category_id
+	FROM
+		tree`
+
+	var dbIDs []struct {
+		ID uuid.UUID `db:"category_id"`
+	}
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbIDs); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(dbIDs))
+	for i, dbID := range dbIDs {
+		ids[i] = dbID.ID
+	}
+
+	return ids, nil
+}
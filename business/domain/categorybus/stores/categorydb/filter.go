@@ -0,0 +1,26 @@
+package categorydb
+
+import (
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/google/uuid"
+)
+
+func (s *Store) applyFilter(filter categorybus.QueryFilter, qb *sqldb.Builder) {
+	if filter.ID != nil {
+		qb.Equal("category_id", *filter.ID)
+	}
+
+	if filter.ParentID != nil {
+		switch *filter.ParentID {
+		case uuid.Nil:
+			qb.IsNull("parent_id")
+		default:
+			qb.Equal("parent_id", *filter.ParentID)
+		}
+	}
+
+	if filter.Name != nil {
+		qb.Contains("name", *filter.Name)
+	}
+}
@@ -0,0 +1,53 @@
+package categorydb
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/google/uuid"
+)
+
+type category struct {
+	ID          uuid.UUID     `db:"category_id"`
+	ParentID    uuid.NullUUID `db:"parent_id"`
+	Name        string        `db:"name"`
+	DateCreated time.Time     `db:"date_created"`
+	DateUpdated time.Time     `db:"date_updated"`
+}
+
+func toDBCategory(bus categorybus.Category) category {
+	db := category{
+		ID: bus.ID,
+		ParentID: uuid.NullUUID{
+			UUID:  bus.ParentID,
+			Valid: bus.ParentID != uuid.Nil,
+		},
+		Name:        bus.Name,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+
+	return db
+}
+
+func toBusCategory(db category) categorybus.Category {
+	bus := categorybus.Category{
+		ID:          db.ID,
+		ParentID:    db.ParentID.UUID,
+		Name:        db.Name,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus
+}
+
+func toBusCategories(dbs []category) []categorybus.Category {
+	bus := make([]categorybus.Category, len(dbs))
+
+	for i, db := range dbs {
+		bus[i] = toBusCategory(db)
+	}
+
+	return bus
+}
@@ -0,0 +1,11 @@
+package categorydb
+
+import (
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+var orderByFields = sqldb.Columns{
+	categorybus.OrderByID:   "category_id",
+	categorybus.OrderByName: "name",
+}
@@ -0,0 +1,45 @@
+package categorybus
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/google/uuid"
+)
+
+// TestGenerateNewCategories is a helper method for testing.
+func TestGenerateNewCategories(n int, parentID uuid.UUID) []NewCategory {
+	newCats := make([]NewCategory, n)
+
+	idx := rand.Intn(10000)
+	for i := 0; i < n; i++ {
+		idx++
+
+		nc := NewCategory{
+			ParentID: parentID,
+			Name:     fmt.Sprintf("Category%d", idx),
+		}
+
+		newCats[i] = nc
+	}
+
+	return newCats
+}
+
+// TestGenerateSeedCategories is a helper method for testing.
+func TestGenerateSeedCategories(ctx context.Context, n int, api *Business, parentID uuid.UUID) ([]Category, error) {
+	newCats := TestGenerateNewCategories(n, parentID)
+
+	cats := make([]Category, len(newCats))
+	for i, nc := range newCats {
+		cat, err := api.Create(ctx, nc)
+		if err != nil {
+			return nil, fmt.Errorf("seeding category: idx: %d : %w", i, err)
+		}
+
+		cats[i] = cat
+	}
+
+	return cats, nil
+}
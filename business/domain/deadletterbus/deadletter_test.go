@@ -0,0 +1,202 @@
+package deadletterbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/deadletterbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_DeadLetter(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, replay(db.BusDomain), "replay")
+}
+
+// =============================================================================
+
+// deadLetter holds the parts of a dead letter the tests check.
+type deadLetter struct {
+	EventID  uuid.UUID
+	Domain   string
+	Action   string
+	Attempts int
+	Error    string
+	Replayed bool
+}
+
+func toDeadLetter(dl deadletterbus.DeadLetter) deadLetter {
+	return deadLetter{
+		EventID:  dl.EventID,
+		Domain:   dl.Domain,
+		Action:   dl.Action,
+		Attempts: dl.Attempts,
+		Error:    dl.Error,
+		Replayed: !dl.DateReplayed.IsZero(),
+	}
+}
+
+// =============================================================================
+
+func replay(busDomain dbtest.BusDomain) []unitest.Table {
+	const (
+		domain = "test"
+		action = "failed"
+	)
+
+	retry := delegate.Retry{
+		Attempts:   3,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond,
+	}
+
+	// The function fails until it's fixed.
+	var fixed bool
+	busDomain.Delegate.Register(domain, action, "test", func(ctx context.Context, data delegate.Data) error {
+		if !fixed {
+			return errors.New("not fixed")
+		}
+		return nil
+	}, delegate.WithRetry(retry))
+
+	eventID := uuid.New()
+
+	filter := deadletterbus.QueryFilter{
+		Domain: dbtest.StringPointer(domain),
+	}
+
+	table := []unitest.Table{
+		{
+			Name: "exhausted",
+			ExpResp: []deadLetter{
+				{
+					EventID:  eventID,
+					Domain:   domain,
+					Action:   action,
+					Attempts: 3,
+					Error:    "not fixed",
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				data := delegate.Data{
+					ID:        eventID,
+					Domain:    domain,
+					Action:    action,
+					RawParams: []byte(`{}`),
+				}
+
+				if err := busDomain.Delegate.Call(ctx, data); err != nil {
+					return err
+				}
+
+				dls, err := busDomain.DeadLetter.Query(ctx, filter, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				resp := make([]deadLetter, len(dls))
+				for i, dl := range dls {
+					resp[i] = toDeadLetter(dl)
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "succeeded",
+			ExpResp: deadLetter{
+				EventID:  eventID,
+				Domain:   domain,
+				Action:   action,
+				Attempts: 5,
+				Replayed: true,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				dls, err := busDomain.DeadLetter.Query(ctx, filter, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				if len(dls) != 1 {
+					return fmt.Errorf("got %d dead letters, exp 1", len(dls))
+				}
+
+				// A replay that fails keeps the dead letter for another one.
+				dl, err := busDomain.DeadLetter.Replay(ctx, dls[0], busDomain.Delegate.Replay)
+				if err != nil {
+					return err
+				}
+
+				if dl.Error != "not fixed" || !dl.DateReplayed.IsZero() {
+					return fmt.Errorf("replay should have failed: %+v", dl)
+				}
+
+				fixed = true
+
+				stale := dl
+
+				dl, err = busDomain.DeadLetter.Replay(ctx, dl, busDomain.Delegate.Replay)
+				if err != nil {
+					return err
+				}
+
+				// A dead letter read before it was replayed isn't replayed
+				// again.
+				if _, err := busDomain.DeadLetter.Replay(ctx, stale, busDomain.Delegate.Replay); !errors.Is(err, deadletterbus.ErrReplayed) {
+					return fmt.Errorf("stale replay: got %v, exp %v", err, deadletterbus.ErrReplayed)
+				}
+
+				// A dead letter is only replayed until it succeeds.
+				if _, err := busDomain.DeadLetter.Replay(ctx, dl, busDomain.Delegate.Replay); !errors.Is(err, deadletterbus.ErrReplayed) {
+					return fmt.Errorf("replay: got %v, exp %v", err, deadletterbus.ErrReplayed)
+				}
+
+				replayed := filter
+				replayed.Replayed = dbtest.BoolPointer(true)
+
+				n, err := busDomain.DeadLetter.Count(ctx, replayed)
+				if err != nil {
+					return err
+				}
+
+				if n != 1 {
+					return fmt.Errorf("got %d replayed dead letters, exp 1", n)
+				}
+
+				dl, err = busDomain.DeadLetter.QueryByID(ctx, dl.ID)
+				if err != nil {
+					return err
+				}
+
+				return toDeadLetter(dl)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
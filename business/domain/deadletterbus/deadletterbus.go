@@ -0,0 +1,124 @@
+// Package deadletterbus provides business access to the events the delegate
+// functions failed to handle.
+package deadletterbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound = errors.New("dead letter not found")
+	ErrReplayed = errors.New("dead letter already replayed")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, dl DeadLetter) error
+	Update(ctx context.Context, dl DeadLetter) error
+	MarkReplayed(ctx context.Context, dl DeadLetter) (bool, error)
+	Query(ctx context.Context, filter QueryFilter, page page.Page) ([]DeadLetter, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, deadLetterID uuid.UUID) (DeadLetter, error)
+}
+
+// Business manages the set of APIs for dead letter access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs a dead letter business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// DeadLetter implements the delegate.DeadLetterer interface and keeps the
+// event along with the error from the last attempt at handling it.
+func (b *Business) DeadLetter(ctx context.Context, data delegate.Data, attempts int, cause error) error {
+	dl := toDeadLetter(data, attempts, cause, time.Now())
+
+	if err := b.storer.Create(ctx, dl); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of dead letters, newest first.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, page page.Page) ([]DeadLetter, error) {
+	dls, err := b.storer.Query(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return dls, nil
+}
+
+// Count returns the total number of dead letters.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the dead letter by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, deadLetterID uuid.UUID) (DeadLetter, error) {
+	dl, err := b.storer.QueryByID(ctx, deadLetterID)
+	if err != nil {
+		return DeadLetter{}, fmt.Errorf("query: deadLetterID[%s]: %w", deadLetterID, err)
+	}
+
+	return dl, nil
+}
+
+// Replay hands the event of the dead letter to the replay function and
+// records the outcome. The dead letter is marked as replayed before the
+// function is called, so a dead letter replayed at the same time is only
+// handed over once. The error from a failed replay is kept on the dead
+// letter and the mark is removed so it can be replayed again, it's not
+// returned.
+func (b *Business) Replay(ctx context.Context, dl DeadLetter, replay delegate.Func) (DeadLetter, error) {
+	if !dl.DateReplayed.IsZero() {
+		return DeadLetter{}, ErrReplayed
+	}
+
+	now := time.Now()
+
+	dl.Attempts++
+	dl.Error = ""
+	dl.DateUpdated = now
+	dl.DateReplayed = now
+
+	marked, err := b.storer.MarkReplayed(ctx, dl)
+	if err != nil {
+		return DeadLetter{}, fmt.Errorf("markreplayed: deadLetterID[%s]: %w", dl.ID, err)
+	}
+
+	if !marked {
+		return DeadLetter{}, ErrReplayed
+	}
+
+	if err := replay(ctx, dl.Data()); err != nil {
+		b.log.Error(ctx, "deadletter replay", "deadLetterID", dl.ID, "target", dl.Target, "msg", err)
+
+		dl.Error = err.Error()
+		dl.DateReplayed = time.Time{}
+
+		if err := b.storer.Update(ctx, dl); err != nil {
+			return DeadLetter{}, fmt.Errorf("update: deadLetterID[%s]: %w", dl.ID, err)
+		}
+	}
+
+	return dl, nil
+}
@@ -0,0 +1,10 @@
+package deadletterbus
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	Domain   *string
+	Action   *string
+	Target   *string
+	Replayed *bool
+}
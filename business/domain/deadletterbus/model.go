@@ -0,0 +1,51 @@
+package deadletterbus
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/google/uuid"
+)
+
+// DeadLetter represents an event a delegate function still failed to handle
+// after its last attempt. The date replayed is zero until a replay of the
+// event has succeeded, the error is the one from the last attempt.
+type DeadLetter struct {
+	ID           uuid.UUID
+	EventID      uuid.UUID
+	Domain       string
+	Action       string
+	RawParams    []byte
+	Target       string
+	Attempts     int
+	Error        string
+	DateCreated  time.Time
+	DateUpdated  time.Time
+	DateReplayed time.Time
+}
+
+// Data returns the event so it can be handled again.
+func (dl DeadLetter) Data() delegate.Data {
+	return delegate.Data{
+		ID:        dl.EventID,
+		Domain:    dl.Domain,
+		Action:    dl.Action,
+		RawParams: dl.RawParams,
+		Target:    dl.Target,
+	}
+}
+
+func toDeadLetter(data delegate.Data, attempts int, cause error, now time.Time) DeadLetter {
+	return DeadLetter{
+		ID:          uuid.New(),
+		EventID:     data.ID,
+		Domain:      data.Domain,
+		Action:      data.Action,
+		RawParams:   data.RawParams,
+		Target:      data.Target,
+		Attempts:    attempts,
+		Error:       cause.Error(),
+		DateCreated: now,
+		DateUpdated: now,
+	}
+}
@@ -0,0 +1,155 @@
+// Package deadletterdb contains dead letter related CRUD functionality.
+package deadletterdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/domain/deadletterbus"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for dead letter database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new dead letter into the database.
+func (s *Store) Create(ctx context.Context, dl deadletterbus.DeadLetter) error {
+	const q = `
+	INSERT INTO dead_letters
+		(dead_letter_id, event_id, domain, action, params, target, attempts, error, date_created, date_updated, date_replayed)
+	VALUES
+		(:dead_letter_id, :event_id, :domain, :action, :params, :target, :attempts, :error, :date_created, :date_updated, :date_replayed)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDeadLetter(dl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update records the outcome of the last replay of a dead letter.
+func (s *Store) Update(ctx context.Context, dl deadletterbus.DeadLetter) error {
+	const q = `
+	UPDATE
+		dead_letters
+	SET
+		"attempts" = :attempts,
+		"error" = :error,
+		"date_updated" = :date_updated,
+		"date_replayed" = :date_replayed
+	WHERE
+		dead_letter_id = :dead_letter_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDeadLetter(dl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// MarkReplayed records the dead letter as replayed unless it already was. It
+// reports whether it was marked.
+func (s *Store) MarkReplayed(ctx context.Context, dl deadletterbus.DeadLetter) (bool, error) {
+	const q = `
+	UPDATE
+		dead_letters
+	SET
+		"attempts" = :attempts,
+		"error" = '',
+		"date_updated" = :date_updated,
+		"date_replayed" = :date_replayed
+	WHERE
+		dead_letter_id = :dead_letter_id AND
+		date_replayed IS NULL
+	RETURNING
+		dead_letter_id`
+
+	var dbMarked struct {
+		ID uuid.UUID `db:"dead_letter_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBDeadLetter(dl), &dbMarked); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return true, nil
+}
+
+// Query retrieves a list of existing dead letters from the database, newest
+// first.
+func (s *Store) Query(ctx context.Context, filter deadletterbus.QueryFilter, page page.Page) ([]deadletterbus.DeadLetter, error) {
+	qb := sqldb.NewBuilder("dead_letters", "dead_letter_id, event_id, domain, action, params, target, attempts, error, date_created, date_updated, date_replayed")
+	s.applyFilter(filter, qb)
+
+	qb.Bind("offset", (page.Number()-1)*page.RowsPerPage())
+	qb.Bind("rows_per_page", page.RowsPerPage())
+
+	q := qb.String() + " ORDER BY date_created DESC, dead_letter_id OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY"
+
+	var dbDls []deadLetter
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, qb.Data(), &dbDls); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusDeadLetters(dbDls), nil
+}
+
+// Count returns the total number of dead letters in the DB.
+func (s *Store) Count(ctx context.Context, filter deadletterbus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("dead_letters", "count(1)")
+	s.applyFilter(filter, qb)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, qb.String(), qb.Data(), &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID finds the dead letter identified by a given ID.
+func (s *Store) QueryByID(ctx context.Context, deadLetterID uuid.UUID) (deadletterbus.DeadLetter, error) {
+	data := struct {
+		ID string `db:"dead_letter_id"`
+	}{
+		ID: deadLetterID.String(),
+	}
+
+	const q = `
+	SELECT
+		dead_letter_id, event_id, domain, action, params, target, attempts, error, date_created, date_updated, date_replayed
+	FROM
+		dead_letters
+	WHERE
+		dead_letter_id = :dead_letter_id`
+
+	var dbDl deadLetter
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbDl); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return deadletterbus.DeadLetter{}, fmt.Errorf("db: %w", deadletterbus.ErrNotFound)
+		}
+		return deadletterbus.DeadLetter{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusDeadLetter(dbDl), nil
+}
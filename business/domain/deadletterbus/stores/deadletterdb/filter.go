@@ -0,0 +1,28 @@
+package deadletterdb
+
+import (
+	"github.com/ardanlabs/encore/business/domain/deadletterbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+func (s *Store) applyFilter(filter deadletterbus.QueryFilter, qb *sqldb.Builder) {
+	if filter.Domain != nil {
+		qb.Equal("domain", *filter.Domain)
+	}
+
+	if filter.Action != nil {
+		qb.Equal("action", *filter.Action)
+	}
+
+	if filter.Target != nil {
+		qb.Equal("target", *filter.Target)
+	}
+
+	if filter.Replayed != nil {
+		if *filter.Replayed {
+			qb.Where("date_replayed IS NOT NULL")
+		} else {
+			qb.IsNull("date_replayed")
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package deadletterdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/deadletterbus"
+	"github.com/google/uuid"
+)
+
+type deadLetter struct {
+	ID           uuid.UUID    `db:"dead_letter_id"`
+	EventID      uuid.UUID    `db:"event_id"`
+	Domain       string       `db:"domain"`
+	Action       string       `db:"action"`
+	Params       []byte       `db:"params"`
+	Target       string       `db:"target"`
+	Attempts     int          `db:"attempts"`
+	Error        string       `db:"error"`
+	DateCreated  time.Time    `db:"date_created"`
+	DateUpdated  time.Time    `db:"date_updated"`
+	DateReplayed sql.NullTime `db:"date_replayed"`
+}
+
+func toDBDeadLetter(bus deadletterbus.DeadLetter) deadLetter {
+	db := deadLetter{
+		ID:           bus.ID,
+		EventID:      bus.EventID,
+		Domain:       bus.Domain,
+		Action:       bus.Action,
+		Params:       bus.RawParams,
+		Target:       bus.Target,
+		Attempts:     bus.Attempts,
+		Error:        bus.Error,
+		DateCreated:  bus.DateCreated.UTC(),
+		DateUpdated:  bus.DateUpdated.UTC(),
+		DateReplayed: sql.NullTime{Time: bus.DateReplayed.UTC(), Valid: !bus.DateReplayed.IsZero()},
+	}
+
+	return db
+}
+
+func toBusDeadLetter(db deadLetter) deadletterbus.DeadLetter {
+	bus := deadletterbus.DeadLetter{
+		ID:          db.ID,
+		EventID:     db.EventID,
+		Domain:      db.Domain,
+		Action:      db.Action,
+		RawParams:   db.Params,
+		Target:      db.Target,
+		Attempts:    db.Attempts,
+		Error:       db.Error,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	if db.DateReplayed.Valid {
+		bus.DateReplayed = db.DateReplayed.Time.In(time.Local)
+	}
+
+	return bus
+}
+
+func toBusDeadLetters(dbs []deadLetter) []deadletterbus.DeadLetter {
+	bus := make([]deadletterbus.DeadLetter, len(dbs))
+
+	for i, db := range dbs {
+		bus[i] = toBusDeadLetter(db)
+	}
+
+	return bus
+}
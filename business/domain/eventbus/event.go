@@ -0,0 +1,103 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/google/uuid"
+)
+
+// changeActions holds the actions of each domain that are recorded in the
+// log, the ones that create, update or delete something.
+var changeActions = map[string][]string{
+	productbus.DomainName: {productbus.ActionCreated, productbus.ActionUpdated, productbus.ActionDeleted},
+	homebus.DomainName:    {homebus.ActionCreated, homebus.ActionUpdated, homebus.ActionDeleted},
+	userbus.DomainName:    {userbus.ActionCreated, userbus.ActionUpdated, userbus.ActionDeleted},
+}
+
+// Types returns the types of the events recorded in the log. A type is the
+// domain and action joined with a dot, like product.created.
+func Types() []string {
+	var types []string
+	for domain, actions := range changeActions {
+		for _, action := range actions {
+			types = append(types, Type(domain, action))
+		}
+	}
+	slices.Sort(types)
+
+	return types
+}
+
+// Type returns the type of the event for the domain and action.
+func Type(domain string, action string) string {
+	return domain + "." + action
+}
+
+// ParseChange returns the id of what was changed by the delegate data of a
+// created, updated or deleted action and the id of the user that owns it.
+// A user owns the events about themselves.
+func ParseChange(data delegate.Data) (entityID uuid.UUID, userID uuid.UUID, err error) {
+	if !slices.Contains(changeActions[data.Domain], data.Action) {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("not a change: %s", Type(data.Domain, data.Action))
+	}
+
+	switch data.Domain {
+	case productbus.DomainName:
+		var params productbus.ActionChangedParms
+		if err := json.Unmarshal(data.RawParams, &params); err != nil {
+			return uuid.Nil, uuid.Nil, fmt.Errorf("expected an encoded %T: %w", params, err)
+		}
+		return params.ProductID, params.UserID, nil
+
+	case homebus.DomainName:
+		var params homebus.ActionChangedParms
+		if err := json.Unmarshal(data.RawParams, &params); err != nil {
+			return uuid.Nil, uuid.Nil, fmt.Errorf("expected an encoded %T: %w", params, err)
+		}
+		return params.HomeID, params.UserID, nil
+
+	default:
+		// Updates carry userbus.ActionUpdatedParms, which holds the user id
+		// the same way.
+		var params userbus.ActionChangedParms
+		if err := json.Unmarshal(data.RawParams, &params); err != nil {
+			return uuid.Nil, uuid.Nil, fmt.Errorf("expected an encoded %T: %w", params, err)
+		}
+		return params.UserID, params.UserID, nil
+	}
+}
+
+// =============================================================================
+
+// registerDelegateFunctions will register action functions with the delegate
+// system. If the business was constructed for query only, there won't be a
+// delegate provided.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate == nil {
+		return
+	}
+
+	for domain, actions := range changeActions {
+		for _, action := range actions {
+			b.delegate.Register(domain, action, "eventbus.changed", b.actionChanged)
+		}
+	}
+}
+
+// actionChanged is executed by the other domains indirectly when something
+// in them is created, updated or deleted.
+func (b *Business) actionChanged(ctx context.Context, data delegate.Data) error {
+	entityID, userID, err := ParseChange(data)
+	if err != nil {
+		return err
+	}
+
+	return b.record(ctx, data, entityID, userID)
+}
@@ -0,0 +1,250 @@
+package eventbus_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/eventbus"
+	"github.com/ardanlabs/encore/business/domain/eventbus/stores/eventdb"
+	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Event(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, query(db.BusDomain, sd), "query")
+	unitest.Run(t, trim(db, sd), "trim")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, userbus.Roles.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	prd := productbus.Product{ID: uuid.New(), UserID: sd.Users[0].ID}
+	hme := homebus.Home{ID: uuid.New(), UserID: sd.Users[1].ID}
+
+	// record sends a change to a product and a home through the delegate
+	// and returns the id of the last event from before them.
+	record := func(ctx context.Context) (int64, error) {
+		lastID, err := busDomain.Event.QueryLastID(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := busDomain.Delegate.Call(ctx, productbus.ActionChangedData(productbus.ActionCreated, prd)); err != nil {
+			return 0, err
+		}
+
+		if err := busDomain.Delegate.Call(ctx, homebus.ActionChangedData(homebus.ActionUpdated, hme)); err != nil {
+			return 0, err
+		}
+
+		return lastID, nil
+	}
+
+	table := []unitest.Table{
+		{
+			Name: "all",
+			ExpResp: []eventbus.Event{
+				{
+					Domain:   productbus.DomainName,
+					Action:   productbus.ActionCreated,
+					EntityID: prd.ID,
+					UserID:   prd.UserID,
+				},
+				{
+					Domain:   homebus.DomainName,
+					Action:   homebus.ActionUpdated,
+					EntityID: hme.ID,
+					UserID:   hme.UserID,
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				lastID, err := record(ctx)
+				if err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Event.Query(ctx, eventbus.QueryFilter{}, lastID, 10)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: cmpEvents,
+		},
+		{
+			Name: "user",
+			ExpResp: []eventbus.Event{
+				{
+					Domain:   homebus.DomainName,
+					Action:   homebus.ActionUpdated,
+					EntityID: hme.ID,
+					UserID:   hme.UserID,
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				lastID, err := record(ctx)
+				if err != nil {
+					return err
+				}
+
+				filter := eventbus.QueryFilter{
+					UserID: &sd.Users[1].ID,
+				}
+
+				resp, err := busDomain.Event.Query(ctx, filter, lastID, 10)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: cmpEvents,
+		},
+		{
+			Name: "duplicate",
+			ExpResp: []eventbus.Event{
+				{
+					SourceID: prd.ID,
+					Domain:   productbus.DomainName,
+					Action:   productbus.ActionUpdated,
+					EntityID: prd.ID,
+					UserID:   prd.UserID,
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				lastID, err := busDomain.Event.QueryLastID(ctx)
+				if err != nil {
+					return err
+				}
+
+				// An event delivered more than once is recorded once.
+				data := productbus.ActionChangedData(productbus.ActionUpdated, prd)
+				data.ID = prd.ID
+
+				for range 2 {
+					if err := busDomain.Delegate.Call(ctx, data); err != nil {
+						return err
+					}
+				}
+
+				resp, err := busDomain.Event.Query(ctx, eventbus.QueryFilter{}, lastID, 10)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: cmpEvents,
+		},
+	}
+
+	return table
+}
+
+func trim(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	usrs := []userbus.User{sd.Users[0].User, sd.Users[1].User, sd.Users[0].User}
+
+	table := []unitest.Table{
+		{
+			Name: "size",
+			ExpResp: []eventbus.Event{
+				{
+					Domain:   userbus.DomainName,
+					Action:   userbus.ActionCreated,
+					EntityID: usrs[1].ID,
+					UserID:   usrs[1].ID,
+				},
+				{
+					Domain:   userbus.DomainName,
+					Action:   userbus.ActionCreated,
+					EntityID: usrs[2].ID,
+					UserID:   usrs[2].ID,
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				dlg := delegate.New(db.Log, nil, nil)
+				eventBus := eventbus.NewBusiness(db.Log, dlg, eventdb.NewStore(db.Log, db.DB), 2)
+
+				for _, usr := range usrs {
+					if err := dlg.Call(ctx, userbus.ActionChangedData(userbus.ActionCreated, usr)); err != nil {
+						return err
+					}
+				}
+
+				if err := eventBus.Trim(ctx); err != nil {
+					return err
+				}
+
+				resp, err := eventBus.Query(ctx, eventbus.QueryFilter{}, 0, 10)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: cmpEvents,
+		},
+	}
+
+	return table
+}
+
+// cmpEvents compares the events ignoring the values set when they are
+// recorded.
+func cmpEvents(got any, exp any) string {
+	gotResp, exists := got.([]eventbus.Event)
+	if !exists {
+		return "error occurred"
+	}
+
+	for i := range gotResp {
+		gotResp[i].ID = 0
+		gotResp[i].Data = nil
+		gotResp[i].DateCreated = time.Time{}
+	}
+
+	return cmp.Diff(gotResp, exp)
+}
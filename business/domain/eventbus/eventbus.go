@@ -0,0 +1,129 @@
+// Package eventbus provides business access to the log of changes made to
+// products, homes and users.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// DefaultSize is the number of events kept in the log when no size is
+// specified.
+const DefaultSize = 10_000
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, evt Event) (int64, error)
+	Query(ctx context.Context, filter QueryFilter, afterID int64, rows int) ([]Event, error)
+	QueryLastID(ctx context.Context) (int64, error)
+	Trim(ctx context.Context, size int) error
+}
+
+// Business manages the set of APIs for event access.
+type Business struct {
+	log      *logger.Logger
+	delegate *delegate.Delegate
+	storer   Storer
+	size     int
+
+	mu      sync.Mutex
+	changed chan struct{}
+}
+
+// NewBusiness constructs an event business API for use. The log is bounded
+// and keeps only the most recent size events once it's trimmed.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer, size int) *Business {
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	b := Business{
+		log:      log,
+		delegate: delegate,
+		storer:   storer,
+		size:     size,
+		changed:  make(chan struct{}),
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// Query retrieves the events recorded after the specified event in the order
+// they were recorded, returning no more than rows of them.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, afterID int64, rows int) ([]Event, error) {
+	evts, err := b.storer.Query(ctx, filter, afterID, rows)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return evts, nil
+}
+
+// QueryLastID returns the id of the last event recorded, or zero when the
+// log is empty.
+func (b *Business) QueryLastID(ctx context.Context) (int64, error) {
+	id, err := b.storer.QueryLastID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("querylastid: %w", err)
+	}
+
+	return id, nil
+}
+
+// Trim drops the oldest events beyond the size of the log.
+func (b *Business) Trim(ctx context.Context) error {
+	if err := b.storer.Trim(ctx, b.size); err != nil {
+		return fmt.Errorf("trim: %w", err)
+	}
+
+	return nil
+}
+
+// Changed returns a channel that is closed when this instance next records
+// an event. Events recorded by other instances of the service don't close
+// it, so readers still need to check the log from time to time.
+func (b *Business) Changed() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.changed
+}
+
+// record adds the event described by the delegate data to the log. An event
+// with an id is only recorded the first time it's delivered.
+func (b *Business) record(ctx context.Context, data delegate.Data, entityID uuid.UUID, userID uuid.UUID) error {
+	evt := Event{
+		SourceID:    data.ID,
+		Domain:      data.Domain,
+		Action:      data.Action,
+		EntityID:    entityID,
+		UserID:      userID,
+		Data:        data.RawParams,
+		DateCreated: time.Now(),
+	}
+
+	id, err := b.storer.Create(ctx, evt)
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	if id == 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	close(b.changed)
+	b.changed = make(chan struct{})
+	b.mu.Unlock()
+
+	return nil
+}
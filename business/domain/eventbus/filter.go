@@ -0,0 +1,9 @@
+package eventbus
+
+import "github.com/google/uuid"
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	UserID *uuid.UUID
+}
@@ -0,0 +1,21 @@
+package eventbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event represents a change made to something in one of the domains. Events
+// are numbered in the order they are recorded. SourceID is the id of the
+// delegate event it was recorded from, when that event had one.
+type Event struct {
+	ID          int64
+	SourceID    uuid.UUID
+	Domain      string
+	Action      string
+	EntityID    uuid.UUID
+	UserID      uuid.UUID
+	Data        []byte
+	DateCreated time.Time
+}
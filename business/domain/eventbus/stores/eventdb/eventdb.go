@@ -0,0 +1,120 @@
+// Package eventdb contains event related CRUD functionality.
+package eventdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/domain/eventbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for event database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new event into the database and returns the id it was
+// given. The inserts take a lock held until they commit, so the events are
+// committed in the order of their ids and a reader resuming after an id
+// can't miss an event that was still being written. An event with a source
+// that was already recorded isn't inserted again and zero is returned.
+func (s *Store) Create(ctx context.Context, evt eventbus.Event) (int64, error) {
+	const q = `
+	WITH lock AS (
+		SELECT pg_advisory_xact_lock(hashtext('events'))
+	)
+	INSERT INTO events
+		(source_id, domain, action, entity_id, user_id, data, date_created)
+	SELECT
+		:source_id, :domain, :action, :entity_id, :user_id, :data, :date_created
+	FROM
+		lock
+	ON CONFLICT (source_id) DO NOTHING
+	RETURNING
+		event_id`
+
+	var dbEvt struct {
+		ID int64 `db:"event_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBEvent(evt), &dbEvt); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return dbEvt.ID, nil
+}
+
+// Query retrieves the events after the specified event from the database in
+// the order they were recorded.
+func (s *Store) Query(ctx context.Context, filter eventbus.QueryFilter, afterID int64, rows int) ([]eventbus.Event, error) {
+	qb := sqldb.NewBuilder("events", "event_id, source_id, domain, action, entity_id, user_id, data, date_created")
+	s.applyFilter(filter, qb)
+
+	qb.Where("event_id > :after_id")
+	qb.Bind("after_id", afterID)
+	qb.Bind("rows", rows)
+
+	q := qb.String() + " ORDER BY event_id LIMIT :rows"
+
+	var dbEvts []event
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, qb.Data(), &dbEvts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusEvents(dbEvts), nil
+}
+
+// QueryLastID returns the id of the last event in the database.
+func (s *Store) QueryLastID(ctx context.Context) (int64, error) {
+	const q = `
+	SELECT
+		COALESCE(MAX(event_id), 0) AS event_id
+	FROM
+		events`
+
+	var dbEvt struct {
+		ID int64 `db:"event_id"`
+	}
+	if err := sqldb.QueryStruct(ctx, s.log, s.db, q, &dbEvt); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return dbEvt.ID, nil
+}
+
+// Trim removes the events older than the last size events from the
+// database.
+func (s *Store) Trim(ctx context.Context, size int) error {
+	data := struct {
+		Size int `db:"size"`
+	}{
+		Size: size,
+	}
+
+	const q = `
+	DELETE FROM
+		events
+	WHERE
+		event_id <= (SELECT MAX(event_id) FROM events) - :size`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
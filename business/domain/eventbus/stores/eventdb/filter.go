@@ -0,0 +1,12 @@
+package eventdb
+
+import (
+	"github.com/ardanlabs/encore/business/domain/eventbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+func (s *Store) applyFilter(filter eventbus.QueryFilter, qb *sqldb.Builder) {
+	if filter.UserID != nil {
+		qb.Equal("user_id", *filter.UserID)
+	}
+}
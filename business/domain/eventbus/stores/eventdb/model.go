@@ -0,0 +1,62 @@
+package eventdb
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/eventbus"
+	"github.com/google/uuid"
+)
+
+type event struct {
+	ID          int64         `db:"event_id"`
+	SourceID    uuid.NullUUID `db:"source_id"`
+	Domain      string        `db:"domain"`
+	Action      string        `db:"action"`
+	EntityID    uuid.UUID     `db:"entity_id"`
+	UserID      uuid.UUID     `db:"user_id"`
+	Data        []byte        `db:"data"`
+	DateCreated time.Time     `db:"date_created"`
+}
+
+func toDBEvent(bus eventbus.Event) event {
+	db := event{
+		ID: bus.ID,
+		SourceID: uuid.NullUUID{
+			UUID:  bus.SourceID,
+			Valid: bus.SourceID != uuid.Nil,
+		},
+		Domain:      bus.Domain,
+		Action:      bus.Action,
+		EntityID:    bus.EntityID,
+		UserID:      bus.UserID,
+		Data:        bus.Data,
+		DateCreated: bus.DateCreated.UTC(),
+	}
+
+	return db
+}
+
+func toBusEvent(db event) eventbus.Event {
+	bus := eventbus.Event{
+		ID:          db.ID,
+		SourceID:    db.SourceID.UUID,
+		Domain:      db.Domain,
+		Action:      db.Action,
+		EntityID:    db.EntityID,
+		UserID:      db.UserID,
+		Data:        db.Data,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus
+}
+
+func toBusEvents(dbs []event) []eventbus.Event {
+	bus := make([]eventbus.Event, len(dbs))
+
+	for i, db := range dbs {
+		bus[i] = toBusEvent(db)
+	}
+
+	return bus
+}
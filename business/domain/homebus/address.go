@@ -0,0 +1,50 @@
+package homebus
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidAddress is matched by an AddressError using errors.Is.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// AddressValidator declares the behavior needed to check an address against
+// the rules of its country.
+type AddressValidator interface {
+	ValidateAddress(addr Address) error
+}
+
+// FieldError describes the problem found with a single field of an address
+// or of the attributes of a home. The field is named after the struct field.
+type FieldError struct {
+	Field string
+	Err   string
+}
+
+// AddressError is returned when an address breaks the rules of its country.
+type AddressError struct {
+	Fields []FieldError
+}
+
+// Add records a problem with the specified field.
+func (ae *AddressError) Add(field string, msg string) {
+	ae.Fields = append(ae.Fields, FieldError{
+		Field: field,
+		Err:   msg,
+	})
+}
+
+// Error implements the error interface.
+func (ae *AddressError) Error() string {
+	msgs := make([]string, len(ae.Fields))
+	for i, fld := range ae.Fields {
+		msgs[i] = fld.Field + ": " + fld.Err
+	}
+
+	return "invalid address: " + strings.Join(msgs, ", ")
+}
+
+// Is allows the error to be compared with ErrInvalidAddress.
+func (ae *AddressError) Is(target error) bool {
+	return target == ErrInvalidAddress
+}
@@ -0,0 +1,121 @@
+// Package address provides an address validator for the home domain with
+// rules for each ISO 3166 country it knows about.
+package address
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ardanlabs/encore/business/domain/homebus"
+)
+
+// The rules for each country keyed by the ISO 3166-1 alpha-2 code.
+//
+//go:embed countries.json
+var countriesJSON []byte
+
+// Set of policies for the state field of an address.
+const (
+	stateRequired  = "required"
+	stateOptional  = "optional"
+	stateForbidden = "forbidden"
+)
+
+type rule struct {
+	Postcode string   `json:"postcode"`
+	State    string   `json:"state"`
+	States   []string `json:"states"`
+}
+
+type country struct {
+	postcode *regexp.Regexp
+	state    string
+	states   map[string]bool
+}
+
+// countries is built once from the embedded data. Broken data is a bug in
+// the program so it panics like regexp.MustCompile.
+var countries = mustParseCountries(countriesJSON)
+
+func mustParseCountries(data []byte) map[string]country {
+	var rules map[string]rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		panic(fmt.Sprintf("address: parsing countries: %s", err))
+	}
+
+	m := make(map[string]country, len(rules))
+	for code, r := range rules {
+		switch r.State {
+		case stateRequired, stateOptional, stateForbidden:
+		default:
+			panic(fmt.Sprintf("address: country %s: unknown state policy %q", code, r.State))
+		}
+
+		c := country{
+			postcode: regexp.MustCompile(r.Postcode),
+			state:    r.State,
+			states:   make(map[string]bool, len(r.States)),
+		}
+
+		for _, s := range r.States {
+			c.states[s] = true
+		}
+
+		m[code] = c
+	}
+
+	return m
+}
+
+// =============================================================================
+
+// Validator checks addresses against the rules of their country. Countries
+// without rules only need a postal code.
+type Validator struct{}
+
+// NewValidator constructs a validator using the embedded country rules.
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// ValidateAddress implements the homebus.AddressValidator interface. All the
+// problems found are returned together in a homebus.AddressError.
+func (v *Validator) ValidateAddress(addr homebus.Address) error {
+	var ae homebus.AddressError
+
+	code := strings.ToUpper(addr.Country)
+	zip := strings.ToUpper(strings.TrimSpace(addr.ZipCode))
+	state := strings.ToUpper(strings.TrimSpace(addr.State))
+
+	c, exists := countries[code]
+
+	switch {
+	case zip == "":
+		ae.Add("ZipCode", "postal code is required")
+
+	case exists && !c.postcode.MatchString(zip):
+		ae.Add("ZipCode", fmt.Sprintf("invalid postal code for %s", code))
+	}
+
+	if exists {
+		switch {
+		case c.state == stateRequired && state == "":
+			ae.Add("State", fmt.Sprintf("state is required for %s", code))
+
+		case c.state == stateForbidden && state != "":
+			ae.Add("State", fmt.Sprintf("state is not used in %s", code))
+
+		case state != "" && len(c.states) > 0 && !c.states[state]:
+			ae.Add("State", fmt.Sprintf("unknown state %q for %s", addr.State, code))
+		}
+	}
+
+	if len(ae.Fields) > 0 {
+		return &ae
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package address_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/homebus/address"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ValidateAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		addr   homebus.Address
+		fields []string
+	}{
+		{"us", homebus.Address{ZipCode: "35810", State: "AL", Country: "US"}, nil},
+		{"us-zip4", homebus.Address{ZipCode: "35810-1234", State: "al", Country: "US"}, nil},
+		{"us-state", homebus.Address{ZipCode: "35810", State: "XX", Country: "US"}, []string{"State"}},
+		{"us-nostate", homebus.Address{ZipCode: "35810", Country: "US"}, []string{"State"}},
+		{"gb", homebus.Address{ZipCode: "SW1A 1AA", Country: "GB"}, nil},
+		{"ca", homebus.Address{ZipCode: "k1a 0b1", State: "ON", Country: "CA"}, nil},
+		{"nl", homebus.Address{ZipCode: "1012 JS", Country: "NL"}, nil},
+		{"nl-state", homebus.Address{ZipCode: "1012JS", State: "NH", Country: "NL"}, []string{"State"}},
+		{"de-zip", homebus.Address{ZipCode: "1012", Country: "DE"}, []string{"ZipCode"}},
+		{"unknown", homebus.Address{ZipCode: "ANY-1", Country: "ZZ"}, nil},
+		{"nozip", homebus.Address{State: "AL", Country: "US"}, []string{"ZipCode"}},
+	}
+
+	v := address.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateAddress(tt.addr)
+
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("Should be able to validate the address : %s", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, homebus.ErrInvalidAddress) {
+				t.Fatalf("Should get an invalid address error : %v", err)
+			}
+
+			var ae *homebus.AddressError
+			if !errors.As(err, &ae) {
+				t.Fatalf("Should get an address error : %v", err)
+			}
+
+			var fields []string
+			for _, fld := range ae.Fields {
+				fields = append(fields, fld.Field)
+			}
+
+			if diff := cmp.Diff(fields, tt.fields); diff != "" {
+				t.Errorf("Should get the expected fields :\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package homebus
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrInvalidAttributes is matched by an AttributeError using errors.Is.
+var ErrInvalidAttributes = errors.New("invalid attributes")
+
+// Attributes represents the physical characteristics of a home. A zero value
+// means the attribute is unknown.
+type Attributes struct {
+	Bedrooms   int
+	Bathrooms  float64
+	SquareFeet int
+	YearBuilt  int
+	Units      int
+}
+
+// UpdateAttributes is what attributes can be updated in the store.
+type UpdateAttributes struct {
+	Bedrooms   *int
+	Bathrooms  *float64
+	SquareFeet *int
+	YearBuilt  *int
+	Units      *int
+}
+
+// AttributeError is returned when the attributes of a home break the rules
+// of its type.
+type AttributeError struct {
+	Fields []FieldError
+}
+
+// Add records a problem with the specified field.
+func (ae *AttributeError) Add(field string, msg string) {
+	ae.Fields = append(ae.Fields, FieldError{
+		Field: field,
+		Err:   msg,
+	})
+}
+
+// Error implements the error interface.
+func (ae *AttributeError) Error() string {
+	msgs := make([]string, len(ae.Fields))
+	for i, fld := range ae.Fields {
+		msgs[i] = fld.Field + ": " + fld.Err
+	}
+
+	return "invalid attributes: " + strings.Join(msgs, ", ")
+}
+
+// Is allows the error to be compared with ErrInvalidAttributes.
+func (ae *AttributeError) Is(target error) bool {
+	return target == ErrInvalidAttributes
+}
+
+// =============================================================================
+
+// Limits that apply to every type of home.
+const (
+	maxBathrooms   = 50
+	maxSquareFeet  = 1_000_000
+	minYearBuilt   = 1600
+	yearsInAdvance = 5
+)
+
+// attributeRule holds the limits that depend on the type of home.
+type attributeRule struct {
+	maxBedrooms int
+	minUnits    int
+	maxUnits    int
+}
+
+// validateAttributes checks the attributes against the rules of the type.
+func validateAttributes(typ Type, attrs Attributes) error {
+	var ae AttributeError
+
+	rule := attributeRules[typ.name]
+
+	if attrs.Bedrooms < 0 || attrs.Bedrooms > rule.maxBedrooms {
+		ae.Add("Bedrooms", fmt.Sprintf("must be between 0 and %d for %s", rule.maxBedrooms, typ.name))
+	}
+
+	switch {
+	case attrs.Bathrooms < 0 || attrs.Bathrooms > maxBathrooms:
+		ae.Add("Bathrooms", fmt.Sprintf("must be between 0 and %d", maxBathrooms))
+	case math.Mod(attrs.Bathrooms*2, 1) != 0:
+		ae.Add("Bathrooms", "must be a multiple of 0.5")
+	}
+
+	if attrs.SquareFeet < 0 || attrs.SquareFeet > maxSquareFeet {
+		ae.Add("SquareFeet", fmt.Sprintf("must be between 0 and %d", maxSquareFeet))
+	}
+
+	maxYear := time.Now().Year() + yearsInAdvance
+	if attrs.YearBuilt != 0 && (attrs.YearBuilt < minYearBuilt || attrs.YearBuilt > maxYear) {
+		ae.Add("YearBuilt", fmt.Sprintf("must be between %d and %d", minYearBuilt, maxYear))
+	}
+
+	if attrs.Units != 0 && (attrs.Units < rule.minUnits || attrs.Units > rule.maxUnits) {
+		msg := fmt.Sprintf("must be between %d and %d for %s", rule.minUnits, rule.maxUnits, typ.name)
+		if rule.minUnits == rule.maxUnits {
+			msg = fmt.Sprintf("must be %d for %s", rule.minUnits, typ.name)
+		}
+		ae.Add("Units", msg)
+	}
+
+	if len(ae.Fields) > 0 {
+		return &ae
+	}
+
+	return nil
+}
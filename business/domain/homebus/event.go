@@ -1 +1,97 @@
 package homebus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/google/uuid"
+)
+
+// DomainName represents the name of this domain.
+const DomainName = "home"
+
+// Set of delegate actions.
+const (
+	ActionCreated     = "created"
+	ActionUpdated     = "updated"
+	ActionDeleted     = "deleted"
+	ActionTransferred = "transferred"
+)
+
+// ActionChangedParms represents the parameters for the created, updated and
+// deleted actions.
+type ActionChangedParms struct {
+	HomeID uuid.UUID
+	UserID uuid.UUID
+}
+
+// String returns a string representation of the action parameters.
+func (ac *ActionChangedParms) String() string {
+	return fmt.Sprintf("&EventParamsChanged{HomeID:%v, UserID:%v}", ac.HomeID, ac.UserID)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (ac *ActionChangedParms) Marshal() ([]byte, error) {
+	return json.Marshal(ac)
+}
+
+// ActionChangedData constructs the data for the created, updated or deleted
+// action specified.
+func ActionChangedData(action string, hme Home) delegate.Data {
+	params := ActionChangedParms{
+		HomeID: hme.ID,
+		UserID: hme.UserID,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    action,
+		RawParams: rawParams,
+	}
+}
+
+// ActionTransferredParms represents the parameters for the transferred
+// action.
+type ActionTransferredParms struct {
+	HomeID     uuid.UUID
+	TransferID uuid.UUID
+	FromUserID uuid.UUID
+	ToUserID   uuid.UUID
+}
+
+// String returns a string representation of the action parameters.
+func (at *ActionTransferredParms) String() string {
+	return fmt.Sprintf("&EventParamsTransferred{HomeID:%v, FromUserID:%v, ToUserID:%v}", at.HomeID, at.FromUserID, at.ToUserID)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (at *ActionTransferredParms) Marshal() ([]byte, error) {
+	return json.Marshal(at)
+}
+
+// ActionTransferredData constructs the data for the transferred action.
+func ActionTransferredData(trn Transfer) delegate.Data {
+	params := ActionTransferredParms{
+		HomeID:     trn.HomeID,
+		TransferID: trn.ID,
+		FromUserID: trn.FromUserID,
+		ToUserID:   trn.ToUserID,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    ActionTransferred,
+		RawParams: rawParams,
+	}
+}
@@ -0,0 +1,44 @@
+package homebus
+
+import (
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
+	"github.com/ardanlabs/encore/business/sdk/order"
+)
+
+// Set of fields a query can be limited to. The address, attributes and
+// location each stand for the group of values that make them up.
+const (
+	FieldID          = "home_id"
+	FieldUserID      = "user_id"
+	FieldType        = "type"
+	FieldAddress     = "address"
+	FieldAttributes  = "attributes"
+	FieldLocation    = "location"
+	FieldDateCreated = "date_created"
+	FieldDateUpdated = "date_updated"
+)
+
+// orderFields maps the fields results can be ordered by to the field that
+// holds their value. Distance is worked out by every query.
+var orderFields = map[string]string{
+	OrderByID:         FieldID,
+	OrderByType:       FieldType,
+	OrderByUserID:     FieldUserID,
+	OrderByBedrooms:   FieldAttributes,
+	OrderByBathrooms:  FieldAttributes,
+	OrderBySquareFeet: FieldAttributes,
+	OrderByYearBuilt:  FieldAttributes,
+	OrderByUnits:      FieldAttributes,
+}
+
+// withOrderFields adds the fields holding the values the results are ordered
+// by to the set.
+func withOrderFields(fields fieldset.Set, orderBy order.By) fieldset.Set {
+	for _, key := range orderBy.Keys() {
+		if field, exists := orderFields[key.Field]; exists {
+			fields = fields.With(field)
+		}
+	}
+
+	return fields
+}
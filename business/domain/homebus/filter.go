@@ -3,15 +3,26 @@ package homebus
 import (
 	"time"
 
+	"github.com/ardanlabs/encore/business/sdk/filter"
 	"github.com/google/uuid"
 )
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
 type QueryFilter struct {
-	ID               *uuid.UUID
+	ID               []filter.Condition[uuid.UUID]
 	UserID           *uuid.UUID
 	Type             *Type
 	StartCreatedDate *time.Time
 	EndCreatedDate   *time.Time
+
+	// Near matches homes with a location inside the area.
+	Near *Area
+
+	// Attribute conditions, homes match when every condition holds.
+	Bedrooms   []filter.Condition[int]
+	Bathrooms  []filter.Condition[float64]
+	SquareFeet []filter.Condition[int]
+	YearBuilt  []filter.Condition[int]
+	Units      []filter.Condition[int]
 }
@@ -0,0 +1,11 @@
+// Package geocode provides geocoders that find the location of a home address.
+package geocode
+
+import "strings"
+
+// key normalizes the parts of an address used to look up a location so
+// "sw1a 1aa" and "SW1A1AA" are treated the same.
+func key(country string, zipCode string) string {
+	zip := strings.ToUpper(strings.ReplaceAll(zipCode, " ", ""))
+	return strings.ToUpper(country) + ":" + zip
+}
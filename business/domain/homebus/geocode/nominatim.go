@@ -0,0 +1,89 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/homebus"
+)
+
+// Nominatim places addresses using a Nominatim compatible search service
+// such as the one run by OpenStreetMap.
+type Nominatim struct {
+	url       string
+	userAgent string
+	client    *http.Client
+}
+
+// NewNominatim constructs a geocoder for the service at the specified url.
+// Public instances require a user agent that identifies the application.
+func NewNominatim(url string, userAgent string) *Nominatim {
+	return &Nominatim{
+		url:       strings.TrimSuffix(url, "/"),
+		userAgent: userAgent,
+		client: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// Geocode implements the homebus.Geocoder interface.
+func (n *Nominatim) Geocode(ctx context.Context, addr homebus.Address) (homebus.Location, error) {
+	v := url.Values{}
+	v.Set("format", "json")
+	v.Set("limit", "1")
+	v.Set("street", addr.Address1)
+	v.Set("city", addr.City)
+	v.Set("state", addr.State)
+	v.Set("postalcode", addr.ZipCode)
+	v.Set("countrycodes", strings.ToLower(addr.Country))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.url+"/search?"+v.Encode(), nil)
+	if err != nil {
+		return homebus.Location{}, fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if n.userAgent != "" {
+		req.Header.Set("User-Agent", n.userAgent)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return homebus.Location{}, fmt.Errorf("do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return homebus.Location{}, fmt.Errorf("geocode status: %d", resp.StatusCode)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return homebus.Location{}, fmt.Errorf("decode: %w", err)
+	}
+
+	if len(results) == 0 {
+		return homebus.Location{}, homebus.ErrLocationNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return homebus.Location{}, fmt.Errorf("parse latitude: %w", err)
+	}
+
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return homebus.Location{}, fmt.Errorf("parse longitude: %w", err)
+	}
+
+	return homebus.Location{Latitude: lat, Longitude: lng}, nil
+}
@@ -2,6 +2,7 @@ package homebus_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"testing"
@@ -11,6 +12,9 @@ import (
 	"github.com/ardanlabs/encore/business/domain/homebus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
+	"github.com/ardanlabs/encore/business/sdk/filter"
+	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/ardanlabs/encore/business/sdk/unitest"
 	"github.com/google/go-cmp/cmp"
@@ -36,6 +40,10 @@ func Test_Home(t *testing.T) {
 	unitest.Run(t, query(db.BusDomain, sd), "query")
 	unitest.Run(t, create(db.BusDomain, sd), "create")
 	unitest.Run(t, update(db.BusDomain, sd), "update")
+	unitest.Run(t, near(db.BusDomain, sd), "near")
+	unitest.Run(t, attributes(db.BusDomain, sd), "attributes")
+	unitest.Run(t, member(db.BusDomain, sd), "member")
+	unitest.Run(t, transfer(db.BusDomain, sd), "transfer")
 	unitest.Run(t, delete(db.BusDomain, sd), "delete")
 }
 
@@ -124,7 +132,7 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 			Name:    "all",
 			ExpResp: hmes,
 			ExcFunc: func(ctx context.Context) any {
-				resp, err := busDomain.Home.Query(ctx, homebus.QueryFilter{}, homebus.DefaultOrderBy, page.MustParse("1", "10"))
+				resp, err := busDomain.Home.Query(ctx, homebus.QueryFilter{}, fieldset.Set{}, homebus.DefaultOrderBy, page.MustParse("1", "10"))
 				if err != nil {
 					return err
 				}
@@ -201,6 +209,10 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 					State:    "AL",
 					Country:  "US",
 				},
+				Location: homebus.Location{
+					Latitude:  34.7784,
+					Longitude: -86.5879,
+				},
 			},
 			ExcFunc: func(ctx context.Context) any {
 				nh := homebus.NewHome{
@@ -237,6 +249,34 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "address",
+			ExpResp: homebus.ErrInvalidAddress,
+			ExcFunc: func(ctx context.Context) any {
+				nh := homebus.NewHome{
+					UserID: sd.Users[0].ID,
+					Type:   homebus.Types.Condo,
+					Address: homebus.Address{
+						Address1: "Prinsengracht 263",
+						ZipCode:  "1016 GV",
+						City:     "Amsterdam",
+						State:    "NH",
+						Country:  "NL",
+					},
+				}
+
+				_, err := busDomain.Home.Create(ctx, nh)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
 	}
 
 	return table
@@ -258,6 +298,10 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 					State:    "AL",
 					Country:  "US",
 				},
+				Location: homebus.Location{
+					Latitude:  34.7784,
+					Longitude: -86.5879,
+				},
 				DateCreated: sd.Users[0].Homes[0].DateCreated,
 				DateUpdated: sd.Users[0].Homes[0].DateCreated,
 			},
@@ -301,6 +345,424 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	return table
 }
 
+func near(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "distance",
+			ExpResp: []string{"35801", "35810"},
+			ExcFunc: func(ctx context.Context) any {
+				addrs := []homebus.Address{
+					homebus.ParseAddress("123 Mocking Bird Lane", "", "35810", "Huntsville", "AL", "US"),
+					homebus.ParseAddress("350 Fifth Avenue", "", "10118", "New York", "NY", "US"),
+					homebus.ParseAddress("308 Fountain Circle", "", "35801", "Huntsville", "AL", "US"),
+				}
+
+				for _, addr := range addrs {
+					nh := homebus.NewHome{
+						UserID:  sd.Users[1].ID,
+						Type:    homebus.Types.Single,
+						Address: addr,
+					}
+
+					if _, err := busDomain.Home.Create(ctx, nh); err != nil {
+						return err
+					}
+				}
+
+				filter := homebus.QueryFilter{
+					UserID: &sd.Users[1].ID,
+					Near: &homebus.Area{
+						Center: homebus.Location{
+							Latitude:  34.7304,
+							Longitude: -86.5861,
+						},
+						RadiusKM: 20,
+					},
+				}
+
+				orderBy := order.NewBy(homebus.OrderByDistance, order.ASC)
+
+				hmes, err := busDomain.Home.Query(ctx, filter, fieldset.Set{}, orderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				zipCodes := make([]string, len(hmes))
+				for i, hme := range hmes {
+					zipCodes[i] = hme.Address.ZipCode
+				}
+
+				return zipCodes
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "withoutnear",
+			ExpResp: homebus.ErrDistanceWithoutNear,
+			ExcFunc: func(ctx context.Context) any {
+				orderBy := order.NewBy(homebus.OrderByDistance, order.ASC)
+
+				_, err := busDomain.Home.Query(ctx, homebus.QueryFilter{}, fieldset.Set{}, orderBy, page.MustParse("1", "10"))
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func attributes(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	addr := homebus.ParseAddress("123 Mocking Bird Lane", "", "35810", "Huntsville", "AL", "US")
+
+	table := []unitest.Table{
+		{
+			Name:    "range",
+			ExpResp: []int{3, 4},
+			ExcFunc: func(ctx context.Context) any {
+				for _, bedrooms := range []int{2, 3, 4, 5} {
+					nh := homebus.NewHome{
+						UserID:  sd.Admins[1].ID,
+						Type:    homebus.Types.Townhouse,
+						Address: addr,
+						Attributes: homebus.Attributes{
+							Bedrooms:   bedrooms,
+							Bathrooms:  1.5,
+							SquareFeet: 400 * bedrooms,
+							YearBuilt:  1990,
+						},
+					}
+
+					if _, err := busDomain.Home.Create(ctx, nh); err != nil {
+						return err
+					}
+				}
+
+				filter := homebus.QueryFilter{
+					UserID: &sd.Admins[1].ID,
+					Bedrooms: []filter.Condition[int]{
+						filter.Compare(filter.Operators.GT, 2),
+						filter.Compare(filter.Operators.LTE, 4),
+					},
+				}
+
+				orderBy := order.NewBy(homebus.OrderBySquareFeet, order.ASC)
+
+				hmes, err := busDomain.Home.Query(ctx, filter, fieldset.Set{}, orderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				bedrooms := make([]int, len(hmes))
+				for i, hme := range hmes {
+					bedrooms[i] = hme.Attributes.Bedrooms
+				}
+
+				return bedrooms
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "units",
+			ExpResp: homebus.ErrInvalidAttributes,
+			ExcFunc: func(ctx context.Context) any {
+				nh := homebus.NewHome{
+					UserID:  sd.Admins[1].ID,
+					Type:    homebus.Types.MultiFamily,
+					Address: addr,
+					Attributes: homebus.Attributes{
+						Units: 1,
+					},
+				}
+
+				_, err := busDomain.Home.Create(ctx, nh)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "type",
+			ExpResp: homebus.ErrInvalidAttributes,
+			ExcFunc: func(ctx context.Context) any {
+				nh := homebus.NewHome{
+					UserID:  sd.Admins[1].ID,
+					Type:    homebus.Types.MultiFamily,
+					Address: addr,
+					Attributes: homebus.Attributes{
+						Bedrooms: 12,
+						Units:    4,
+					},
+				}
+
+				hme, err := busDomain.Home.Create(ctx, nh)
+				if err != nil {
+					return err
+				}
+
+				uh := homebus.UpdateHome{
+					Type: &homebus.Types.Condo,
+				}
+
+				_, err = busDomain.Home.Update(ctx, hme, uh)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func member(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	hme := sd.Users[0].Homes[0]
+	usr := sd.Users[1].User
+
+	table := []unitest.Table{
+		{
+			Name:    "add",
+			ExpResp: homebus.Roles.Editor,
+			ExcFunc: func(ctx context.Context) any {
+				nm := homebus.NewMember{
+					UserID: usr.ID,
+					Role:   homebus.Roles.Editor,
+				}
+
+				if _, err := busDomain.Home.AddMember(ctx, hme, nm); err != nil {
+					return err
+				}
+
+				role, err := busDomain.Home.QueryRole(ctx, hme, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				return role
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "exists",
+			ExpResp: homebus.ErrMemberExists,
+			ExcFunc: func(ctx context.Context) any {
+				nm := homebus.NewMember{
+					UserID: usr.ID,
+					Role:   homebus.Roles.Viewer,
+				}
+
+				_, err := busDomain.Home.AddMember(ctx, hme, nm)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "owner",
+			ExpResp: homebus.ErrOwnerMember,
+			ExcFunc: func(ctx context.Context) any {
+				nm := homebus.NewMember{
+					UserID: usr.ID,
+					Role:   homebus.Roles.Owner,
+				}
+
+				_, err := busDomain.Home.AddMember(ctx, hme, nm)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "members",
+			ExpResp: []homebus.Role{homebus.Roles.Owner, homebus.Roles.Editor},
+			ExcFunc: func(ctx context.Context) any {
+				mems, err := busDomain.Home.QueryMembers(ctx, hme)
+				if err != nil {
+					return err
+				}
+
+				roles := make([]homebus.Role, len(mems))
+				for i, mem := range mems {
+					roles[i] = mem.Role
+				}
+
+				return roles
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "remove",
+			ExpResp: homebus.ErrMemberNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Home.RemoveMember(ctx, hme, usr.ID); err != nil {
+					return err
+				}
+
+				_, err := busDomain.Home.QueryRole(ctx, hme, usr.ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func transfer(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	hme := sd.Admins[0].Homes[0]
+	usr := sd.Users[1].User
+
+	table := []unitest.Table{
+		{
+			Name:    "owner",
+			ExpResp: homebus.ErrTransferToOwner,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Home.RequestTransfer(ctx, hme, hme.UserID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "recipient",
+			ExpResp: homebus.ErrTransferRecipient,
+			ExcFunc: func(ctx context.Context) any {
+				trn, err := busDomain.Home.RequestTransfer(ctx, hme, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Home.AcceptTransfer(ctx, trn, hme.UserID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "expired",
+			ExpResp: homebus.ErrTransferExpired,
+			ExcFunc: func(ctx context.Context) any {
+				trn, err := busDomain.Home.RequestTransfer(ctx, hme, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				trn.DateExpires = time.Now().Add(-time.Minute)
+
+				_, err = busDomain.Home.AcceptTransfer(ctx, trn, usr.ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name: "accept",
+			ExpResp: homebus.Home{
+				ID:          hme.ID,
+				UserID:      usr.ID,
+				Type:        hme.Type,
+				Address:     hme.Address,
+				DateCreated: hme.DateCreated,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				trn, err := busDomain.Home.RequestTransfer(ctx, hme, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Home.AcceptTransfer(ctx, trn, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(homebus.Home)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(homebus.Home)
+
+				if gotResp.DateCreated.Format(time.RFC3339) == expResp.DateCreated.Format(time.RFC3339) {
+					expResp.DateCreated = gotResp.DateCreated
+				}
+
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
 func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	table := []unitest.Table{
 		{
@@ -7,8 +7,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/bulk"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
@@ -20,41 +23,80 @@ import (
 var (
 	ErrNotFound     = errors.New("home not found")
 	ErrUserDisabled = errors.New("user disabled")
+
+	ErrDistanceWithoutNear = errors.New("ordering by distance requires a near filter")
+)
+
+// Set of error variables for sharing a home.
+var (
+	ErrMemberNotFound = errors.New("member not found")
+	ErrMemberExists   = errors.New("user is already a member of the home")
+	ErrOwnerMember    = errors.New("the owner membership can't be changed")
 )
 
+// Set of error variables for transferring a home.
+var (
+	ErrTransferNotFound   = errors.New("transfer not found")
+	ErrTransferToOwner    = errors.New("home is already owned by the recipient")
+	ErrTransferRecipient  = errors.New("transfer is addressed to another user")
+	ErrTransferExpired    = errors.New("transfer has expired")
+	ErrTransferNotPending = errors.New("transfer is no longer pending")
+)
+
+// TransferExpiry is how long a recipient has to accept a transfer.
+const TransferExpiry = 72 * time.Hour
+
 // Storer interface declares the behaviour this package needs to persist and
 // retrieve data.
 type Storer interface {
 	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
 	Create(ctx context.Context, hme Home) error
+	CreateMany(ctx context.Context, hmes []Home) error
 	Update(ctx context.Context, hme Home) error
 	Delete(ctx context.Context, hme Home) error
-	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Home, error)
+	Query(ctx context.Context, filter QueryFilter, fields fieldset.Set, orderBy order.By, page page.Page) ([]Home, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
+	EstimateCount(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, homeID uuid.UUID) (Home, error)
+	QueryByIDs(ctx context.Context, homeIDs []uuid.UUID) ([]Home, error)
 	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Home, error)
+	CreateMember(ctx context.Context, mem Member) error
+	DeleteMember(ctx context.Context, mem Member) error
+	QueryMember(ctx context.Context, homeID uuid.UUID, userID uuid.UUID) (Member, error)
+	QueryMembers(ctx context.Context, homeID uuid.UUID) ([]Member, error)
+	CreateTransfer(ctx context.Context, trn Transfer) error
+	AcceptTransfer(ctx context.Context, trn Transfer) error
+	QueryTransferByID(ctx context.Context, transferID uuid.UUID) (Transfer, error)
 }
 
 // Business manages the set of APIs for home api access.
 type Business struct {
-	log      *logger.Logger
-	userBus  *userbus.Business
-	delegate *delegate.Delegate
-	storer   Storer
+	log       *logger.Logger
+	userBus   *userbus.Business
+	delegate  *delegate.Delegate
+	outboxBus *outboxbus.Business
+	validator AddressValidator
+	geocoder  Geocoder
+	storer    Storer
 }
 
-// NewBusiness constructs a home business API for use.
-func NewBusiness(log *logger.Logger, userBus *userbus.Business, delegate *delegate.Delegate, storer Storer) *Business {
+// NewBusiness constructs a home business API for use. The changes made to
+// homes are sent to other domains through the outbox.
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, delegate *delegate.Delegate, outboxBus *outboxbus.Business, validator AddressValidator, geocoder Geocoder, storer Storer) *Business {
 	return &Business{
-		log:      log,
-		userBus:  userBus,
-		delegate: delegate,
-		storer:   storer,
+		log:       log,
+		userBus:   userBus,
+		delegate:  delegate,
+		outboxBus: outboxBus,
+		validator: validator,
+		geocoder:  geocoder,
+		storer:    storer,
 	}
 }
 
 // NewWithTx constructs a new domain value that will use the
-// specified transaction in any store related calls.
+// specified transaction in any store related calls. The events for the
+// changes made are written in the same transaction.
 func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 	storer, err := b.storer.NewWithTx(tx)
 	if err != nil {
@@ -66,11 +108,22 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 		return nil, err
 	}
 
+	outboxBus := b.outboxBus
+	if outboxBus != nil {
+		outboxBus, err = b.outboxBus.NewWithTx(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	bus := Business{
-		log:      b.log,
-		userBus:  userBus,
-		delegate: b.delegate,
-		storer:   storer,
+		log:       b.log,
+		userBus:   userBus,
+		delegate:  b.delegate,
+		outboxBus: outboxBus,
+		validator: b.validator,
+		geocoder:  b.geocoder,
+		storer:    storer,
 	}
 
 	return &bus, nil
@@ -87,9 +140,91 @@ func (b *Business) Create(ctx context.Context, nh NewHome) (Home, error) {
 		return Home{}, ErrUserDisabled
 	}
 
+	if err := b.validator.ValidateAddress(nh.Address); err != nil {
+		return Home{}, fmt.Errorf("validateaddress: %w", err)
+	}
+
+	if err := validateAttributes(nh.Type, nh.Attributes); err != nil {
+		return Home{}, fmt.Errorf("validateattributes: %w", err)
+	}
+
+	hme := b.newHome(ctx, nh, time.Now())
+
+	if err := b.storer.Create(ctx, hme); err != nil {
+		return Home{}, fmt.Errorf("create: %w", err)
+	}
+
+	if err := b.outboxBus.Add(ctx, ActionChangedData(ActionCreated, hme)); err != nil {
+		return Home{}, fmt.Errorf("outbox: %w", err)
+	}
+
+	return hme, nil
+}
+
+// CreateMany adds the homes to the system with a single insert. Every home
+// is checked before any is added, and when some fail the checks a
+// bulk.Errors is returned with the error of each of them.
+func (b *Business) CreateMany(ctx context.Context, nhs []NewHome) ([]Home, error) {
+	usrs := make(map[uuid.UUID]userbus.User)
+	var itemErrs bulk.Errors
+
+	for i, nh := range nhs {
+		usr, exists := usrs[nh.UserID]
+		if !exists {
+			var err error
+			usr, err = b.userBus.QueryByID(ctx, nh.UserID)
+			if err != nil {
+				if !errors.Is(err, userbus.ErrNotFound) {
+					return nil, fmt.Errorf("user.querybyid: %s: %w", nh.UserID, err)
+				}
+				itemErrs.Add(i, err)
+				continue
+			}
+			usrs[nh.UserID] = usr
+		}
+
+		if !usr.Enabled {
+			itemErrs.Add(i, ErrUserDisabled)
+			continue
+		}
+
+		if err := b.validator.ValidateAddress(nh.Address); err != nil {
+			itemErrs.Add(i, fmt.Errorf("validateaddress: %w", err))
+			continue
+		}
+
+		if err := validateAttributes(nh.Type, nh.Attributes); err != nil {
+			itemErrs.Add(i, fmt.Errorf("validateattributes: %w", err))
+		}
+	}
+
+	if len(itemErrs) > 0 {
+		return nil, itemErrs
+	}
+
 	now := time.Now()
 
-	hme := Home{
+	hmes := make([]Home, len(nhs))
+	for i, nh := range nhs {
+		hmes[i] = b.newHome(ctx, nh, now)
+	}
+
+	if err := b.storer.CreateMany(ctx, hmes); err != nil {
+		return nil, fmt.Errorf("createmany: %w", err)
+	}
+
+	for _, hme := range hmes {
+		if err := b.outboxBus.Add(ctx, ActionChangedData(ActionCreated, hme)); err != nil {
+			return nil, fmt.Errorf("outbox: %w", err)
+		}
+	}
+
+	return hmes, nil
+}
+
+// newHome constructs the home described by nh, placing it on the map.
+func (b *Business) newHome(ctx context.Context, nh NewHome, now time.Time) Home {
+	return Home{
 		ID:   uuid.New(),
 		Type: nh.Type,
 		Address: Address{
@@ -100,16 +235,12 @@ func (b *Business) Create(ctx context.Context, nh NewHome) (Home, error) {
 			State:    nh.Address.State,
 			Country:  nh.Address.Country,
 		},
+		Attributes:  nh.Attributes,
+		Location:    b.geocode(ctx, nh.Address),
 		UserID:      nh.UserID,
 		DateCreated: now,
 		DateUpdated: now,
 	}
-
-	if err := b.storer.Create(ctx, hme); err != nil {
-		return Home{}, fmt.Errorf("create: %w", err)
-	}
-
-	return hme, nil
 }
 
 // Update modifies information about a home.
@@ -144,12 +275,53 @@ func (b *Business) Update(ctx context.Context, hme Home, uh UpdateHome) (Home, e
 		}
 	}
 
+	if uh.Attributes != nil {
+		if uh.Attributes.Bedrooms != nil {
+			hme.Attributes.Bedrooms = *uh.Attributes.Bedrooms
+		}
+
+		if uh.Attributes.Bathrooms != nil {
+			hme.Attributes.Bathrooms = *uh.Attributes.Bathrooms
+		}
+
+		if uh.Attributes.SquareFeet != nil {
+			hme.Attributes.SquareFeet = *uh.Attributes.SquareFeet
+		}
+
+		if uh.Attributes.YearBuilt != nil {
+			hme.Attributes.YearBuilt = *uh.Attributes.YearBuilt
+		}
+
+		if uh.Attributes.Units != nil {
+			hme.Attributes.Units = *uh.Attributes.Units
+		}
+	}
+
+	// A new type can make the existing attributes invalid.
+	if uh.Type != nil || uh.Attributes != nil {
+		if err := validateAttributes(hme.Type, hme.Attributes); err != nil {
+			return Home{}, fmt.Errorf("validateattributes: %w", err)
+		}
+	}
+
+	if uh.Address != nil {
+		if err := b.validator.ValidateAddress(hme.Address); err != nil {
+			return Home{}, fmt.Errorf("validateaddress: %w", err)
+		}
+
+		hme.Location = b.geocode(ctx, hme.Address)
+	}
+
 	hme.DateUpdated = time.Now()
 
 	if err := b.storer.Update(ctx, hme); err != nil {
 		return Home{}, fmt.Errorf("update: %w", err)
 	}
 
+	if err := b.outboxBus.Add(ctx, ActionChangedData(ActionUpdated, hme)); err != nil {
+		return Home{}, fmt.Errorf("outbox: %w", err)
+	}
+
 	return hme, nil
 }
 
@@ -159,12 +331,24 @@ func (b *Business) Delete(ctx context.Context, hme Home) error {
 		return fmt.Errorf("delete: %w", err)
 	}
 
+	if err := b.outboxBus.Add(ctx, ActionChangedData(ActionDeleted, hme)); err != nil {
+		return fmt.Errorf("outbox: %w", err)
+	}
+
 	return nil
 }
 
-// Query retrieves a list of existing homes.
-func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Home, error) {
-	hmes, err := b.storer.Query(ctx, filter, orderBy, page)
+// Query retrieves a list of existing homes with the fields in the set. The
+// id and the fields the homes are ordered by are always returned so the
+// results can be paged.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, fields fieldset.Set, orderBy order.By, page page.Page) ([]Home, error) {
+	if orderBy.Contains(OrderByDistance) && filter.Near == nil {
+		return nil, ErrDistanceWithoutNear
+	}
+
+	fields = withOrderFields(fields.With(FieldID), orderBy)
+
+	hmes, err := b.storer.Query(ctx, filter, fields, orderBy, page)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -177,6 +361,12 @@ func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return b.storer.Count(ctx, filter)
 }
 
+// EstimateCount returns an estimate of the total number of homes, which is
+// far cheaper than counting them.
+func (b *Business) EstimateCount(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.EstimateCount(ctx, filter)
+}
+
 // QueryByID finds the home by the specified Ib.
 func (b *Business) QueryByID(ctx context.Context, homeID uuid.UUID) (Home, error) {
 	hme, err := b.storer.QueryByID(ctx, homeID)
@@ -187,6 +377,17 @@ func (b *Business) QueryByID(ctx context.Context, homeID uuid.UUID) (Home, error
 	return hme, nil
 }
 
+// QueryByIDs finds the homes with the specified IDs. IDs that don't
+// match a home are left out of the result.
+func (b *Business) QueryByIDs(ctx context.Context, homeIDs []uuid.UUID) ([]Home, error) {
+	hmes, err := b.storer.QueryByIDs(ctx, homeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query: homeIDs[%s]: %w", homeIDs, err)
+	}
+
+	return hmes, nil
+}
+
 // QueryByUserID finds the homes by a specified User Ib.
 func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Home, error) {
 	hmes, err := b.storer.QueryByUserID(ctx, userID)
@@ -196,3 +397,178 @@ func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Home,
 
 	return hmes, nil
 }
+
+// AddMember shares the home with another user. The owner role can't be
+// granted this way since a home has a single owner.
+func (b *Business) AddMember(ctx context.Context, hme Home, nm NewMember) (Member, error) {
+	if nm.Role.Equal(Roles.Owner) || nm.UserID == hme.UserID {
+		return Member{}, ErrOwnerMember
+	}
+
+	usr, err := b.userBus.QueryByID(ctx, nm.UserID)
+	if err != nil {
+		return Member{}, fmt.Errorf("user.querybyid: %s: %w", nm.UserID, err)
+	}
+
+	if !usr.Enabled {
+		return Member{}, ErrUserDisabled
+	}
+
+	mem := Member{
+		HomeID:      hme.ID,
+		UserID:      nm.UserID,
+		Role:        nm.Role,
+		DateCreated: time.Now(),
+	}
+
+	if err := b.storer.CreateMember(ctx, mem); err != nil {
+		return Member{}, fmt.Errorf("createmember: %w", err)
+	}
+
+	return mem, nil
+}
+
+// RemoveMember stops sharing the home with the specified user.
+func (b *Business) RemoveMember(ctx context.Context, hme Home, userID uuid.UUID) error {
+	if userID == hme.UserID {
+		return ErrOwnerMember
+	}
+
+	mem, err := b.storer.QueryMember(ctx, hme.ID, userID)
+	if err != nil {
+		return fmt.Errorf("querymember: homeID[%s] userID[%s]: %w", hme.ID, userID, err)
+	}
+
+	if err := b.storer.DeleteMember(ctx, mem); err != nil {
+		return fmt.Errorf("deletemember: %w", err)
+	}
+
+	return nil
+}
+
+// QueryMembers returns the owner of the home followed by the users the home
+// is shared with.
+func (b *Business) QueryMembers(ctx context.Context, hme Home) ([]Member, error) {
+	mems, err := b.storer.QueryMembers(ctx, hme.ID)
+	if err != nil {
+		return nil, fmt.Errorf("querymembers: homeID[%s]: %w", hme.ID, err)
+	}
+
+	owner := Member{
+		HomeID:      hme.ID,
+		UserID:      hme.UserID,
+		Role:        Roles.Owner,
+		DateCreated: hme.DateCreated,
+	}
+
+	return append([]Member{owner}, mems...), nil
+}
+
+// QueryRole returns the role the specified user has on the home.
+func (b *Business) QueryRole(ctx context.Context, hme Home, userID uuid.UUID) (Role, error) {
+	if userID == hme.UserID {
+		return Roles.Owner, nil
+	}
+
+	mem, err := b.storer.QueryMember(ctx, hme.ID, userID)
+	if err != nil {
+		return Role{}, fmt.Errorf("querymember: homeID[%s] userID[%s]: %w", hme.ID, userID, err)
+	}
+
+	return mem.Role, nil
+}
+
+// RequestTransfer nominates a user to become the new owner of the home. The
+// recipient has until the transfer expires to accept it. Requesting a new
+// transfer replaces any transfer still pending for the home.
+func (b *Business) RequestTransfer(ctx context.Context, hme Home, toUserID uuid.UUID) (Transfer, error) {
+	if toUserID == hme.UserID {
+		return Transfer{}, ErrTransferToOwner
+	}
+
+	usr, err := b.userBus.QueryByID(ctx, toUserID)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("user.querybyid: %s: %w", toUserID, err)
+	}
+
+	if !usr.Enabled {
+		return Transfer{}, ErrUserDisabled
+	}
+
+	now := time.Now()
+
+	trn := Transfer{
+		ID:          uuid.New(),
+		HomeID:      hme.ID,
+		FromUserID:  hme.UserID,
+		ToUserID:    toUserID,
+		DateCreated: now,
+		DateExpires: now.Add(TransferExpiry),
+	}
+
+	if err := b.storer.CreateTransfer(ctx, trn); err != nil {
+		return Transfer{}, fmt.Errorf("createtransfer: %w", err)
+	}
+
+	return trn, nil
+}
+
+// AcceptTransfer makes the recipient of the transfer the owner of the home.
+// The change of owner only happens if the home still belongs to the user who
+// requested the transfer.
+func (b *Business) AcceptTransfer(ctx context.Context, trn Transfer, userID uuid.UUID) (Home, error) {
+	if trn.ToUserID != userID {
+		return Home{}, ErrTransferRecipient
+	}
+
+	if !trn.DateAccepted.IsZero() {
+		return Home{}, ErrTransferNotPending
+	}
+
+	now := time.Now()
+
+	if now.After(trn.DateExpires) {
+		return Home{}, ErrTransferExpired
+	}
+
+	trn.DateAccepted = now
+
+	if err := b.storer.AcceptTransfer(ctx, trn); err != nil {
+		return Home{}, fmt.Errorf("accepttransfer: transferID[%s]: %w", trn.ID, err)
+	}
+
+	if err := b.outboxBus.Add(ctx, ActionTransferredData(trn)); err != nil {
+		return Home{}, fmt.Errorf("outbox: transferID[%s]: %w", trn.ID, err)
+	}
+
+	hme, err := b.storer.QueryByID(ctx, trn.HomeID)
+	if err != nil {
+		return Home{}, fmt.Errorf("query: homeID[%s]: %w", trn.HomeID, err)
+	}
+
+	return hme, nil
+}
+
+// QueryTransferByID finds the transfer by the specified ID.
+func (b *Business) QueryTransferByID(ctx context.Context, transferID uuid.UUID) (Transfer, error) {
+	trn, err := b.storer.QueryTransferByID(ctx, transferID)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("query: transferID[%s]: %w", transferID, err)
+	}
+
+	return trn, nil
+}
+
+// geocode finds the location of the address. A home is still saved when its
+// address can't be placed, it just won't be found by a proximity search.
+func (b *Business) geocode(ctx context.Context, addr Address) Location {
+	loc, err := b.geocoder.Geocode(ctx, addr)
+	if err != nil {
+		if !errors.Is(err, ErrLocationNotFound) {
+			b.log.Error(ctx, "geocode", "msg", err)
+		}
+		return Location{}
+	}
+
+	return loc
+}
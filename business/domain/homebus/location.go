@@ -0,0 +1,63 @@
+package homebus
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// ErrLocationNotFound is returned by a Geocoder that can't place an address.
+var ErrLocationNotFound = errors.New("location not found")
+
+// Geocoder declares the behavior needed to find where an address is.
+type Geocoder interface {
+	Geocode(ctx context.Context, addr Address) (Location, error)
+}
+
+// Location represents a point on the earth in decimal degrees. A zero
+// Location means the address of the home could not be geocoded.
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// IsZero reports whether the location is unknown.
+func (l Location) IsZero() bool {
+	return l == Location{}
+}
+
+// Area represents the circle around a location used to find nearby homes.
+type Area struct {
+	Center   Location
+	RadiusKM float64
+}
+
+// EarthRadiusKM is the mean radius of the earth used for distances.
+const EarthRadiusKM = 6371.0
+
+// kmPerDegree is the length of a degree of latitude.
+const kmPerDegree = math.Pi * EarthRadiusKM / 180
+
+// BoundingBox returns the smallest latitude and longitude ranges that hold the
+// area. It's used to cheaply discard homes before computing exact distances.
+// The longitude range is left open near the poles and when the area crosses
+// the antimeridian.
+func (a Area) BoundingBox() (minLat float64, maxLat float64, minLng float64, maxLng float64) {
+	dLat := a.RadiusKM / kmPerDegree
+
+	minLat = math.Max(a.Center.Latitude-dLat, -90)
+	maxLat = math.Min(a.Center.Latitude+dLat, 90)
+	minLng, maxLng = -180, 180
+
+	cos := math.Cos(a.Center.Latitude * math.Pi / 180)
+	if cos < 1e-6 {
+		return minLat, maxLat, minLng, maxLng
+	}
+
+	dLng := a.RadiusKM / (kmPerDegree * cos)
+	if a.Center.Longitude-dLng < -180 || a.Center.Longitude+dLng > 180 {
+		return minLat, maxLat, minLng, maxLng
+	}
+
+	return minLat, maxLat, a.Center.Longitude - dLng, a.Center.Longitude + dLng
+}
@@ -16,21 +16,26 @@ type Address struct {
 	Country  string
 }
 
-// Home represents an individual home.
+// Home represents an individual home. Distance is only set by queries with a
+// Near filter and is the number of kilometers from the center of the area.
 type Home struct {
 	ID          uuid.UUID
 	UserID      uuid.UUID
 	Type        Type
 	Address     Address
+	Attributes  Attributes
+	Location    Location
+	Distance    float64
 	DateCreated time.Time
 	DateUpdated time.Time
 }
 
 // NewHome is what we require from clients when adding a Home.
 type NewHome struct {
-	UserID  uuid.UUID
-	Type    Type
-	Address Address
+	UserID     uuid.UUID
+	Type       Type
+	Address    Address
+	Attributes Attributes
 }
 
 // UpdateAddress is what fields can be updated in the store.
@@ -50,6 +55,34 @@ type UpdateAddress struct {
 // we do not want to use pointers to basic types but we make exepction around
 // marshalling/unmarshalling.
 type UpdateHome struct {
-	Type    *Type
-	Address *UpdateAddress
+	Type       *Type
+	Address    *UpdateAddress
+	Attributes *UpdateAttributes
+}
+
+// Member represents a user the home is shared with. The owner of the home is
+// reported as a member with the owner role.
+type Member struct {
+	HomeID      uuid.UUID
+	UserID      uuid.UUID
+	Role        Role
+	DateCreated time.Time
+}
+
+// NewMember is what we require to share a home with another user.
+type NewMember struct {
+	UserID uuid.UUID
+	Role   Role
+}
+
+// Transfer represents a request by the owner of a home to hand it over to
+// another user. A zero DateAccepted means the transfer is still pending.
+type Transfer struct {
+	ID           uuid.UUID
+	HomeID       uuid.UUID
+	FromUserID   uuid.UUID
+	ToUserID     uuid.UUID
+	DateCreated  time.Time
+	DateExpires  time.Time
+	DateAccepted time.Time
 }
@@ -10,4 +10,14 @@ const (
 	OrderByID     = "home_id"
 	OrderByType   = "type"
 	OrderByUserID = "user_id"
+
+	OrderByBedrooms   = "bedrooms"
+	OrderByBathrooms  = "bathrooms"
+	OrderBySquareFeet = "square_feet"
+	OrderByYearBuilt  = "year_built"
+	OrderByUnits      = "units"
+
+	// OrderByDistance orders homes by their distance from the center of the
+	// Near filter and can only be used with it.
+	OrderByDistance = "distance"
 )
@@ -0,0 +1,65 @@
+package homebus
+
+import "fmt"
+
+type roleSet struct {
+	Owner  Role
+	Editor Role
+	Viewer Role
+}
+
+// Roles represents the set of roles a member of a home can have.
+var Roles = roleSet{
+	Owner:  newRole("OWNER"),
+	Editor: newRole("EDITOR"),
+	Viewer: newRole("VIEWER"),
+}
+
+// =============================================================================
+
+// Set of known member roles.
+var roles = make(map[string]Role)
+
+// Role represents what a member is allowed to do with a home.
+type Role struct {
+	name string
+}
+
+func newRole(role string) Role {
+	r := Role{role}
+	roles[role] = r
+	return r
+}
+
+// String returns the name of the role.
+func (r Role) String() string {
+	return r.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (r Role) Equal(r2 Role) bool {
+	return r.name == r2.name
+}
+
+// =============================================================================
+
+// ParseRole parses the string value and returns a role if one exists.
+func ParseRole(value string) (Role, error) {
+	role, exists := roles[value]
+	if !exists {
+		return Role{}, fmt.Errorf("invalid role %q", value)
+	}
+
+	return role, nil
+}
+
+// MustParseRole parses the string value and returns a role if one exists. If
+// an error occurs the function panics.
+func MustParseRole(value string) Role {
+	role, err := ParseRole(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return role
+}
@@ -1,42 +1,67 @@
 package homedb
 
 import (
-	"bytes"
-	"strings"
+	"fmt"
 
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
-func (s *Store) applyFilter(filter homebus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
-	var wc []string
+// distanceKM is the haversine distance between a home and the center of the
+// near filter. LEAST protects ASIN from rounding errors for antipodal points.
+var distanceKM = fmt.Sprintf(`(%g * 2 * ASIN(LEAST(1, SQRT(
+	POWER(SIN(RADIANS(latitude - :near_lat) / 2), 2) +
+	COS(RADIANS(:near_lat)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - :near_lng) / 2), 2)))))`, homebus.EarthRadiusKM)
 
-	if filter.ID != nil {
-		data["home_id"] = *filter.ID
-		wc = append(wc, "home_id = :home_id")
+// applyDistance adds the distance column to the select list. Homes are only
+// at a distance from something when the near filter is used.
+func (s *Store) applyDistance(filter homebus.QueryFilter, qb *sqldb.Builder) {
+	if filter.Near == nil {
+		qb.Column("0 AS distance")
+		return
 	}
 
+	qb.Column(distanceKM + " AS distance")
+}
+
+func (s *Store) applyFilter(filter homebus.QueryFilter, qb *sqldb.Builder) {
+	sqldb.Conditions(qb, "home_id", filter.ID)
+
 	if filter.UserID != nil {
-		data["user_id"] = *filter.UserID
-		wc = append(wc, "user_id = :user_id")
+		qb.Equal("user_id", *filter.UserID)
 	}
 
 	if filter.Type != nil {
-		data["type"] = filter.Type.String()
-		wc = append(wc, "type = :type")
+		qb.Equal("type", filter.Type.String())
 	}
 
 	if filter.StartCreatedDate != nil {
-		data["start_date_created"] = filter.StartCreatedDate.UTC()
-		wc = append(wc, "date_created >= :start_date_created")
+		qb.AtLeast("date_created", filter.StartCreatedDate.UTC())
 	}
 
 	if filter.EndCreatedDate != nil {
-		data["end_date_created"] = filter.EndCreatedDate.UTC()
-		wc = append(wc, "date_created <= :end_date_created")
+		qb.AtMost("date_created", filter.EndCreatedDate.UTC())
 	}
 
-	if len(wc) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(wc, " AND "))
+	if filter.Near != nil {
+		minLat, maxLat, minLng, maxLng := filter.Near.BoundingBox()
+
+		qb.Bind("near_lat", filter.Near.Center.Latitude)
+		qb.Bind("near_lng", filter.Near.Center.Longitude)
+		qb.Bind("radius_km", filter.Near.RadiusKM)
+		qb.Bind("min_lat", minLat)
+		qb.Bind("max_lat", maxLat)
+		qb.Bind("min_lng", minLng)
+		qb.Bind("max_lng", maxLng)
+
+		qb.Where("latitude BETWEEN :min_lat AND :max_lat")
+		qb.Where("longitude BETWEEN :min_lng AND :max_lng")
+		qb.Where(distanceKM + " <= :radius_km")
 	}
+
+	sqldb.Conditions(qb, "bedrooms", filter.Bedrooms)
+	sqldb.Conditions(qb, "bathrooms", filter.Bathrooms)
+	sqldb.Conditions(qb, "square_feet", filter.SquareFeet)
+	sqldb.Conditions(qb, "year_built", filter.YearBuilt)
+	sqldb.Conditions(qb, "units", filter.Units)
 }
@@ -2,12 +2,13 @@
 package homedb
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
@@ -50,9 +51,9 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (homebus.Storer, error) {
 func (s *Store) Create(ctx context.Context, hme homebus.Home) error {
 	const q = `
     INSERT INTO homes
-        (home_id, user_id, type, address_1, address_2, zip_code, city, state, country, date_created, date_updated)
+        (home_id, user_id, type, address_1, address_2, zip_code, city, state, country, bedrooms, bathrooms, square_feet, year_built, units, latitude, longitude, date_created, date_updated)
     VALUES
-        (:home_id, :user_id, :type, :address_1, :address_2, :zip_code, :city, :state, :country, :date_created, :date_updated)`
+        (:home_id, :user_id, :type, :address_1, :address_2, :zip_code, :city, :state, :country, :bedrooms, :bathrooms, :square_feet, :year_built, :units, :latitude, :longitude, :date_created, :date_updated)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBHome(hme)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -82,6 +83,31 @@ func (s *Store) Delete(ctx context.Context, hme homebus.Home) error {
 	return nil
 }
 
+// CreateMany inserts the homes into the database with a single multi-row
+// insert.
+func (s *Store) CreateMany(ctx context.Context, hmes []homebus.Home) error {
+	if len(hmes) == 0 {
+		return nil
+	}
+
+	const q = `
+    INSERT INTO homes
+        (home_id, user_id, type, address_1, address_2, zip_code, city, state, country, bedrooms, bathrooms, square_feet, year_built, units, latitude, longitude, date_created, date_updated)
+    VALUES
+        (:home_id, :user_id, :type, :address_1, :address_2, :zip_code, :city, :state, :country, :bedrooms, :bathrooms, :square_feet, :year_built, :units, :latitude, :longitude, :date_created, :date_updated)`
+
+	dbHmes := make([]home, len(hmes))
+	for i, hme := range hmes {
+		dbHmes[i] = toDBHome(hme)
+	}
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, dbHmes); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
 // Update replaces a home document in the database.
 func (s *Store) Update(ctx context.Context, hme homebus.Home) error {
 	const q = `
@@ -94,6 +120,13 @@ func (s *Store) Update(ctx context.Context, hme homebus.Home) error {
         "city"          = :city,
         "state"         = :state,
         "country"       = :country,
+        "bedrooms"      = :bedrooms,
+        "bathrooms"     = :bathrooms,
+        "square_feet"   = :square_feet,
+        "year_built"    = :year_built,
+        "units"         = :units,
+        "latitude"      = :latitude,
+        "longitude"     = :longitude,
         "type"          = :type,
         "date_updated"  = :date_updated
     WHERE
@@ -107,34 +140,24 @@ func (s *Store) Update(ctx context.Context, hme homebus.Home) error {
 }
 
 // Query retrieves a list of existing homes from the database.
-func (s *Store) Query(ctx context.Context, filter homebus.QueryFilter, orderBy order.By, page page.Page) ([]homebus.Home, error) {
-	data := map[string]any{
-		"offset":        (page.Number() - 1) * page.RowsPerPage(),
-		"rows_per_page": page.RowsPerPage(),
-	}
-
-	const q = `
-    SELECT
-	    home_id, user_id, type, address_1, address_2, zip_code, city, state, country, date_created, date_updated
-	FROM
-	  	homes`
-
-	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+func (s *Store) Query(ctx context.Context, filter homebus.QueryFilter, fields fieldset.Set, orderBy order.By, page page.Page) ([]homebus.Home, error) {
+	qb := sqldb.NewBuilder("homes", projection.Columns(fields))
+	s.applyDistance(filter, qb)
+	s.applyFilter(filter, qb)
 
-	orderByClause, err := orderByClause(orderBy)
-	if err != nil {
+	if err := qb.Page(orderByFields, orderBy, "home_id", page); err != nil {
 		return nil, err
 	}
 
-	buf.WriteString(orderByClause)
-	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
-
 	var dbHmes []home
-	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbHmes); err != nil {
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, qb.String(), qb.Data(), &dbHmes); err != nil {
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
+	if page.IsBackward() {
+		slices.Reverse(dbHmes)
+	}
+
 	hmes, err := toBusHomes(dbHmes)
 	if err != nil {
 		return nil, err
@@ -145,27 +168,33 @@ func (s *Store) Query(ctx context.Context, filter homebus.QueryFilter, orderBy o
 
 // Count returns the total number of homes in the DB.
 func (s *Store) Count(ctx context.Context, filter homebus.QueryFilter) (int, error) {
-	data := map[string]any{}
-
-	const q = `
-    SELECT
-        count(1)
-    FROM
-        homes`
-
-	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	qb := sqldb.NewBuilder("homes", "count(1)")
+	s.applyFilter(filter, qb)
 
 	var count struct {
 		Count int `db:"count"`
 	}
-	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, qb.String(), qb.Data(), &count); err != nil {
 		return 0, fmt.Errorf("db: %w", err)
 	}
 
 	return count.Count, nil
 }
 
+// EstimateCount returns the planner's estimate of the number of homes in
+// the DB.
+func (s *Store) EstimateCount(ctx context.Context, filter homebus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("homes", "1")
+	s.applyFilter(filter, qb)
+
+	count, err := sqldb.EstimateCount(ctx, s.log, s.db, qb.String(), qb.Data())
+	if err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count, nil
+}
+
 // QueryByID gets the specified home from the database.
 func (s *Store) QueryByID(ctx context.Context, homeID uuid.UUID) (homebus.Home, error) {
 	data := struct {
@@ -176,7 +205,7 @@ func (s *Store) QueryByID(ctx context.Context, homeID uuid.UUID) (homebus.Home,
 
 	const q = `
     SELECT
-	  	home_id, user_id, type, address_1, address_2, zip_code, city, state, country, date_created, date_updated
+	  	home_id, user_id, type, address_1, address_2, zip_code, city, state, country, bedrooms, bathrooms, square_feet, year_built, units, latitude, longitude, date_created, date_updated
     FROM
         homes
     WHERE
@@ -193,6 +222,39 @@ func (s *Store) QueryByID(ctx context.Context, homeID uuid.UUID) (homebus.Home,
 	return toBusHome(dbHme)
 }
 
+// QueryByIDs gets the specified homes from the database.
+func (s *Store) QueryByIDs(ctx context.Context, homeIDs []uuid.UUID) ([]homebus.Home, error) {
+	if len(homeIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(homeIDs))
+	for i, id := range homeIDs {
+		ids[i] = id.String()
+	}
+
+	data := struct {
+		IDs []string `db:"home_ids"`
+	}{
+		IDs: ids,
+	}
+
+	const q = `
+	SELECT
+	    home_id, user_id, type, address_1, address_2, zip_code, city, state, country, bedrooms, bathrooms, square_feet, year_built, units, latitude, longitude, date_created, date_updated
+	FROM
+		homes
+	WHERE
+		home_id IN (:home_ids)`
+
+	var dbs []home
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, q, data, &dbs); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusHomes(dbs)
+}
+
 // QueryByUserID gets the specified home from the database by user id.
 func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]homebus.Home, error) {
 	data := struct {
@@ -203,7 +265,7 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]homebus.
 
 	const q = `
 	SELECT
-	    home_id, user_id, type, address_1, address_2, zip_code, city, state, country, date_created, date_updated
+	    home_id, user_id, type, address_1, address_2, zip_code, city, state, country, bedrooms, bathrooms, square_feet, year_built, units, latitude, longitude, date_created, date_updated
 	FROM
 		homes
 	WHERE
@@ -216,3 +278,202 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]homebus.
 
 	return toBusHomes(dbHmes)
 }
+
+// CreateMember shares a home with a user.
+func (s *Store) CreateMember(ctx context.Context, mem homebus.Member) error {
+	const q = `
+	INSERT INTO home_members
+		(home_id, user_id, role, date_created)
+	VALUES
+		(:home_id, :user_id, :role, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBMember(mem)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", homebus.ErrMemberExists)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMember stops sharing a home with a user.
+func (s *Store) DeleteMember(ctx context.Context, mem homebus.Member) error {
+	const q = `
+	DELETE FROM
+		home_members
+	WHERE
+		home_id = :home_id AND
+		user_id = :user_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBMember(mem)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryMember gets the membership of a user on a home.
+func (s *Store) QueryMember(ctx context.Context, homeID uuid.UUID, userID uuid.UUID) (homebus.Member, error) {
+	data := struct {
+		HomeID string `db:"home_id"`
+		UserID string `db:"user_id"`
+	}{
+		HomeID: homeID.String(),
+		UserID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		home_id, user_id, role, date_created
+	FROM
+		home_members
+	WHERE
+		home_id = :home_id AND
+		user_id = :user_id`
+
+	var dbMem member
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbMem); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return homebus.Member{}, fmt.Errorf("db: %w", homebus.ErrMemberNotFound)
+		}
+		return homebus.Member{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusMember(dbMem)
+}
+
+// QueryMembers gets the users a home is shared with in the order they were
+// added.
+func (s *Store) QueryMembers(ctx context.Context, homeID uuid.UUID) ([]homebus.Member, error) {
+	data := struct {
+		ID string `db:"home_id"`
+	}{
+		ID: homeID.String(),
+	}
+
+	const q = `
+	SELECT
+		home_id, user_id, role, date_created
+	FROM
+		home_members
+	WHERE
+		home_id = :home_id
+	ORDER BY
+		date_created, user_id`
+
+	var dbMems []member
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbMems); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusMembers(dbMems)
+}
+
+// CreateTransfer records a transfer for a home, replacing any transfer that
+// is still pending for it.
+func (s *Store) CreateTransfer(ctx context.Context, trn homebus.Transfer) error {
+	const q = `
+	INSERT INTO home_transfers
+		(transfer_id, home_id, from_user_id, to_user_id, date_created, date_expires, date_accepted)
+	VALUES
+		(:transfer_id, :home_id, :from_user_id, :to_user_id, :date_created, :date_expires, :date_accepted)
+	ON CONFLICT (home_id) WHERE date_accepted IS NULL DO UPDATE SET
+		transfer_id  = EXCLUDED.transfer_id,
+		from_user_id = EXCLUDED.from_user_id,
+		to_user_id   = EXCLUDED.to_user_id,
+		date_created = EXCLUDED.date_created,
+		date_expires = EXCLUDED.date_expires`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTransfer(trn)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// AcceptTransfer marks the transfer as accepted and moves the home to the
+// recipient in a single statement. Nothing changes if the transfer was
+// already accepted or the home changed owner since it was requested.
+func (s *Store) AcceptTransfer(ctx context.Context, trn homebus.Transfer) error {
+	const q = `
+	WITH accepted AS (
+		UPDATE
+			home_transfers AS t
+		SET
+			"date_accepted" = :date_accepted
+		FROM
+			homes AS h
+		WHERE
+			t.transfer_id = :transfer_id AND
+			t.date_accepted IS NULL AND
+			h.home_id = t.home_id AND
+			h.user_id = t.from_user_id
+		RETURNING
+			t.home_id, t.to_user_id
+	), moved AS (
+		UPDATE
+			homes AS h
+		SET
+			"user_id" = a.to_user_id,
+			"date_updated" = :date_accepted
+		FROM
+			accepted AS a
+		WHERE
+			h.home_id = a.home_id
+		RETURNING
+			h.home_id, h.user_id
+	), removed AS (
+		DELETE FROM
+			home_members AS m
+		USING
+			moved AS mv
+		WHERE
+			m.home_id = mv.home_id AND
+			m.user_id = mv.user_id
+	)
+	SELECT
+		count(1)
+	FROM
+		moved`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBTransfer(trn), &count); err != nil {
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	if count.Count == 0 {
+		return fmt.Errorf("db: %w", homebus.ErrTransferNotPending)
+	}
+
+	return nil
+}
+
+// QueryTransferByID gets the specified transfer from the database.
+func (s *Store) QueryTransferByID(ctx context.Context, transferID uuid.UUID) (homebus.Transfer, error) {
+	data := struct {
+		ID string `db:"transfer_id"`
+	}{
+		ID: transferID.String(),
+	}
+
+	const q = `
+	SELECT
+		transfer_id, home_id, from_user_id, to_user_id, date_created, date_expires, date_accepted
+	FROM
+		home_transfers
+	WHERE
+		transfer_id = :transfer_id`
+
+	var dbTrn transfer
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbTrn); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return homebus.Transfer{}, fmt.Errorf("db: %w", homebus.ErrTransferNotFound)
+		}
+		return homebus.Transfer{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusTransfer(dbTrn), nil
+}
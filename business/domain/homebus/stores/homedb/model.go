@@ -1,38 +1,73 @@
 package homedb
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/google/uuid"
 )
 
 type home struct {
-	ID          uuid.UUID `db:"home_id"`
-	UserID      uuid.UUID `db:"user_id"`
-	Type        string    `db:"type"`
-	Address1    string    `db:"address_1"`
-	Address2    string    `db:"address_2"`
-	ZipCode     string    `db:"zip_code"`
-	City        string    `db:"city"`
-	Country     string    `db:"country"`
-	State       string    `db:"state"`
-	DateCreated time.Time `db:"date_created"`
-	DateUpdated time.Time `db:"date_updated"`
+	ID          uuid.UUID       `db:"home_id"`
+	UserID      uuid.UUID       `db:"user_id"`
+	Type        string          `db:"type"`
+	Address1    string          `db:"address_1"`
+	Address2    string          `db:"address_2"`
+	ZipCode     string          `db:"zip_code"`
+	City        string          `db:"city"`
+	Country     string          `db:"country"`
+	State       string          `db:"state"`
+	Bedrooms    int             `db:"bedrooms"`
+	Bathrooms   float64         `db:"bathrooms"`
+	SquareFeet  int             `db:"square_feet"`
+	YearBuilt   int             `db:"year_built"`
+	Units       int             `db:"units"`
+	Latitude    sql.NullFloat64 `db:"latitude"`
+	Longitude   sql.NullFloat64 `db:"longitude"`
+	Distance    float64         `db:"distance"`
+	DateCreated time.Time       `db:"date_created"`
+	DateUpdated time.Time       `db:"date_updated"`
+}
+
+// projection maps the fields a query can be limited to onto their columns.
+var projection = sqldb.Projection{
+	{Field: homebus.FieldID, Columns: "home_id"},
+	{Field: homebus.FieldUserID, Columns: "user_id"},
+	{Field: homebus.FieldType, Columns: "type"},
+	{Field: homebus.FieldAddress, Columns: "address_1, address_2, zip_code, city, state, country"},
+	{Field: homebus.FieldAttributes, Columns: "bedrooms, bathrooms, square_feet, year_built, units"},
+	{Field: homebus.FieldLocation, Columns: "latitude, longitude"},
+	{Field: homebus.FieldDateCreated, Columns: "date_created"},
+	{Field: homebus.FieldDateUpdated, Columns: "date_updated"},
 }
 
 func toDBHome(bus homebus.Home) home {
 	db := home{
-		ID:          bus.ID,
-		UserID:      bus.UserID,
-		Type:        bus.Type.String(),
-		Address1:    bus.Address.Address1,
-		Address2:    bus.Address.Address2,
-		ZipCode:     bus.Address.ZipCode,
-		City:        bus.Address.City,
-		Country:     bus.Address.Country,
-		State:       bus.Address.State,
+		ID:         bus.ID,
+		UserID:     bus.UserID,
+		Type:       bus.Type.String(),
+		Address1:   bus.Address.Address1,
+		Address2:   bus.Address.Address2,
+		ZipCode:    bus.Address.ZipCode,
+		City:       bus.Address.City,
+		Country:    bus.Address.Country,
+		State:      bus.Address.State,
+		Bedrooms:   bus.Attributes.Bedrooms,
+		Bathrooms:  bus.Attributes.Bathrooms,
+		SquareFeet: bus.Attributes.SquareFeet,
+		YearBuilt:  bus.Attributes.YearBuilt,
+		Units:      bus.Attributes.Units,
+		Latitude: sql.NullFloat64{
+			Float64: bus.Location.Latitude,
+			Valid:   !bus.Location.IsZero(),
+		},
+		Longitude: sql.NullFloat64{
+			Float64: bus.Location.Longitude,
+			Valid:   !bus.Location.IsZero(),
+		},
 		DateCreated: bus.DateCreated.UTC(),
 		DateUpdated: bus.DateUpdated.UTC(),
 	}
@@ -41,9 +76,14 @@ func toDBHome(bus homebus.Home) home {
 }
 
 func toBusHome(db home) (homebus.Home, error) {
-	typ, err := homebus.ParseType(db.Type)
-	if err != nil {
-		return homebus.Home{}, fmt.Errorf("parse type: %w", err)
+	// The type is empty when a query wasn't asked for it.
+	var typ homebus.Type
+	if db.Type != "" {
+		var err error
+		typ, err = homebus.ParseType(db.Type)
+		if err != nil {
+			return homebus.Home{}, fmt.Errorf("parse type: %w", err)
+		}
 	}
 
 	bus := homebus.Home{
@@ -58,6 +98,18 @@ func toBusHome(db home) (homebus.Home, error) {
 			Country:  db.Country,
 			State:    db.State,
 		},
+		Attributes: homebus.Attributes{
+			Bedrooms:   db.Bedrooms,
+			Bathrooms:  db.Bathrooms,
+			SquareFeet: db.SquareFeet,
+			YearBuilt:  db.YearBuilt,
+			Units:      db.Units,
+		},
+		Location: homebus.Location{
+			Latitude:  db.Latitude.Float64,
+			Longitude: db.Longitude.Float64,
+		},
+		Distance:    db.Distance,
 		DateCreated: db.DateCreated.In(time.Local),
 		DateUpdated: db.DateUpdated.In(time.Local),
 	}
@@ -78,3 +130,97 @@ func toBusHomes(dbs []home) ([]homebus.Home, error) {
 
 	return bus, nil
 }
+
+// =============================================================================
+
+type member struct {
+	HomeID      uuid.UUID `db:"home_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Role        string    `db:"role"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBMember(bus homebus.Member) member {
+	return member{
+		HomeID:      bus.HomeID,
+		UserID:      bus.UserID,
+		Role:        bus.Role.String(),
+		DateCreated: bus.DateCreated.UTC(),
+	}
+}
+
+func toBusMember(db member) (homebus.Member, error) {
+	role, err := homebus.ParseRole(db.Role)
+	if err != nil {
+		return homebus.Member{}, fmt.Errorf("parse role: %w", err)
+	}
+
+	bus := homebus.Member{
+		HomeID:      db.HomeID,
+		UserID:      db.UserID,
+		Role:        role,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusMembers(dbs []member) ([]homebus.Member, error) {
+	bus := make([]homebus.Member, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusMember(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type transfer struct {
+	ID           uuid.UUID    `db:"transfer_id"`
+	HomeID       uuid.UUID    `db:"home_id"`
+	FromUserID   uuid.UUID    `db:"from_user_id"`
+	ToUserID     uuid.UUID    `db:"to_user_id"`
+	DateCreated  time.Time    `db:"date_created"`
+	DateExpires  time.Time    `db:"date_expires"`
+	DateAccepted sql.NullTime `db:"date_accepted"`
+}
+
+func toDBTransfer(bus homebus.Transfer) transfer {
+	db := transfer{
+		ID:          bus.ID,
+		HomeID:      bus.HomeID,
+		FromUserID:  bus.FromUserID,
+		ToUserID:    bus.ToUserID,
+		DateCreated: bus.DateCreated.UTC(),
+		DateExpires: bus.DateExpires.UTC(),
+		DateAccepted: sql.NullTime{
+			Time:  bus.DateAccepted.UTC(),
+			Valid: !bus.DateAccepted.IsZero(),
+		},
+	}
+
+	return db
+}
+
+func toBusTransfer(db transfer) homebus.Transfer {
+	bus := homebus.Transfer{
+		ID:          db.ID,
+		HomeID:      db.HomeID,
+		FromUserID:  db.FromUserID,
+		ToUserID:    db.ToUserID,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateExpires: db.DateExpires.In(time.Local),
+	}
+
+	if db.DateAccepted.Valid {
+		bus.DateAccepted = db.DateAccepted.Time.In(time.Local)
+	}
+
+	return bus
+}
@@ -1,23 +1,18 @@
 package homedb
 
 import (
-	"fmt"
-
 	"github.com/ardanlabs/encore/business/domain/homebus"
-	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
-var orderByFields = map[string]string{
-	homebus.OrderByID:     "home_id",
-	homebus.OrderByType:   "type",
-	homebus.OrderByUserID: "user_id",
-}
-
-func orderByClause(orderBy order.By) (string, error) {
-	by, exists := orderByFields[orderBy.Field]
-	if !exists {
-		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
-	}
-
-	return " ORDER BY " + by + " " + orderBy.Direction, nil
+var orderByFields = sqldb.Columns{
+	homebus.OrderByID:         "home_id",
+	homebus.OrderByType:       "type",
+	homebus.OrderByUserID:     "user_id",
+	homebus.OrderByBedrooms:   "bedrooms",
+	homebus.OrderByBathrooms:  "bathrooms",
+	homebus.OrderBySquareFeet: "square_feet",
+	homebus.OrderByYearBuilt:  "year_built",
+	homebus.OrderByUnits:      "units",
+	homebus.OrderByDistance:   "distance",
 }
@@ -3,14 +3,20 @@ package homebus
 import "fmt"
 
 type typeSet struct {
-	Single Type
-	Condo  Type
+	Single      Type
+	Condo       Type
+	Townhouse   Type
+	Apartment   Type
+	MultiFamily Type
 }
 
 // Types represents the set of types that can be used.
 var Types = typeSet{
-	Single: newType("SINGLE FAMILY"),
-	Condo:  newType("CONDO"),
+	Single:      newType("SINGLE FAMILY", attributeRule{maxBedrooms: 20, minUnits: 1, maxUnits: 1}),
+	Condo:       newType("CONDO", attributeRule{maxBedrooms: 10, minUnits: 1, maxUnits: 1}),
+	Townhouse:   newType("TOWNHOUSE", attributeRule{maxBedrooms: 10, minUnits: 1, maxUnits: 1}),
+	Apartment:   newType("APARTMENT", attributeRule{maxBedrooms: 10, minUnits: 1, maxUnits: 1}),
+	MultiFamily: newType("MULTI FAMILY", attributeRule{maxBedrooms: 200, minUnits: 2, maxUnits: 100}),
 }
 
 // =============================================================================
@@ -18,14 +24,18 @@ var Types = typeSet{
 // Set of known housing types.
 var types = make(map[string]Type)
 
+// Set of attribute rules for each housing type.
+var attributeRules = make(map[string]attributeRule)
+
 // Type represents a type in the system.
 type Type struct {
 	name string
 }
 
-func newType(typ string) Type {
+func newType(typ string, rule attributeRule) Type {
 	t := Type{typ}
 	types[typ] = t
+	attributeRules[typ] = rule
 	return t
 }
 
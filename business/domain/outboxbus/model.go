@@ -0,0 +1,38 @@
+package outboxbus
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/google/uuid"
+)
+
+// Message represents an event waiting in the outbox to be published. The
+// date sent is zero until the relay has published it.
+type Message struct {
+	ID          uuid.UUID
+	Domain      string
+	Action      string
+	RawParams   []byte
+	DateCreated time.Time
+	DateSent    time.Time
+}
+
+func toMessage(data delegate.Data, now time.Time) Message {
+	return Message{
+		ID:          uuid.New(),
+		Domain:      data.Domain,
+		Action:      data.Action,
+		RawParams:   data.RawParams,
+		DateCreated: now,
+	}
+}
+
+func toData(msg Message) delegate.Data {
+	return delegate.Data{
+		ID:        msg.ID,
+		Domain:    msg.Domain,
+		Action:    msg.Action,
+		RawParams: msg.RawParams,
+	}
+}
@@ -0,0 +1,269 @@
+package outboxbus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/domain/userbus/stores/userdb"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Outbox(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, relay(db, sd), "relay")
+	unitest.Run(t, claim(db.BusDomain), "claim")
+	unitest.Run(t, noOutbox(db, sd), "nooutbox")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, userbus.Roles.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	// The events for the seeded users aren't part of the tests.
+	if _, err := busDomain.Outbox.Relay(ctx); err != nil {
+		return unitest.SeedData{}, fmt.Errorf("relaying seed events : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func relay(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	busDomain := db.BusDomain
+
+	// The ids of the users in the update events that were relayed.
+	var userIDs []uuid.UUID
+	busDomain.Delegate.Register(userbus.DomainName, userbus.ActionUpdated, "test", func(ctx context.Context, data delegate.Data) error {
+		var params userbus.ActionUpdatedParms
+		if err := json.Unmarshal(data.RawParams, &params); err != nil {
+			return err
+		}
+
+		userIDs = append(userIDs, params.UserID)
+		return nil
+	})
+
+	// update disables the user inside a transaction that is committed or
+	// rolled back, then relays the outbox.
+	update := func(ctx context.Context, usr userbus.User, commit bool) ([]uuid.UUID, error) {
+		tx, err := sqldb.NewBeginner(db.DB).Begin()
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		userBus, err := busDomain.User.NewWithTx(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := userBus.Update(ctx, usr, userbus.UpdateUser{Enabled: dbtest.BoolPointer(false)}); err != nil {
+			return nil, err
+		}
+
+		if commit {
+			if err := tx.Commit(); err != nil {
+				return nil, err
+			}
+		}
+
+		userIDs = nil
+
+		if _, err := busDomain.Outbox.Relay(ctx); err != nil {
+			return nil, err
+		}
+
+		// Nothing is left to send the second time.
+		n, err := busDomain.Outbox.Relay(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if n != 0 {
+			return nil, fmt.Errorf("sent %d events again", n)
+		}
+
+		return userIDs, nil
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "commit",
+			ExpResp: []uuid.UUID{sd.Users[0].ID},
+			ExcFunc: func(ctx context.Context) any {
+				userIDs, err := update(ctx, sd.Users[0].User, true)
+				if err != nil {
+					return err
+				}
+
+				return userIDs
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "rollback",
+			ExpResp: []uuid.UUID(nil),
+			ExcFunc: func(ctx context.Context) any {
+				userIDs, err := update(ctx, sd.Users[1].User, false)
+				if err != nil {
+					return err
+				}
+
+				return userIDs
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "tracked",
+			ExpResp: []uuid.UUID{sd.Users[1].ID, sd.Users[0].ID},
+			ExcFunc: func(ctx context.Context) any {
+				userIDs = nil
+
+				if _, err := busDomain.User.Update(ctx, sd.Users[0].User, userbus.UpdateUser{Enabled: dbtest.BoolPointer(true)}); err != nil {
+					return err
+				}
+
+				// Only the events added under the tracked context are relayed
+				// with RelayEvents.
+				tctx, events := outboxbus.Track(ctx)
+				if _, err := busDomain.User.Update(tctx, sd.Users[1].User, userbus.UpdateUser{Enabled: dbtest.BoolPointer(true)}); err != nil {
+					return err
+				}
+
+				n, err := busDomain.Outbox.RelayEvents(ctx, events())
+				if err != nil {
+					return err
+				}
+
+				if n != 1 {
+					return fmt.Errorf("relayed %d tracked events, exp 1", n)
+				}
+
+				n, err = busDomain.Outbox.Relay(ctx)
+				if err != nil {
+					return err
+				}
+
+				if n != 1 {
+					return fmt.Errorf("relayed %d events, exp 1", n)
+				}
+
+				return userIDs
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func claim(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "dedup",
+			ExpResp: []bool{false, true, false, true, false},
+			ExcFunc: func(ctx context.Context) any {
+				eventID := uuid.New()
+
+				claimed, err := busDomain.Outbox.Claimed(ctx, "test", eventID)
+				if err != nil {
+					return err
+				}
+				claims := []bool{claimed}
+
+				for range 2 {
+					claimed, err := busDomain.Outbox.Claim(ctx, "test", eventID)
+					if err != nil {
+						return err
+					}
+					claims = append(claims, claimed)
+				}
+
+				claimed, err = busDomain.Outbox.Claimed(ctx, "test", eventID)
+				if err != nil {
+					return err
+				}
+				claims = append(claims, claimed)
+
+				// Every consumer handles the event once.
+				claimed, err = busDomain.Outbox.Claimed(ctx, "other", eventID)
+				if err != nil {
+					return err
+				}
+				claims = append(claims, claimed)
+
+				return claims
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func noOutbox(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	// A business constructed without an outbox can only query.
+	userBus := userbus.NewBusiness(db.Log, nil, userdb.NewStore(db.Log, db.DB))
+
+	table := []unitest.Table{
+		{
+			Name:    "update",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := userBus.Update(ctx, sd.Users[0].User, userbus.UpdateUser{Enabled: dbtest.BoolPointer(true)})
+
+				return errors.Is(err, outboxbus.ErrNoOutbox)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
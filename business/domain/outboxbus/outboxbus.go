@@ -0,0 +1,199 @@
+// Package outboxbus provides business access to the outbox of events that
+// are published once the change that raised them has been committed.
+package outboxbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// ErrNoOutbox is returned when an event is added by a business that was
+// constructed without an outbox.
+var ErrNoOutbox = errors.New("no outbox provided")
+
+// relayRows is the number of messages read from the outbox at a time.
+const relayRows = 100
+
+// leaseTime is how long a relay holds the events it took from the outbox
+// before another relay can take the ones it didn't send.
+const leaseTime = time.Minute
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, msg Message) error
+	Lease(ctx context.Context, now time.Time, until time.Time, rows int) ([]Message, error)
+	LeaseByIDs(ctx context.Context, eventIDs []uuid.UUID, now time.Time, until time.Time) ([]Message, error)
+	MarkSent(ctx context.Context, msg Message) error
+	Claim(ctx context.Context, consumer string, eventID uuid.UUID, now time.Time) (bool, error)
+	Claimed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error)
+	Purge(ctx context.Context, before time.Time) error
+}
+
+// Publisher declares the behavior needed to publish the events in the
+// outbox. The delegate pubsub topic implements it.
+type Publisher interface {
+	Publish(ctx context.Context, data delegate.Data) (string, error)
+}
+
+// Business manages the set of APIs for outbox access.
+type Business struct {
+	log       *logger.Logger
+	publisher Publisher
+	storer    Storer
+}
+
+// NewBusiness constructs an outbox business API for use.
+func NewBusiness(log *logger.Logger, publisher Publisher, storer Storer) *Business {
+	return &Business{
+		log:       log,
+		publisher: publisher,
+		storer:    storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:       b.log,
+		publisher: b.publisher,
+		storer:    storer,
+	}
+
+	return &bus, nil
+}
+
+// Add writes the event to the outbox under a new event id. When the business
+// was constructed with a transaction, the event is only published if the
+// transaction commits. The id is recorded when the context is tracking the
+// events added under it. A business constructed without an outbox can only
+// query, so adding an event to a nil outbox fails with ErrNoOutbox.
+func (b *Business) Add(ctx context.Context, data delegate.Data) error {
+	if b == nil {
+		return ErrNoOutbox
+	}
+
+	msg := toMessage(data, time.Now())
+
+	if err := b.storer.Create(ctx, msg); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	if t, ok := ctx.Value(trackerKey).(*tracker); ok {
+		t.add(msg.ID)
+	}
+
+	return nil
+}
+
+// Relay publishes the events in the outbox in the order they were added and
+// marks them as sent. The events are leased while they're published, so a
+// relay running at the same time skips them. An event is published again if
+// marking it fails, so consumers must use Claimed to drop the events they
+// have already handled. It returns the number of events sent.
+func (b *Business) Relay(ctx context.Context) (int, error) {
+	var sent int
+
+	for {
+		now := time.Now()
+
+		msgs, err := b.storer.Lease(ctx, now, now.Add(leaseTime), relayRows)
+		if err != nil {
+			return sent, fmt.Errorf("lease: %w", err)
+		}
+
+		n, err := b.send(ctx, msgs)
+		sent += n
+		if err != nil {
+			return sent, err
+		}
+
+		if len(msgs) < relayRows {
+			return sent, nil
+		}
+	}
+}
+
+// RelayEvents publishes the specified events that are still in the outbox
+// and haven't been sent, leaving the rest of the outbox to Relay. It returns
+// the number of events sent.
+func (b *Business) RelayEvents(ctx context.Context, eventIDs []uuid.UUID) (int, error) {
+	if len(eventIDs) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+
+	msgs, err := b.storer.LeaseByIDs(ctx, eventIDs, now, now.Add(leaseTime))
+	if err != nil {
+		return 0, fmt.Errorf("leasebyids: %w", err)
+	}
+
+	return b.send(ctx, msgs)
+}
+
+// send publishes the leased messages and marks them as sent.
+func (b *Business) send(ctx context.Context, msgs []Message) (int, error) {
+	for i, msg := range msgs {
+		if _, err := b.publisher.Publish(ctx, toData(msg)); err != nil {
+			return i, fmt.Errorf("publish: eventID[%s]: %w", msg.ID, err)
+		}
+
+		msg.DateSent = time.Now()
+
+		if err := b.storer.MarkSent(ctx, msg); err != nil {
+			return i, fmt.Errorf("marksent: eventID[%s]: %w", msg.ID, err)
+		}
+	}
+
+	return len(msgs), nil
+}
+
+// Claim records that the consumer has handled the event. It returns false
+// when the consumer had already claimed the event. An event is claimed once
+// it's handled, so a consumer that fails or dies while handling it handles
+// it again when it's delivered again. Consumers must then be able to handle
+// an event more than once.
+func (b *Business) Claim(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error) {
+	claimed, err := b.storer.Claim(ctx, consumer, eventID, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("claim: consumer[%s] eventID[%s]: %w", consumer, eventID, err)
+	}
+
+	return claimed, nil
+}
+
+// Claimed reports whether the consumer has claimed the event, in which case
+// it has already been handled and should be dropped.
+func (b *Business) Claimed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error) {
+	claimed, err := b.storer.Claimed(ctx, consumer, eventID)
+	if err != nil {
+		return false, fmt.Errorf("claimed: consumer[%s] eventID[%s]: %w", consumer, eventID, err)
+	}
+
+	return claimed, nil
+}
+
+// Purge removes the events sent and the claims made before the specified
+// time. It must be long enough ago that the events won't be delivered again.
+func (b *Business) Purge(ctx context.Context, before time.Time) error {
+	if err := b.storer.Purge(ctx, before); err != nil {
+		return fmt.Errorf("purge: %w", err)
+	}
+
+	return nil
+}
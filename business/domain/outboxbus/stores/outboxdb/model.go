@@ -0,0 +1,60 @@
+package outboxdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
+	"github.com/google/uuid"
+)
+
+type message struct {
+	ID          uuid.UUID    `db:"event_id"`
+	Domain      string       `db:"domain"`
+	Action      string       `db:"action"`
+	Params      []byte       `db:"params"`
+	DateCreated time.Time    `db:"date_created"`
+	DateSent    sql.NullTime `db:"date_sent"`
+}
+
+func toDBMessage(bus outboxbus.Message) message {
+	db := message{
+		ID:          bus.ID,
+		Domain:      bus.Domain,
+		Action:      bus.Action,
+		Params:      bus.RawParams,
+		DateCreated: bus.DateCreated.UTC(),
+		DateSent: sql.NullTime{
+			Time:  bus.DateSent.UTC(),
+			Valid: !bus.DateSent.IsZero(),
+		},
+	}
+
+	return db
+}
+
+func toBusMessage(db message) outboxbus.Message {
+	bus := outboxbus.Message{
+		ID:          db.ID,
+		Domain:      db.Domain,
+		Action:      db.Action,
+		RawParams:   db.Params,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	if db.DateSent.Valid {
+		bus.DateSent = db.DateSent.Time.In(time.Local)
+	}
+
+	return bus
+}
+
+func toBusMessages(dbs []message) []outboxbus.Message {
+	bus := make([]outboxbus.Message, len(dbs))
+
+	for i, db := range dbs {
+		bus[i] = toBusMessage(db)
+	}
+
+	return bus
+}
@@ -0,0 +1,282 @@
+// Package outboxdb contains outbox related CRUD functionality.
+package outboxdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for outbox database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (outboxbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new message into the outbox.
+func (s *Store) Create(ctx context.Context, msg outboxbus.Message) error {
+	const q = `
+	INSERT INTO outbox
+		(event_id, domain, action, params, date_created, date_sent)
+	VALUES
+		(:event_id, :domain, :action, :params, :date_created, :date_sent)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBMessage(msg)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Lease takes up to rows of the oldest messages that haven't been sent and
+// aren't leased, holding them until the specified time. Messages another
+// call is leasing at the same time are skipped.
+func (s *Store) Lease(ctx context.Context, now time.Time, until time.Time, rows int) ([]outboxbus.Message, error) {
+	data := struct {
+		Now   time.Time `db:"now"`
+		Until time.Time `db:"until"`
+		Rows  int       `db:"rows"`
+	}{
+		Now:   now.UTC(),
+		Until: until.UTC(),
+		Rows:  rows,
+	}
+
+	const q = `
+	WITH leased AS (
+		UPDATE
+			outbox
+		SET
+			"date_leased" = :until
+		WHERE
+			event_id IN (
+				SELECT
+					event_id
+				FROM
+					outbox
+				WHERE
+					date_sent IS NULL AND
+					(date_leased IS NULL OR date_leased < :now)
+				ORDER BY
+					date_created, event_id
+				LIMIT :rows
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			event_id, domain, action, params, date_created, date_sent
+	)
+	SELECT
+		event_id, domain, action, params, date_created, date_sent
+	FROM
+		leased
+	ORDER BY
+		date_created, event_id`
+
+	var dbMsgs []message
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbMsgs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusMessages(dbMsgs), nil
+}
+
+// LeaseByIDs takes the specified messages that haven't been sent and aren't
+// leased, holding them until the specified time.
+func (s *Store) LeaseByIDs(ctx context.Context, eventIDs []uuid.UUID, now time.Time, until time.Time) ([]outboxbus.Message, error) {
+	if len(eventIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(eventIDs))
+	for i, id := range eventIDs {
+		ids[i] = id.String()
+	}
+
+	data := struct {
+		IDs   []string  `db:"event_ids"`
+		Now   time.Time `db:"now"`
+		Until time.Time `db:"until"`
+	}{
+		IDs:   ids,
+		Now:   now.UTC(),
+		Until: until.UTC(),
+	}
+
+	const q = `
+	WITH leased AS (
+		UPDATE
+			outbox
+		SET
+			"date_leased" = :until
+		WHERE
+			event_id IN (
+				SELECT
+					event_id
+				FROM
+					outbox
+				WHERE
+					event_id IN (:event_ids) AND
+					date_sent IS NULL AND
+					(date_leased IS NULL OR date_leased < :now)
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			event_id, domain, action, params, date_created, date_sent
+	)
+	SELECT
+		event_id, domain, action, params, date_created, date_sent
+	FROM
+		leased
+	ORDER BY
+		date_created, event_id`
+
+	var dbMsgs []message
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, q, data, &dbMsgs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusMessages(dbMsgs), nil
+}
+
+// MarkSent records the date the message was sent.
+func (s *Store) MarkSent(ctx context.Context, msg outboxbus.Message) error {
+	const q = `
+	UPDATE
+		outbox
+	SET
+		"date_sent" = :date_sent
+	WHERE
+		event_id = :event_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBMessage(msg)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Claim records the consumer as having handled the event unless it already
+// has. It reports whether the claim was recorded.
+func (s *Store) Claim(ctx context.Context, consumer string, eventID uuid.UUID, now time.Time) (bool, error) {
+	data := struct {
+		Consumer    string    `db:"consumer"`
+		EventID     string    `db:"event_id"`
+		DateCreated time.Time `db:"date_created"`
+	}{
+		Consumer:    consumer,
+		EventID:     eventID.String(),
+		DateCreated: now.UTC(),
+	}
+
+	const q = `
+	INSERT INTO outbox_claims
+		(consumer, event_id, date_created)
+	VALUES
+		(:consumer, :event_id, :date_created)
+	ON CONFLICT DO NOTHING
+	RETURNING
+		event_id`
+
+	var dbClaim struct {
+		EventID uuid.UUID `db:"event_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbClaim); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return true, nil
+}
+
+// Claimed reports whether the consumer has claimed the event.
+func (s *Store) Claimed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error) {
+	data := struct {
+		Consumer string `db:"consumer"`
+		EventID  string `db:"event_id"`
+	}{
+		Consumer: consumer,
+		EventID:  eventID.String(),
+	}
+
+	const q = `
+	SELECT
+		event_id
+	FROM
+		outbox_claims
+	WHERE
+		consumer = :consumer AND
+		event_id = :event_id`
+
+	var dbClaim struct {
+		EventID uuid.UUID `db:"event_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbClaim); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return true, nil
+}
+
+// Purge removes the messages sent and the claims made before the specified
+// time.
+func (s *Store) Purge(ctx context.Context, before time.Time) error {
+	data := struct {
+		Before time.Time `db:"before"`
+	}{
+		Before: before.UTC(),
+	}
+
+	const q = `
+	WITH claims AS (
+		DELETE FROM
+			outbox_claims
+		WHERE
+			date_created < :before
+	)
+	DELETE FROM
+		outbox
+	WHERE
+		date_sent < :before`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
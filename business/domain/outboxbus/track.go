@@ -0,0 +1,41 @@
+package outboxbus
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type ctxKey int
+
+const trackerKey ctxKey = 1
+
+// tracker holds the ids of the events added to the outbox under a context.
+type tracker struct {
+	mu  sync.Mutex
+	ids []uuid.UUID
+}
+
+func (t *tracker) add(id uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ids = append(t.ids, id)
+}
+
+// Track returns a context that records the ids of the events added to the
+// outbox under it, along with a function that returns those ids. It lets a
+// caller relay only the events written by its own transaction.
+func Track(ctx context.Context) (context.Context, func() []uuid.UUID) {
+	t := tracker{}
+
+	ids := func() []uuid.UUID {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		return append([]uuid.UUID(nil), t.ids...)
+	}
+
+	return context.WithValue(ctx, trackerKey, &t), ids
+}
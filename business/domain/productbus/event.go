@@ -4,17 +4,165 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/google/uuid"
 )
 
+// DomainName represents the name of this domain.
+const DomainName = "product"
+
+// Set of delegate actions.
+const (
+	ActionCreated      = "created"
+	ActionUpdated      = "updated"
+	ActionDeleted      = "deleted"
+	ActionLowStock     = "low_stock"
+	ActionPriceChanged = "price_changed"
+)
+
+// ActionChangedParms represents the parameters for the created, updated and
+// deleted actions.
+type ActionChangedParms struct {
+	ProductID uuid.UUID
+	UserID    uuid.UUID
+}
+
+// String returns a string representation of the action parameters.
+func (ac *ActionChangedParms) String() string {
+	return fmt.Sprintf("&EventParamsChanged{ProductID:%v, UserID:%v}", ac.ProductID, ac.UserID)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (ac *ActionChangedParms) Marshal() ([]byte, error) {
+	return json.Marshal(ac)
+}
+
+// ActionChangedData constructs the data for the created, updated or deleted
+// action specified.
+func ActionChangedData(action string, prd Product) delegate.Data {
+	params := ActionChangedParms{
+		ProductID: prd.ID,
+		UserID:    prd.UserID,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    action,
+		RawParams: rawParams,
+	}
+}
+
+// ActionLowStockParms represents the parameters for the low stock action.
+type ActionLowStockParms struct {
+	ProductID    uuid.UUID
+	UserID       uuid.UUID
+	Name         string
+	Quantity     int
+	ReorderLevel int
+}
+
+// String returns a string representation of the action parameters.
+func (al *ActionLowStockParms) String() string {
+	return fmt.Sprintf("&EventParamsLowStock{ProductID:%v, Quantity:%v, ReorderLevel:%v}", al.ProductID, al.Quantity, al.ReorderLevel)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (al *ActionLowStockParms) Marshal() ([]byte, error) {
+	return json.Marshal(al)
+}
+
+// ActionLowStockData constructs the data for the low stock action.
+func ActionLowStockData(prd Product) delegate.Data {
+	params := ActionLowStockParms{
+		ProductID:    prd.ID,
+		UserID:       prd.UserID,
+		Name:         prd.Name.String(),
+		Quantity:     prd.Quantity,
+		ReorderLevel: prd.ReorderLevel,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    ActionLowStock,
+		RawParams: rawParams,
+	}
+}
+
+// ActionPriceChangedParms represents the parameters for the price changed
+// action.
+type ActionPriceChangedParms struct {
+	ProductID     uuid.UUID
+	UserID        uuid.UUID
+	PriceID       uuid.UUID
+	OldCost       float64
+	Cost          float64
+	DateEffective time.Time
+}
+
+// String returns a string representation of the action parameters.
+func (ap *ActionPriceChangedParms) String() string {
+	return fmt.Sprintf("&EventParamsPriceChanged{ProductID:%v, PriceID:%v, OldCost:%v, Cost:%v}", ap.ProductID, ap.PriceID, ap.OldCost, ap.Cost)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (ap *ActionPriceChangedParms) Marshal() ([]byte, error) {
+	return json.Marshal(ap)
+}
+
+// ActionPriceChangedData constructs the data for the price changed action.
+// The product is expected to hold the cost from before the change.
+func ActionPriceChangedData(prd Product, prc Price) delegate.Data {
+	params := ActionPriceChangedParms{
+		ProductID:     prd.ID,
+		UserID:        prd.UserID,
+		PriceID:       prc.ID,
+		OldCost:       prd.Cost,
+		Cost:          prc.Cost,
+		DateEffective: prc.DateEffective,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    ActionPriceChanged,
+		RawParams: rawParams,
+	}
+}
+
+// =============================================================================
+
+// userUpdatedRetry gives the products of a user more time to be brought in
+// line with the user before the event is dead lettered, since a missed
+// update leaves the products of a disabled user active.
+var userUpdatedRetry = delegate.Retry{
+	Attempts:   5,
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 10 * time.Second,
+}
+
 // registerDelegateFunctions will register action functions with the delegate
 // system. If the business was constructed for query only, there won't be a
 // delegate provided.
 func (b *Business) registerDelegateFunctions() {
 	if b.delegate != nil {
-		b.delegate.Register(userbus.DomainName, userbus.ActionUpdated, b.actionUserUpdated)
+		b.delegate.Register(userbus.DomainName, userbus.ActionUpdated, "productbus.user-updated", b.actionUserUpdated, delegate.WithMode(delegate.Async), delegate.WithRetry(userUpdatedRetry))
 	}
 }
 
@@ -28,9 +176,16 @@ func (b *Business) actionUserUpdated(ctx context.Context, data delegate.Data) er
 
 	b.log.Info(ctx, "action-userupdate", "user_id", params.UserID, "enabled", params.Enabled)
 
-	// Now we can see if this user has been disabled. If they have been, we will
-	// want to disable to mark all these products as deleted. Right now we don't
-	// have support for this, but you can see how we can process the event.
+	// The products of a disabled user are deactivated so they drop out of
+	// the default product queries. They are activated again when the user
+	// is enabled.
+	if params.Enabled == nil {
+		return nil
+	}
+
+	if err := b.storer.SetActiveByUserID(ctx, params.UserID, *params.Enabled, time.Now()); err != nil {
+		return fmt.Errorf("setactivebyuserid: userID[%s]: %w", params.UserID, err)
+	}
 
 	return nil
 }
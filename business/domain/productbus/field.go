@@ -0,0 +1,42 @@
+package productbus
+
+import (
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
+	"github.com/ardanlabs/encore/business/sdk/order"
+)
+
+// Set of fields a query can be limited to.
+const (
+	FieldID           = "product_id"
+	FieldUserID       = "user_id"
+	FieldName         = "name"
+	FieldCost         = "cost"
+	FieldQuantity     = "quantity"
+	FieldReorderLevel = "reorder_level"
+	FieldLowStock     = "low_stock_alerted"
+	FieldActive       = "active"
+	FieldDateCreated  = "date_created"
+	FieldDateUpdated  = "date_updated"
+)
+
+// orderFields maps the fields results can be ordered by to the field that
+// holds their value. Rank is worked out by every query.
+var orderFields = map[string]string{
+	OrderByProductID: FieldID,
+	OrderByUserID:    FieldUserID,
+	OrderByName:      FieldName,
+	OrderByCost:      FieldCost,
+	OrderByQuantity:  FieldQuantity,
+}
+
+// withOrderFields adds the fields holding the values the results are ordered
+// by to the set.
+func withOrderFields(fields fieldset.Set, orderBy order.By) fieldset.Set {
+	for _, key := range orderBy.Keys() {
+		if field, exists := orderFields[key.Field]; exists {
+			fields = fields.With(field)
+		}
+	}
+
+	return fields
+}
@@ -1,14 +1,26 @@
 package productbus
 
 import (
+	"github.com/ardanlabs/encore/business/sdk/filter"
 	"github.com/google/uuid"
 )
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
 type QueryFilter struct {
-	ID       *uuid.UUID
+	ID       []filter.Condition[uuid.UUID]
 	Name     *Name
-	Cost     *float64
-	Quantity *int
+	Cost     []filter.Condition[float64]
+	Quantity []filter.Condition[int]
+	Active   *bool
+
+	// CategoryID matches products in the category or any of its descendants.
+	CategoryID *uuid.UUID
+
+	// Tag matches products carrying a tag with this name.
+	Tag *string
+
+	// Search matches products against a web search style query such as
+	// `"red guitar" -used`.
+	Search *string
 }
@@ -6,23 +6,32 @@ import (
 	"github.com/google/uuid"
 )
 
-// Product represents an individual product.
+// Product represents an individual product. A product is inactive while the
+// user who owns it is disabled. Snippet and Rank are only set when the product
+// was found through a search, they highlight the matching text and tell how
+// well it matched.
 type Product struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	Name        Name
-	Cost        float64
-	Quantity    int
-	DateCreated time.Time
-	DateUpdated time.Time
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Name            Name
+	Cost            float64
+	Quantity        int
+	ReorderLevel    int
+	LowStockAlerted bool
+	Active          bool
+	Snippet         string
+	Rank            float64
+	DateCreated     time.Time
+	DateUpdated     time.Time
 }
 
 // NewProduct is what we require from clients when adding a Product.
 type NewProduct struct {
-	UserID   uuid.UUID
-	Name     Name
-	Cost     float64
-	Quantity int
+	UserID       uuid.UUID
+	Name         Name
+	Cost         float64
+	Quantity     int
+	ReorderLevel int
 }
 
 // UpdateProduct defines what information may be provided to modify an
@@ -32,7 +41,57 @@ type NewProduct struct {
 // explicitly blank. Normally we do not want to use pointers to basic types but
 // we make exceptions around marshalling/unmarshalling.
 type UpdateProduct struct {
-	Name     *Name
-	Cost     *float64
-	Quantity *int
+	Name         *Name
+	Cost         *float64
+	Quantity     *int
+	ReorderLevel *int
+}
+
+// Movement represents an entry in the stock ledger for a product. The
+// quantity is signed, so the sum of all movements for a product is the
+// quantity on hand.
+type Movement struct {
+	ID          uuid.UUID
+	ProductID   uuid.UUID
+	Type        MovementType
+	Quantity    int
+	Reason      string
+	DateCreated time.Time
+}
+
+// NewMovement is what we require from clients when recording a stock
+// movement. The quantity is the number of units moved and must be positive
+// for receipts, sales and returns. Adjustments may be positive or negative.
+type NewMovement struct {
+	Type     MovementType
+	Quantity int
+	Reason   string
+}
+
+// Reconciliation represents the result of comparing the quantity recorded
+// on a product against the quantity computed from its stock ledger.
+type Reconciliation struct {
+	ProductID      uuid.UUID
+	Quantity       int
+	LedgerQuantity int
+	Balanced       bool
+}
+
+// Price represents an entry in the price timeline of a product. A price
+// that has not been applied yet is a scheduled change and has a zero
+// DateApplied.
+type Price struct {
+	ID            uuid.UUID
+	ProductID     uuid.UUID
+	Cost          float64
+	DateEffective time.Time
+	DateApplied   time.Time
+	DateCreated   time.Time
+}
+
+// NewPrice is what we require from clients when scheduling a future price
+// change for a product.
+type NewPrice struct {
+	Cost          float64
+	DateEffective time.Time
 }
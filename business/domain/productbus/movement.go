@@ -0,0 +1,68 @@
+package productbus
+
+import "fmt"
+
+type movementTypeSet struct {
+	Receipt    MovementType
+	Sale       MovementType
+	Adjustment MovementType
+	Return     MovementType
+}
+
+// MovementTypes represents the set of stock movement types that can be used.
+var MovementTypes = movementTypeSet{
+	Receipt:    newMovementType("RECEIPT"),
+	Sale:       newMovementType("SALE"),
+	Adjustment: newMovementType("ADJUSTMENT"),
+	Return:     newMovementType("RETURN"),
+}
+
+// =============================================================================
+
+// Set of known movement types.
+var movementTypes = make(map[string]MovementType)
+
+// MovementType represents the reason stock for a product changed.
+type MovementType struct {
+	name string
+}
+
+func newMovementType(typ string) MovementType {
+	t := MovementType{typ}
+	movementTypes[typ] = t
+	return t
+}
+
+// String returns the name of the movement type.
+func (t MovementType) String() string {
+	return t.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (t MovementType) Equal(t2 MovementType) bool {
+	return t.name == t2.name
+}
+
+// =============================================================================
+
+// ParseMovementType parses the string value and returns a movement type if
+// one exists.
+func ParseMovementType(value string) (MovementType, error) {
+	typ, exists := movementTypes[value]
+	if !exists {
+		return MovementType{}, fmt.Errorf("invalid movement type %q", value)
+	}
+
+	return typ, nil
+}
+
+// MustParseMovementType parses the string value and returns a movement type
+// if one exists. If an error occurs the function panics.
+func MustParseMovementType(value string) MovementType {
+	typ, err := ParseMovementType(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return typ
+}
@@ -12,4 +12,5 @@ const (
 	OrderByName      = "name"
 	OrderByCost      = "cost"
 	OrderByQuantity  = "quantity"
+	OrderByRank      = "rank"
 )
@@ -2,7 +2,10 @@ package productbus_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"testing"
 	"time"
@@ -10,10 +13,17 @@ import (
 	"encore.dev/et"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/bulk"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
+	"github.com/ardanlabs/encore/business/sdk/filter"
+	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/business/sdk/unitest"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 )
 
 func Test_Product(t *testing.T) {
@@ -34,8 +44,12 @@ func Test_Product(t *testing.T) {
 	// -------------------------------------------------------------------------
 
 	unitest.Run(t, query(db.BusDomain, sd), "query")
+	unitest.Run(t, search(db.BusDomain, sd), "search")
 	unitest.Run(t, create(db.BusDomain, sd), "create")
 	unitest.Run(t, update(db.BusDomain, sd), "update")
+	unitest.Run(t, movement(db.BusDomain, sqldb.NewBeginner(db.DB), sd), "movement")
+	unitest.Run(t, price(db.BusDomain, sqldb.NewBeginner(db.DB), sd), "price")
+	unitest.Run(t, active(db.BusDomain, sd), "active")
 	unitest.Run(t, delete(db.BusDomain, sd), "delete")
 }
 
@@ -88,6 +102,50 @@ func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
 
 // =============================================================================
 
+// multiOrderIDs returns the ids of the products ordered by user and then by
+// id in descending order.
+func multiOrderIDs(prds []productbus.Product) []uuid.UUID {
+	prds = slices.Clone(prds)
+	sort.Slice(prds, func(i, j int) bool {
+		if prds[i].UserID != prds[j].UserID {
+			return prds[i].UserID.String() < prds[j].UserID.String()
+		}
+		return prds[i].ID.String() > prds[j].ID.String()
+	})
+
+	ids := make([]uuid.UUID, len(prds))
+	for i, prd := range prds {
+		ids[i] = prd.ID
+	}
+
+	return ids
+}
+
+// nameOnly returns the products with only the id and the name set.
+func nameOnly(prds []productbus.Product) []productbus.Product {
+	names := make([]productbus.Product, len(prds))
+	for i, prd := range prds {
+		names[i] = productbus.Product{
+			ID:   prd.ID,
+			Name: prd.Name,
+		}
+	}
+
+	return names
+}
+
+// conditionIDs returns the ids of the products costing at least minCost.
+func conditionIDs(prds []productbus.Product, minCost float64) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, prd := range prds {
+		if prd.Cost >= minCost {
+			ids = append(ids, prd.ID)
+		}
+	}
+
+	return ids
+}
+
 func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	prds := make([]productbus.Product, 0, len(sd.Admins[0].Products)+len(sd.Users[0].Products))
 	prds = append(prds, sd.Admins[0].Products...)
@@ -106,7 +164,7 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 					Name: dbtest.ProductNamePointer("Name"),
 				}
 
-				resp, err := busDomain.Product.Query(ctx, filter, productbus.DefaultOrderBy, page.MustParse("1", "10"))
+				resp, err := busDomain.Product.Query(ctx, filter, fieldset.Set{}, productbus.DefaultOrderBy, page.MustParse("1", "10"))
 				if err != nil {
 					return err
 				}
@@ -134,6 +192,159 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "multiorder",
+			ExpResp: multiOrderIDs(prds),
+			ExcFunc: func(ctx context.Context) any {
+				filter := productbus.QueryFilter{
+					Name: dbtest.ProductNamePointer("Name"),
+				}
+
+				orderBy := order.NewBy(productbus.OrderByUserID, order.ASC).ThenBy(productbus.OrderByProductID, order.DESC)
+
+				// Page through a row at a time so the cursor has to compare
+				// columns ordered in different directions.
+				var ids []uuid.UUID
+				pg := page.MustParse("1", "1")
+				for {
+					resp, err := busDomain.Product.Query(ctx, filter, fieldset.Set{}, orderBy, pg)
+					if err != nil {
+						return err
+					}
+
+					if len(resp) == 0 {
+						break
+					}
+					ids = append(ids, resp[0].ID)
+
+					_, next := pg.Cursors(orderBy, len(resp), func(i int) page.Key {
+						return page.Key{
+							Values: []string{resp[i].UserID.String(), resp[i].ID.String()},
+							ID:     resp[i].ID.String(),
+						}
+					})
+					pg = page.MustParseCursor(next, "1")
+				}
+
+				return ids
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "fields",
+			ExpResp: nameOnly(prds),
+			ExcFunc: func(ctx context.Context) any {
+				filter := productbus.QueryFilter{
+					Name: dbtest.ProductNamePointer("Name"),
+				}
+
+				fields := fieldset.New(productbus.FieldName)
+
+				resp, err := busDomain.Product.Query(ctx, filter, fields, productbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "conditions",
+			ExpResp: conditionIDs(prds[1:3], prds[1].Cost),
+			ExcFunc: func(ctx context.Context) any {
+				filter := productbus.QueryFilter{
+					ID: []filter.Condition[uuid.UUID]{
+						filter.In(prds[0].ID, prds[1].ID, prds[2].ID),
+						filter.Compare(filter.Operators.NE, prds[0].ID),
+					},
+					Cost: []filter.Condition[float64]{
+						filter.Compare(filter.Operators.GTE, prds[1].Cost),
+					},
+				}
+
+				resp, err := busDomain.Product.Query(ctx, filter, fieldset.Set{}, productbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				ids := make([]uuid.UUID, len(resp))
+				for i, prd := range resp {
+					ids[i] = prd.ID
+				}
+
+				return ids
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "lookahead",
+			ExpResp: len(prds[:3]),
+			ExcFunc: func(ctx context.Context) any {
+				filter := productbus.QueryFilter{
+					Name: dbtest.ProductNamePointer("Name"),
+				}
+
+				resp, err := busDomain.Product.Query(ctx, filter, fieldset.Set{}, productbus.DefaultOrderBy, page.MustParse("1", "2").WithLookahead())
+				if err != nil {
+					return err
+				}
+
+				return len(resp)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "each",
+			ExpResp: conditionIDs(prds, 0),
+			ExcFunc: func(ctx context.Context) any {
+				filter := productbus.QueryFilter{
+					Name: dbtest.ProductNamePointer("Name"),
+				}
+
+				var ids []uuid.UUID
+				err := busDomain.Product.QueryEach(ctx, filter, productbus.DefaultOrderBy, func(prd productbus.Product) error {
+					ids = append(ids, prd.ID)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+
+				return ids
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "byids",
+			ExpResp: []uuid.UUID{sd.Users[0].Products[0].ID},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Product.QueryByIDs(ctx, []uuid.UUID{sd.Users[0].Products[0].ID, uuid.New()})
+				if err != nil {
+					return err
+				}
+
+				ids := make([]uuid.UUID, len(resp))
+				for i, prd := range resp {
+					ids[i] = prd.ID
+				}
+
+				return ids
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 		{
 			Name:    "byid",
 			ExpResp: sd.Users[0].Products[0],
@@ -169,6 +380,55 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	return table
 }
 
+func search(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "snippet",
+			ExpResp: map[string]string{
+				"Player's Guitar": "Player&#39;s <b>Guitar</b>",
+				"Guitar Strings":  "<b>Guitar</b> Strings",
+			},
+			ExcFunc: func(ctx context.Context) any {
+				for _, name := range []string{"Player's Guitar", "Guitar Strings", "Drum Kit"} {
+					np := productbus.NewProduct{
+						UserID:   sd.Users[0].ID,
+						Name:     productbus.MustParseName(name),
+						Cost:     10,
+						Quantity: 1,
+					}
+
+					if _, err := busDomain.Product.Create(ctx, np); err != nil {
+						return err
+					}
+				}
+
+				filter := productbus.QueryFilter{
+					Search: dbtest.StringPointer("guitars"),
+				}
+
+				orderBy := order.NewBy(productbus.OrderByRank, order.DESC)
+
+				resp, err := busDomain.Product.Query(ctx, filter, fieldset.Set{}, orderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				snippets := make(map[string]string)
+				for _, prd := range resp {
+					snippets[prd.Name.String()] = prd.Snippet
+				}
+
+				return snippets
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
 func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	table := []unitest.Table{
 		{
@@ -178,6 +438,7 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				Name:     productbus.MustParseName("Guitar"),
 				Cost:     10.34,
 				Quantity: 10,
+				Active:   true,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				np := productbus.NewProduct{
@@ -209,6 +470,73 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "many",
+			ExpResp: []int{10, 5},
+			ExcFunc: func(ctx context.Context) any {
+				nps := []productbus.NewProduct{
+					{
+						UserID:   sd.Users[0].ID,
+						Name:     productbus.MustParseName("Piano"),
+						Cost:     120.50,
+						Quantity: 10,
+					},
+					{
+						UserID:   sd.Users[0].ID,
+						Name:     productbus.MustParseName("Violin"),
+						Cost:     80,
+						Quantity: 5,
+					},
+				}
+
+				resp, err := busDomain.Product.CreateMany(ctx, nps)
+				if err != nil {
+					return err
+				}
+
+				quantities := make([]int, len(resp))
+				for i, prd := range resp {
+					quantities[i] = prd.Quantity
+				}
+
+				return quantities
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "manyinvalid",
+			ExpResp: map[int]bool{1: true},
+			ExcFunc: func(ctx context.Context) any {
+				nps := []productbus.NewProduct{
+					{
+						UserID:   sd.Users[0].ID,
+						Name:     productbus.MustParseName("Drum"),
+						Cost:     30,
+						Quantity: 1,
+					},
+					{
+						UserID:   sd.Users[0].ID,
+						Name:     productbus.MustParseName("Flute"),
+						Cost:     -1,
+						Quantity: 1,
+					},
+				}
+
+				_, err := busDomain.Product.CreateMany(ctx, nps)
+
+				itemErrs, ok := bulk.AsErrors(err)
+				if !ok {
+					return err
+				}
+
+				return itemErrs.Indexes()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 	}
 
 	return table
@@ -224,6 +552,7 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				Name:        productbus.MustParseName("Guitar"),
 				Cost:        10.34,
 				Quantity:    10,
+				Active:      true,
 				DateCreated: sd.Users[0].Products[0].DateCreated,
 				DateUpdated: sd.Users[0].Products[0].DateCreated,
 			},
@@ -259,6 +588,544 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	return table
 }
 
+func movement(busDomain dbtest.BusDomain, bgn sqldb.Beginner, sd unitest.SeedData) []unitest.Table {
+	prd := sd.Admins[0].Products[0]
+
+	// The ids of the products in the low stock events that were relayed.
+	var lowStockIDs []uuid.UUID
+	busDomain.Delegate.Register(productbus.DomainName, productbus.ActionLowStock, "test", func(ctx context.Context, data delegate.Data) error {
+		var params productbus.ActionLowStockParms
+		if err := json.Unmarshal(data.RawParams, &params); err != nil {
+			return err
+		}
+
+		lowStockIDs = append(lowStockIDs, params.ProductID)
+		return nil
+	})
+
+	table := []unitest.Table{
+		{
+			Name: "sale",
+			ExpResp: productbus.Movement{
+				ProductID: prd.ID,
+				Type:      productbus.MovementTypes.Sale,
+				Quantity:  -1,
+				Reason:    "order 1001",
+			},
+			ExcFunc: func(ctx context.Context) any {
+				nm := productbus.NewMovement{
+					Type:     productbus.MovementTypes.Sale,
+					Quantity: 1,
+					Reason:   "order 1001",
+				}
+
+				updPrd, mov, err := busDomain.Product.CreateMovement(ctx, prd, nm)
+				if err != nil {
+					return err
+				}
+
+				if updPrd.Quantity != prd.Quantity-1 {
+					return fmt.Errorf("expected quantity %d, got %d", prd.Quantity-1, updPrd.Quantity)
+				}
+
+				return mov
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(productbus.Movement)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				expResp := exp.(productbus.Movement)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "insufficient",
+			ExpResp: productbus.ErrInsufficientStock,
+			ExcFunc: func(ctx context.Context) any {
+				cur, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				nm := productbus.NewMovement{
+					Type:     productbus.MovementTypes.Sale,
+					Quantity: cur.Quantity + 1,
+					Reason:   "order 1002",
+				}
+
+				_, _, err = busDomain.Product.CreateMovement(ctx, cur, nm)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "stale",
+			ExpResp: productbus.ErrInsufficientStock,
+			ExcFunc: func(ctx context.Context) any {
+
+				// The product value still holds the quantity from before the
+				// first sale, the stock is checked against the database.
+				nm := productbus.NewMovement{
+					Type:     productbus.MovementTypes.Sale,
+					Quantity: prd.Quantity,
+					Reason:   "order 1003",
+				}
+
+				_, _, err := busDomain.Product.CreateMovement(ctx, prd, nm)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "missing",
+			ExpResp: productbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				missing := prd
+				missing.ID = uuid.New()
+
+				nm := productbus.NewMovement{
+					Type:     productbus.MovementTypes.Receipt,
+					Quantity: 1,
+					Reason:   "order 1004",
+				}
+
+				_, _, err := busDomain.Product.CreateMovement(ctx, missing, nm)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name: "ledger",
+			ExpResp: []productbus.MovementType{
+				productbus.MovementTypes.Receipt,
+				productbus.MovementTypes.Sale,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				movs, err := busDomain.Product.QueryMovements(ctx, prd.ID, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				types := make([]productbus.MovementType, len(movs))
+				for i, mov := range movs {
+					types[i] = mov.Type
+				}
+
+				return types
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "reconcile",
+			ExpResp: productbus.Reconciliation{
+				ProductID:      prd.ID,
+				Quantity:       prd.Quantity - 1,
+				LedgerQuantity: prd.Quantity - 1,
+				Balanced:       true,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				cur, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Product.Reconcile(ctx, cur)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "staleupdate",
+			ExpResp: productbus.Reconciliation{
+				ProductID:      prd.ID,
+				Quantity:       prd.Quantity + 5,
+				LedgerQuantity: prd.Quantity + 5,
+				Balanced:       true,
+			},
+			ExcFunc: func(ctx context.Context) any {
+
+				// The product value still holds the quantity from before the
+				// sale, the adjustment is taken against the database.
+				if _, err := busDomain.Product.Update(ctx, prd, productbus.UpdateProduct{Quantity: dbtest.IntPointer(prd.Quantity + 5)}); err != nil {
+					return err
+				}
+
+				cur, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Product.Reconcile(ctx, cur)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "lowstock",
+			ExpResp: []bool{true, false},
+			ExcFunc: func(ctx context.Context) any {
+				cur, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				level := cur.Quantity
+				cur, err = busDomain.Product.Update(ctx, cur, productbus.UpdateProduct{ReorderLevel: &level})
+				if err != nil {
+					return err
+				}
+
+				alerted := []bool{cur.LowStockAlerted}
+
+				nm := productbus.NewMovement{
+					Type:     productbus.MovementTypes.Receipt,
+					Quantity: 1,
+					Reason:   "restock",
+				}
+
+				cur, _, err = busDomain.Product.CreateMovement(ctx, cur, nm)
+				if err != nil {
+					return err
+				}
+
+				return append(alerted, cur.LowStockAlerted)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "lowstockevent",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				np := productbus.NewProduct{
+					Name:         productbus.MustParseName("Reorder"),
+					Cost:         10,
+					Quantity:     5,
+					ReorderLevel: 4,
+					UserID:       sd.Admins[0].ID,
+				}
+
+				cur, err := busDomain.Product.Create(ctx, np)
+				if err != nil {
+					return err
+				}
+
+				nm := productbus.NewMovement{
+					Type:     productbus.MovementTypes.Sale,
+					Quantity: 1,
+					Reason:   "order 1002",
+				}
+
+				// sell drops the product to its reorder level inside a
+				// transaction that is committed or rolled back, then relays
+				// the outbox.
+				sell := func(commit bool) error {
+					tx, err := bgn.Begin()
+					if err != nil {
+						return err
+					}
+					defer tx.Rollback()
+
+					productBus, err := busDomain.Product.NewWithTx(tx)
+					if err != nil {
+						return err
+					}
+
+					if _, _, err := productBus.CreateMovement(ctx, cur, nm); err != nil {
+						return err
+					}
+
+					if commit {
+						if err := tx.Commit(); err != nil {
+							return err
+						}
+					}
+
+					_, err = busDomain.Outbox.Relay(ctx)
+					return err
+				}
+
+				lowStockIDs = nil
+
+				// A sale that is rolled back raises no alert.
+				if err := sell(false); err != nil {
+					return err
+				}
+
+				if err := sell(true); err != nil {
+					return err
+				}
+
+				var n int
+				for _, id := range lowStockIDs {
+					if id == cur.ID {
+						n++
+					}
+				}
+
+				return n
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func price(busDomain dbtest.BusDomain, bgn sqldb.Beginner, sd unitest.SeedData) []unitest.Table {
+	prd := sd.Admins[0].Products[0]
+
+	// The ids of the prices in the price changed events that were relayed.
+	var priceIDs []uuid.UUID
+	busDomain.Delegate.Register(productbus.DomainName, productbus.ActionPriceChanged, "test", func(ctx context.Context, data delegate.Data) error {
+		var params productbus.ActionPriceChangedParms
+		if err := json.Unmarshal(data.RawParams, &params); err != nil {
+			return err
+		}
+
+		priceIDs = append(priceIDs, params.PriceID)
+		return nil
+	})
+
+	table := []unitest.Table{
+		{
+			Name:    "history",
+			ExpResp: []float64{prd.Cost, 42.50},
+			ExcFunc: func(ctx context.Context) any {
+				cur, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Product.Update(ctx, cur, productbus.UpdateProduct{Cost: dbtest.FloatPointer(42.50)}); err != nil {
+					return err
+				}
+
+				prcs, err := busDomain.Product.QueryPrices(ctx, prd.ID, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				costs := make([]float64, len(prcs))
+				for i, prc := range prcs {
+					costs[i] = prc.Cost
+				}
+
+				return costs
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "past",
+			ExpResp: productbus.ErrInvalidPrice,
+			ExcFunc: func(ctx context.Context) any {
+				np := productbus.NewPrice{
+					Cost:          10,
+					DateEffective: time.Now().Add(-time.Hour),
+				}
+
+				_, err := busDomain.Product.SchedulePrice(ctx, prd, np)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "scheduled",
+			ExpResp: 35.0,
+			ExcFunc: func(ctx context.Context) any {
+				np := productbus.NewPrice{
+					Cost:          35,
+					DateEffective: time.Now().Add(time.Hour),
+				}
+
+				prc, err := busDomain.Product.SchedulePrice(ctx, prd, np)
+				if err != nil {
+					return err
+				}
+
+				n, err := busDomain.Product.ApplyDuePrices(ctx, bgn, time.Now())
+				if err != nil {
+					return err
+				}
+
+				if n != 0 {
+					return fmt.Errorf("expected no due prices, got %d", n)
+				}
+
+				n, err = busDomain.Product.ApplyDuePrices(ctx, bgn, prc.DateEffective)
+				if err != nil {
+					return err
+				}
+
+				if n != 1 {
+					return fmt.Errorf("expected 1 applied price, got %d", n)
+				}
+
+				// A price is only applied once.
+				n, err = busDomain.Product.ApplyDuePrices(ctx, bgn, prc.DateEffective)
+				if err != nil {
+					return err
+				}
+
+				if n != 0 {
+					return fmt.Errorf("expected no applied prices, got %d", n)
+				}
+
+				// The event of the applied price is sent once it's relayed.
+				priceIDs = nil
+
+				if _, err := busDomain.Outbox.Relay(ctx); err != nil {
+					return err
+				}
+
+				if diff := cmp.Diff(priceIDs, []uuid.UUID{prc.ID}); diff != "" {
+					return fmt.Errorf("expected a price changed event for the price:\n%s", diff)
+				}
+
+				cur, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				return cur.Cost
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func active(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "user",
+			ExpResp: []bool{false, false, true},
+			ExcFunc: func(ctx context.Context) any {
+				usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
+				if err != nil {
+					return err
+				}
+
+				prds, err := productbus.TestGenerateSeedProducts(ctx, 1, busDomain.Product, usrs[0].ID)
+				if err != nil {
+					return err
+				}
+
+				var states []bool
+
+				if _, err := busDomain.User.Update(ctx, usrs[0], userbus.UpdateUser{Enabled: dbtest.BoolPointer(false)}); err != nil {
+					return err
+				}
+
+				// The products are changed once the update is relayed from
+				// the outbox.
+				if _, err := busDomain.Outbox.Relay(ctx); err != nil {
+					return err
+				}
+
+				prd, err := busDomain.Product.QueryByID(ctx, prds[0].ID)
+				if err != nil {
+					return err
+				}
+				states = append(states, prd.Active)
+
+				filter := productbus.QueryFilter{
+					ID:     []filter.Condition[uuid.UUID]{filter.Compare(filter.Operators.EQ, prds[0].ID)},
+					Active: dbtest.BoolPointer(true),
+				}
+
+				found, err := busDomain.Product.Query(ctx, filter, fieldset.Set{}, productbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+				states = append(states, len(found) != 0)
+
+				usr, err := busDomain.User.QueryByID(ctx, usrs[0].ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.Update(ctx, usr, userbus.UpdateUser{Enabled: dbtest.BoolPointer(true)}); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Outbox.Relay(ctx); err != nil {
+					return err
+				}
+
+				prd, err = busDomain.Product.QueryByID(ctx, prds[0].ID)
+				if err != nil {
+					return err
+				}
+				states = append(states, prd.Active)
+
+				return states
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
 func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	table := []unitest.Table{
 		{
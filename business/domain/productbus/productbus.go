@@ -3,12 +3,16 @@ package productbus
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/bulk"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
@@ -21,6 +25,14 @@ var (
 	ErrNotFound     = errors.New("product not found")
 	ErrUserDisabled = errors.New("user disabled")
 	ErrInvalidCost  = errors.New("cost not valid")
+
+	ErrInvalidMovement   = errors.New("movement not valid")
+	ErrInsufficientStock = errors.New("insufficient stock")
+
+	ErrUnknownCategory = errors.New("unknown category")
+	ErrUnknownTag      = errors.New("unknown tag")
+
+	ErrInvalidPrice = errors.New("price change not valid")
 )
 
 // Storer interface declares the behavior this package needs to perists and
@@ -28,29 +40,68 @@ var (
 type Storer interface {
 	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
 	Create(ctx context.Context, prd Product) error
+	CreateMany(ctx context.Context, prds []Product) error
 	Update(ctx context.Context, prd Product) error
+	SetLowStock(ctx context.Context, prd Product) (bool, error)
 	Delete(ctx context.Context, prd Product) error
-	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Product, error)
+	Query(ctx context.Context, filter QueryFilter, fields fieldset.Set, orderBy order.By, page page.Page) ([]Product, error)
+	QueryEach(ctx context.Context, filter QueryFilter, orderBy order.By, fn func(Product) error) error
 	Count(ctx context.Context, filter QueryFilter) (int, error)
+	EstimateCount(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, productID uuid.UUID) (Product, error)
+	QueryByIDs(ctx context.Context, productIDs []uuid.UUID) ([]Product, error)
 	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Product, error)
+	SetActiveByUserID(ctx context.Context, userID uuid.UUID, active bool, now time.Time) error
+
+	// CreateMovement must append the movement to the ledger and apply its
+	// quantity to the product as a single atomic operation, returning the
+	// new quantity. It must fail with ErrInsufficientStock when the quantity
+	// would drop below zero, and with ErrNotFound when there's no product.
+	CreateMovement(ctx context.Context, mov Movement) (int, error)
+
+	// SetQuantity must set the product's quantity and append an adjustment
+	// for the difference from the current quantity to the ledger as a single
+	// atomic operation, returning the quantity of the adjustment. Nothing is
+	// recorded when the product already has the quantity.
+	SetQuantity(ctx context.Context, mov Movement, quantity int) (int, error)
+	QueryMovements(ctx context.Context, productID uuid.UUID, page page.Page) ([]Movement, error)
+	CountMovements(ctx context.Context, productID uuid.UUID) (int, error)
+	SumMovements(ctx context.Context, productID uuid.UUID) (int, error)
+
+	// SetCategories and SetTags must replace the full set of associations
+	// for the product as a single atomic operation.
+	SetCategories(ctx context.Context, productID uuid.UUID, categoryIDs []uuid.UUID) error
+	QueryCategoryIDs(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error)
+	SetTags(ctx context.Context, productID uuid.UUID, tagIDs []uuid.UUID) error
+	QueryTagIDs(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error)
+
+	// ApplyPrice must mark the price as applied and set the product's cost
+	// as a single atomic operation.
+	CreatePrice(ctx context.Context, prc Price) error
+	ApplyPrice(ctx context.Context, prc Price) (bool, error)
+	QueryPrices(ctx context.Context, productID uuid.UUID, page page.Page) ([]Price, error)
+	CountPrices(ctx context.Context, productID uuid.UUID) (int, error)
+	QueryDuePrices(ctx context.Context, now time.Time) ([]Price, error)
 }
 
 // Business manages the set of APIs for product access.
 type Business struct {
-	log      *logger.Logger
-	userBus  *userbus.Business
-	delegate *delegate.Delegate
-	storer   Storer
+	log       *logger.Logger
+	userBus   *userbus.Business
+	delegate  *delegate.Delegate
+	outboxBus *outboxbus.Business
+	storer    Storer
 }
 
-// NewBusiness constructs a product business API for use.
-func NewBusiness(log *logger.Logger, userBus *userbus.Business, delegate *delegate.Delegate, storer Storer) *Business {
+// NewBusiness constructs a product business API for use. The changes made to
+// products are sent to other domains through the outbox.
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, delegate *delegate.Delegate, outboxBus *outboxbus.Business, storer Storer) *Business {
 	b := Business{
-		log:      log,
-		userBus:  userBus,
-		delegate: delegate,
-		storer:   storer,
+		log:       log,
+		userBus:   userBus,
+		delegate:  delegate,
+		outboxBus: outboxBus,
+		storer:    storer,
 	}
 
 	b.registerDelegateFunctions()
@@ -59,7 +110,8 @@ func NewBusiness(log *logger.Logger, userBus *userbus.Business, delegate *delega
 }
 
 // NewWithTx constructs a new business value that will use the
-// specified transaction in any store related calls.
+// specified transaction in any store related calls. The events for the
+// changes made are written in the same transaction.
 func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 	storer, err := b.storer.NewWithTx(tx)
 	if err != nil {
@@ -71,11 +123,20 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 		return nil, err
 	}
 
+	outboxBus := b.outboxBus
+	if outboxBus != nil {
+		outboxBus, err = b.outboxBus.NewWithTx(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	bus := Business{
-		log:      b.log,
-		userBus:  userBus,
-		delegate: b.delegate,
-		storer:   storer,
+		log:       b.log,
+		userBus:   userBus,
+		delegate:  b.delegate,
+		outboxBus: outboxBus,
+		storer:    storer,
 	}
 
 	return &bus, nil
@@ -99,34 +160,138 @@ func (b *Business) Create(ctx context.Context, np NewProduct) (Product, error) {
 	now := time.Now()
 
 	prd := Product{
-		ID:          uuid.New(),
-		Name:        np.Name,
-		Cost:        np.Cost,
-		Quantity:    np.Quantity,
-		UserID:      np.UserID,
-		DateCreated: now,
-		DateUpdated: now,
+		ID:           uuid.New(),
+		Name:         np.Name,
+		Cost:         np.Cost,
+		ReorderLevel: np.ReorderLevel,
+		Active:       true,
+		UserID:       np.UserID,
+		DateCreated:  now,
+		DateUpdated:  now,
 	}
 
 	if err := b.storer.Create(ctx, prd); err != nil {
 		return Product{}, fmt.Errorf("create: %w", err)
 	}
 
+	if err := b.stock(ctx, &prd, np.Quantity, now); err != nil {
+		return Product{}, err
+	}
+
+	if err := b.outboxBus.Add(ctx, ActionChangedData(ActionCreated, prd)); err != nil {
+		return Product{}, fmt.Errorf("outbox: %w", err)
+	}
+
 	return prd, nil
 }
 
+// CreateMany adds the products to the system with a single insert. Every
+// product is checked before any is added, and when some fail the checks a
+// bulk.Errors is returned with the error of each of them.
+func (b *Business) CreateMany(ctx context.Context, nps []NewProduct) ([]Product, error) {
+	usrs := make(map[uuid.UUID]userbus.User)
+	var itemErrs bulk.Errors
+
+	for i, np := range nps {
+		usr, exists := usrs[np.UserID]
+		if !exists {
+			var err error
+			usr, err = b.userBus.QueryByID(ctx, np.UserID)
+			if err != nil {
+				if !errors.Is(err, userbus.ErrNotFound) {
+					return nil, fmt.Errorf("user.querybyid: %s: %w", np.UserID, err)
+				}
+				itemErrs.Add(i, err)
+				continue
+			}
+			usrs[np.UserID] = usr
+		}
+
+		switch {
+		case np.Cost < 0:
+			itemErrs.Add(i, ErrInvalidCost)
+		case !usr.Enabled:
+			itemErrs.Add(i, ErrUserDisabled)
+		}
+	}
+
+	if len(itemErrs) > 0 {
+		return nil, itemErrs
+	}
+
+	now := time.Now()
+
+	prds := make([]Product, len(nps))
+	for i, np := range nps {
+		prds[i] = Product{
+			ID:           uuid.New(),
+			Name:         np.Name,
+			Cost:         np.Cost,
+			ReorderLevel: np.ReorderLevel,
+			Active:       true,
+			UserID:       np.UserID,
+			DateCreated:  now,
+			DateUpdated:  now,
+		}
+	}
+
+	if err := b.storer.CreateMany(ctx, prds); err != nil {
+		return nil, fmt.Errorf("createmany: %w", err)
+	}
+
+	for i := range prds {
+		if err := b.stock(ctx, &prds[i], nps[i].Quantity, now); err != nil {
+			return nil, fmt.Errorf("item[%d]: %w", i, err)
+		}
+
+		if err := b.outboxBus.Add(ctx, ActionChangedData(ActionCreated, prds[i])); err != nil {
+			return nil, fmt.Errorf("item[%d]: outbox: %w", i, err)
+		}
+	}
+
+	return prds, nil
+}
+
+// stock records the first price of a new product and its initial quantity.
+// The product starts with no stock, so the quantity is recorded as a
+// receipt and the ledger accounts for every unit on hand.
+func (b *Business) stock(ctx context.Context, prd *Product, quantity int, now time.Time) error {
+	if err := b.addPrice(ctx, *prd, now); err != nil {
+		return fmt.Errorf("addprice: %w", err)
+	}
+
+	if quantity != 0 {
+		nm := NewMovement{
+			Type:     MovementTypes.Receipt,
+			Quantity: quantity,
+			Reason:   "initial stock",
+		}
+
+		if _, err := b.addMovement(ctx, prd, nm, now); err != nil {
+			return fmt.Errorf("addmovement: %w", err)
+		}
+	}
+
+	if err := b.updateLowStock(ctx, prd); err != nil {
+		return fmt.Errorf("updatelowstock: %w", err)
+	}
+
+	return nil
+}
+
 // Update modifies information about a product.
 func (b *Business) Update(ctx context.Context, prd Product, up UpdateProduct) (Product, error) {
 	if up.Name != nil {
 		prd.Name = *up.Name
 	}
 
+	oldCost := prd.Cost
 	if up.Cost != nil {
 		prd.Cost = *up.Cost
 	}
 
-	if up.Quantity != nil {
-		prd.Quantity = *up.Quantity
+	if up.ReorderLevel != nil {
+		prd.ReorderLevel = *up.ReorderLevel
 	}
 
 	prd.DateUpdated = time.Now()
@@ -135,6 +300,40 @@ func (b *Business) Update(ctx context.Context, prd Product, up UpdateProduct) (P
 		return Product{}, fmt.Errorf("update: %w", err)
 	}
 
+	// A change to the cost is kept in the price history.
+	if prd.Cost != oldCost {
+		if err := b.addPrice(ctx, prd, prd.DateUpdated); err != nil {
+			return Product{}, fmt.Errorf("addprice: %w", err)
+		}
+	}
+
+	// A change to the quantity is recorded in the ledger as an adjustment
+	// for the difference. The difference is taken from the quantity in the
+	// database rather than the one on the product value, which may be stale.
+	if up.Quantity != nil {
+		mov := Movement{
+			ID:          uuid.New(),
+			ProductID:   prd.ID,
+			Type:        MovementTypes.Adjustment,
+			Reason:      "product update",
+			DateCreated: prd.DateUpdated,
+		}
+
+		if _, err := b.storer.SetQuantity(ctx, mov, *up.Quantity); err != nil {
+			return Product{}, fmt.Errorf("setquantity: %w", err)
+		}
+
+		prd.Quantity = *up.Quantity
+	}
+
+	if err := b.updateLowStock(ctx, &prd); err != nil {
+		return Product{}, fmt.Errorf("updatelowstock: %w", err)
+	}
+
+	if err := b.outboxBus.Add(ctx, ActionChangedData(ActionUpdated, prd)); err != nil {
+		return Product{}, fmt.Errorf("outbox: %w", err)
+	}
+
 	return prd, nil
 }
 
@@ -144,12 +343,20 @@ func (b *Business) Delete(ctx context.Context, prd Product) error {
 		return fmt.Errorf("delete: %w", err)
 	}
 
+	if err := b.outboxBus.Add(ctx, ActionChangedData(ActionDeleted, prd)); err != nil {
+		return fmt.Errorf("outbox: %w", err)
+	}
+
 	return nil
 }
 
-// Query retrieves a list of existing products.
-func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Product, error) {
-	prds, err := b.storer.Query(ctx, filter, orderBy, page)
+// Query retrieves a list of existing products with the fields in the set.
+// The id and the fields the products are ordered by are always returned so
+// the results can be paged.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, fields fieldset.Set, orderBy order.By, page page.Page) ([]Product, error) {
+	fields = withOrderFields(fields.With(FieldID), orderBy)
+
+	prds, err := b.storer.Query(ctx, filter, fields, orderBy, page)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -157,11 +364,27 @@ func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.
 	return prds, nil
 }
 
+// QueryEach finds every product matching the filter and passes each to fn
+// as it's read, so they can be processed without holding them in memory.
+func (b *Business) QueryEach(ctx context.Context, filter QueryFilter, orderBy order.By, fn func(Product) error) error {
+	if err := b.storer.QueryEach(ctx, filter, orderBy, fn); err != nil {
+		return fmt.Errorf("queryeach: %w", err)
+	}
+
+	return nil
+}
+
 // Count returns the total number of products.
 func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return b.storer.Count(ctx, filter)
 }
 
+// EstimateCount returns an estimate of the total number of products, which is
+// far cheaper than counting them.
+func (b *Business) EstimateCount(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.EstimateCount(ctx, filter)
+}
+
 // QueryByID finds the product by the specified Ib.
 func (b *Business) QueryByID(ctx context.Context, productID uuid.UUID) (Product, error) {
 	prd, err := b.storer.QueryByID(ctx, productID)
@@ -172,6 +395,17 @@ func (b *Business) QueryByID(ctx context.Context, productID uuid.UUID) (Product,
 	return prd, nil
 }
 
+// QueryByIDs finds the products with the specified IDs. IDs that don't
+// match a product are left out of the result.
+func (b *Business) QueryByIDs(ctx context.Context, productIDs []uuid.UUID) ([]Product, error) {
+	prds, err := b.storer.QueryByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query: productIDs[%s]: %w", productIDs, err)
+	}
+
+	return prds, nil
+}
+
 // QueryByUserID finds the products by a specified User Ib.
 func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Product, error) {
 	prds, err := b.storer.QueryByUserID(ctx, userID)
@@ -181,3 +415,315 @@ func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Produ
 
 	return prds, nil
 }
+
+// =============================================================================
+
+// CreateMovement records a stock movement against the specified product and
+// returns the product with its new quantity.
+func (b *Business) CreateMovement(ctx context.Context, prd Product, nm NewMovement) (Product, Movement, error) {
+	mov, err := b.addMovement(ctx, &prd, nm, time.Now())
+	if err != nil {
+		return Product{}, Movement{}, err
+	}
+
+	if err := b.updateLowStock(ctx, &prd); err != nil {
+		return Product{}, Movement{}, fmt.Errorf("updatelowstock: %w", err)
+	}
+
+	if err := b.outboxBus.Add(ctx, ActionChangedData(ActionUpdated, prd)); err != nil {
+		return Product{}, Movement{}, fmt.Errorf("outbox: %w", err)
+	}
+
+	return prd, mov, nil
+}
+
+// QueryMovements retrieves the stock ledger for the specified product.
+func (b *Business) QueryMovements(ctx context.Context, productID uuid.UUID, page page.Page) ([]Movement, error) {
+	movs, err := b.storer.QueryMovements(ctx, productID, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: productID[%s]: %w", productID, err)
+	}
+
+	return movs, nil
+}
+
+// CountMovements returns the total number of movements for the specified
+// product.
+func (b *Business) CountMovements(ctx context.Context, productID uuid.UUID) (int, error) {
+	return b.storer.CountMovements(ctx, productID)
+}
+
+// Reconcile recomputes the quantity of the specified product from its stock
+// ledger and compares it to the quantity recorded on the product.
+func (b *Business) Reconcile(ctx context.Context, prd Product) (Reconciliation, error) {
+	sum, err := b.storer.SumMovements(ctx, prd.ID)
+	if err != nil {
+		return Reconciliation{}, fmt.Errorf("summovements: productID[%s]: %w", prd.ID, err)
+	}
+
+	rec := Reconciliation{
+		ProductID:      prd.ID,
+		Quantity:       prd.Quantity,
+		LedgerQuantity: sum,
+		Balanced:       prd.Quantity == sum,
+	}
+
+	return rec, nil
+}
+
+// SetCategories replaces the categories the product belongs to.
+func (b *Business) SetCategories(ctx context.Context, prd Product, categoryIDs []uuid.UUID) error {
+	if err := b.storer.SetCategories(ctx, prd.ID, categoryIDs); err != nil {
+		return fmt.Errorf("setcategories: productID[%s]: %w", prd.ID, err)
+	}
+
+	return nil
+}
+
+// QueryCategoryIDs returns the ids of the categories the product belongs to.
+func (b *Business) QueryCategoryIDs(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := b.storer.QueryCategoryIDs(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("querycategoryids: productID[%s]: %w", productID, err)
+	}
+
+	return ids, nil
+}
+
+// SetTags replaces the tags attached to the product.
+func (b *Business) SetTags(ctx context.Context, prd Product, tagIDs []uuid.UUID) error {
+	if err := b.storer.SetTags(ctx, prd.ID, tagIDs); err != nil {
+		return fmt.Errorf("settags: productID[%s]: %w", prd.ID, err)
+	}
+
+	return nil
+}
+
+// QueryTagIDs returns the ids of the tags attached to the product.
+func (b *Business) QueryTagIDs(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := b.storer.QueryTagIDs(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("querytagids: productID[%s]: %w", productID, err)
+	}
+
+	return ids, nil
+}
+
+// SchedulePrice records a price change for the product that will be applied
+// at its effective date by ApplyDuePrices.
+func (b *Business) SchedulePrice(ctx context.Context, prd Product, np NewPrice) (Price, error) {
+	now := time.Now()
+
+	if np.Cost < 0 {
+		return Price{}, fmt.Errorf("%w: cost must not be negative", ErrInvalidPrice)
+	}
+
+	if !np.DateEffective.After(now) {
+		return Price{}, fmt.Errorf("%w: effective date must be in the future", ErrInvalidPrice)
+	}
+
+	prc := Price{
+		ID:            uuid.New(),
+		ProductID:     prd.ID,
+		Cost:          np.Cost,
+		DateEffective: np.DateEffective,
+		DateCreated:   now,
+	}
+
+	if err := b.storer.CreatePrice(ctx, prc); err != nil {
+		return Price{}, fmt.Errorf("createprice: %w", err)
+	}
+
+	return prc, nil
+}
+
+// QueryPrices retrieves the price timeline for the specified product,
+// including any scheduled changes, ordered by effective date.
+func (b *Business) QueryPrices(ctx context.Context, productID uuid.UUID, page page.Page) ([]Price, error) {
+	prcs, err := b.storer.QueryPrices(ctx, productID, page)
+	if err != nil {
+		return nil, fmt.Errorf("queryprices: productID[%s]: %w", productID, err)
+	}
+
+	return prcs, nil
+}
+
+// CountPrices returns the total number of entries in the price timeline for
+// the specified product.
+func (b *Business) CountPrices(ctx context.Context, productID uuid.UUID) (int, error) {
+	return b.storer.CountPrices(ctx, productID)
+}
+
+// ApplyDuePrices applies every scheduled price change whose effective date
+// has been reached and writes a price changed event to the outbox for each
+// one. Every price is applied in its own transaction along with its event, so
+// the event is only sent for a price that was applied. It returns the number
+// of price changes applied.
+func (b *Business) ApplyDuePrices(ctx context.Context, bgn sqldb.Beginner, now time.Time) (int, error) {
+	prcs, err := b.storer.QueryDuePrices(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("querydueprices: %w", err)
+	}
+
+	var n int
+	for _, prc := range prcs {
+		applied, err := b.applyPrice(ctx, bgn, prc, now)
+		if err != nil {
+			return n, fmt.Errorf("applyprice: priceID[%s]: %w", prc.ID, err)
+		}
+
+		if applied {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// applyPrice applies the scheduled price and writes its price changed event
+// inside a transaction. The event is only written when this call applied the
+// price.
+func (b *Business) applyPrice(ctx context.Context, bgn sqldb.Beginner, prc Price, now time.Time) (bool, error) {
+	tx, err := bgn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("begin: %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			b.log.Error(ctx, "apply price", "msg", "rollback failed", "ERROR", err)
+		}
+	}()
+
+	bus, err := b.NewWithTx(tx)
+	if err != nil {
+		return false, fmt.Errorf("newwithtx: %w", err)
+	}
+
+	prd, err := bus.storer.QueryByID(ctx, prc.ProductID)
+	if err != nil {
+		return false, fmt.Errorf("querybyid: productID[%s]: %w", prc.ProductID, err)
+	}
+
+	prc.DateApplied = now
+
+	applied, err := bus.storer.ApplyPrice(ctx, prc)
+	if err != nil {
+		return false, fmt.Errorf("applyprice: %w", err)
+	}
+
+	if !applied {
+		return false, nil
+	}
+
+	if err := bus.outboxBus.Add(ctx, ActionPriceChangedData(prd, prc)); err != nil {
+		return false, fmt.Errorf("outbox: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+
+	return true, nil
+}
+
+// addPrice records the current cost of the product in its price history.
+func (b *Business) addPrice(ctx context.Context, prd Product, now time.Time) error {
+	prc := Price{
+		ID:            uuid.New(),
+		ProductID:     prd.ID,
+		Cost:          prd.Cost,
+		DateEffective: now,
+		DateApplied:   now,
+		DateCreated:   now,
+	}
+
+	return b.storer.CreatePrice(ctx, prc)
+}
+
+// addMovement validates the movement, writes it to the ledger and sets the
+// quantity of the product value to the one in the database. The stock is
+// checked by the store against the current quantity rather than the one on
+// the product value, which may be stale.
+func (b *Business) addMovement(ctx context.Context, prd *Product, nm NewMovement, now time.Time) (Movement, error) {
+	quantity, err := movementQuantity(nm)
+	if err != nil {
+		return Movement{}, err
+	}
+
+	mov := Movement{
+		ID:          uuid.New(),
+		ProductID:   prd.ID,
+		Type:        nm.Type,
+		Quantity:    quantity,
+		Reason:      nm.Reason,
+		DateCreated: now,
+	}
+
+	qty, err := b.storer.CreateMovement(ctx, mov)
+	if err != nil {
+		return Movement{}, fmt.Errorf("createmovement: %w", err)
+	}
+
+	prd.Quantity = qty
+	prd.DateUpdated = now
+
+	return mov, nil
+}
+
+// updateLowStock compares the product's quantity against its reorder level.
+// A low stock event is written to the outbox only when the product first
+// drops to its reorder level. The alerted state is stored with the product
+// and only the call that changed it writes the event, so it is not raised
+// again until the stock has been replenished. The event is written in the
+// same transaction as the stock change, so it's only sent if that change is
+// committed.
+func (b *Business) updateLowStock(ctx context.Context, prd *Product) error {
+	low := prd.ReorderLevel > 0 && prd.Quantity <= prd.ReorderLevel
+	if low == prd.LowStockAlerted {
+		return nil
+	}
+
+	prd.LowStockAlerted = low
+
+	changed, err := b.storer.SetLowStock(ctx, *prd)
+	if err != nil {
+		return fmt.Errorf("setlowstock: %w", err)
+	}
+
+	if !changed || !low {
+		return nil
+	}
+
+	if err := b.outboxBus.Add(ctx, ActionLowStockData(*prd)); err != nil {
+		return fmt.Errorf("outbox: %w", err)
+	}
+
+	return nil
+}
+
+// movementQuantity returns the signed quantity the movement applies to the
+// product's stock.
+func movementQuantity(nm NewMovement) (int, error) {
+	switch nm.Type {
+	case MovementTypes.Receipt, MovementTypes.Return:
+		if nm.Quantity <= 0 {
+			return 0, fmt.Errorf("%w: %s quantity must be positive", ErrInvalidMovement, nm.Type)
+		}
+		return nm.Quantity, nil
+
+	case MovementTypes.Sale:
+		if nm.Quantity <= 0 {
+			return 0, fmt.Errorf("%w: %s quantity must be positive", ErrInvalidMovement, nm.Type)
+		}
+		return -nm.Quantity, nil
+
+	case MovementTypes.Adjustment:
+		if nm.Quantity == 0 {
+			return 0, fmt.Errorf("%w: %s quantity must not be zero", ErrInvalidMovement, nm.Type)
+		}
+		return nm.Quantity, nil
+	}
+
+	return 0, fmt.Errorf("%w: unknown type %q", ErrInvalidMovement, nm.Type)
+}
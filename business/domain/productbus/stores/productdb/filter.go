@@ -1,38 +1,63 @@
 package productdb
 
 import (
-	"bytes"
-	"fmt"
-	"strings"
-
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
-func (s *Store) applyFilter(filter productbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
-	var wc []string
-
-	if filter.ID != nil {
-		data["product_id"] = *filter.ID
-		wc = append(wc, "product_id = :product_id")
+// escapedName is the product name escaped for HTML, so the only markup in a
+// highlighted snippet is the markup ts_headline adds around the matches.
+const escapedName = `replace(replace(replace(replace(replace(name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+
+// applySearch adds the highlighted snippet and the relevance rank to the
+// selected columns. Without a search every product has the same rank.
+func (s *Store) applySearch(filter productbus.QueryFilter, qb *sqldb.Builder) {
+	if filter.Search == nil {
+		qb.Column("'' AS snippet")
+		qb.Column("0 AS rank")
+		return
 	}
 
+	qb.Bind("search", *filter.Search)
+	qb.Column("ts_headline('english', " + escapedName + ", websearch_to_tsquery('english', :search)) AS snippet")
+	qb.Column("ts_rank(search, websearch_to_tsquery('english', :search)) AS rank")
+}
+
+func (s *Store) applyFilter(filter productbus.QueryFilter, qb *sqldb.Builder) {
+	sqldb.Conditions(qb, "product_id", filter.ID)
+
 	if filter.Name != nil {
-		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "name LIKE :name")
+		qb.Contains("name", filter.Name.String())
+	}
+
+	sqldb.Conditions(qb, "cost", filter.Cost)
+	sqldb.Conditions(qb, "quantity", filter.Quantity)
+
+	if filter.Active != nil {
+		qb.Equal("active", *filter.Active)
 	}
 
-	if filter.Cost != nil {
-		data["cost"] = *filter.Cost
-		wc = append(wc, "cost = :cost")
+	if filter.CategoryID != nil {
+		qb.Bind("category_id", *filter.CategoryID)
+		qb.Where(`EXISTS (
+		WITH RECURSIVE tree AS (
+			SELECT category_id FROM categories WHERE category_id = :category_id
+			UNION
+			SELECT c.category_id FROM categories AS c JOIN tree AS t ON c.parent_id = t.category_id
+		)
+		SELECT 1 FROM product_categories AS pc JOIN tree AS t ON t.category_id = pc.category_id
+		WHERE pc.product_id = products.product_id)`)
 	}
 
-	if filter.Quantity != nil {
-		data["quantity"] = *filter.Quantity
-		wc = append(wc, "quantity = :quantity")
+	if filter.Tag != nil {
+		qb.Bind("tag", *filter.Tag)
+		qb.Where(`EXISTS (
+		SELECT 1 FROM product_tags AS pt JOIN tags AS t ON t.tag_id = pt.tag_id
+		WHERE pt.product_id = products.product_id AND t.name = :tag)`)
 	}
 
-	if len(wc) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(wc, " AND "))
+	if filter.Search != nil {
+		qb.Bind("search", *filter.Search)
+		qb.Where("search @@ websearch_to_tsquery('english', :search)")
 	}
 }
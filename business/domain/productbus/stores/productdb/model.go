@@ -1,62 +1,152 @@
 package productdb
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/google/uuid"
 )
 
 type product struct {
-	ID          uuid.UUID `db:"product_id"`
-	UserID      uuid.UUID `db:"user_id"`
-	Name        string    `db:"name"`
-	Cost        float64   `db:"cost"`
-	Quantity    int       `db:"quantity"`
-	DateCreated time.Time `db:"date_created"`
-	DateUpdated time.Time `db:"date_updated"`
+	ID              uuid.UUID `db:"product_id"`
+	UserID          uuid.UUID `db:"user_id"`
+	Name            string    `db:"name"`
+	Cost            float64   `db:"cost"`
+	Quantity        int       `db:"quantity"`
+	ReorderLevel    int       `db:"reorder_level"`
+	LowStockAlerted bool      `db:"low_stock_alerted"`
+	Active          bool      `db:"active"`
+	DateCreated     time.Time `db:"date_created"`
+	DateUpdated     time.Time `db:"date_updated"`
+	Snippet         string    `db:"snippet"`
+	Rank            float64   `db:"rank"`
+}
+
+// projection maps the fields a query can be limited to onto their columns.
+var projection = sqldb.Projection{
+	{Field: productbus.FieldID, Columns: "product_id"},
+	{Field: productbus.FieldUserID, Columns: "user_id"},
+	{Field: productbus.FieldName, Columns: "name"},
+	{Field: productbus.FieldCost, Columns: "cost"},
+	{Field: productbus.FieldQuantity, Columns: "quantity"},
+	{Field: productbus.FieldReorderLevel, Columns: "reorder_level"},
+	{Field: productbus.FieldLowStock, Columns: "low_stock_alerted"},
+	{Field: productbus.FieldActive, Columns: "active"},
+	{Field: productbus.FieldDateCreated, Columns: "date_created"},
+	{Field: productbus.FieldDateUpdated, Columns: "date_updated"},
 }
 
 func toDBProduct(bus productbus.Product) product {
 	db := product{
+		ID:              bus.ID,
+		UserID:          bus.UserID,
+		Name:            bus.Name.String(),
+		Cost:            bus.Cost,
+		Quantity:        bus.Quantity,
+		ReorderLevel:    bus.ReorderLevel,
+		LowStockAlerted: bus.LowStockAlerted,
+		Active:          bus.Active,
+		DateCreated:     bus.DateCreated.UTC(),
+		DateUpdated:     bus.DateUpdated.UTC(),
+	}
+
+	return db
+}
+
+func toBusProduct(db product) (productbus.Product, error) {
+	// The name is empty when a query wasn't asked for it.
+	var name productbus.Name
+	if db.Name != "" {
+		var err error
+		name, err = productbus.ParseName(db.Name)
+		if err != nil {
+			return productbus.Product{}, fmt.Errorf("parse name: %w", err)
+		}
+	}
+
+	bus := productbus.Product{
+		ID:              db.ID,
+		UserID:          db.UserID,
+		Name:            name,
+		Cost:            db.Cost,
+		Quantity:        db.Quantity,
+		ReorderLevel:    db.ReorderLevel,
+		LowStockAlerted: db.LowStockAlerted,
+		Active:          db.Active,
+		Snippet:         db.Snippet,
+		Rank:            db.Rank,
+		DateCreated:     db.DateCreated.In(time.Local),
+		DateUpdated:     db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusProducts(dbs []product) ([]productbus.Product, error) {
+	bus := make([]productbus.Product, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusProduct(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type movement struct {
+	ID          uuid.UUID `db:"movement_id"`
+	ProductID   uuid.UUID `db:"product_id"`
+	Type        string    `db:"type"`
+	Quantity    int       `db:"quantity"`
+	Reason      string    `db:"reason"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBMovement(bus productbus.Movement) movement {
+	db := movement{
 		ID:          bus.ID,
-		UserID:      bus.UserID,
-		Name:        bus.Name.String(),
-		Cost:        bus.Cost,
+		ProductID:   bus.ProductID,
+		Type:        bus.Type.String(),
 		Quantity:    bus.Quantity,
+		Reason:      bus.Reason,
 		DateCreated: bus.DateCreated.UTC(),
-		DateUpdated: bus.DateUpdated.UTC(),
 	}
 
 	return db
 }
 
-func toBusProduct(db product) (productbus.Product, error) {
-	name, err := productbus.ParseName(db.Name)
+func toBusMovement(db movement) (productbus.Movement, error) {
+	typ, err := productbus.ParseMovementType(db.Type)
 	if err != nil {
-		return productbus.Product{}, fmt.Errorf("parse name: %w", err)
+		return productbus.Movement{}, fmt.Errorf("parse type: %w", err)
 	}
 
-	bus := productbus.Product{
+	bus := productbus.Movement{
 		ID:          db.ID,
-		UserID:      db.UserID,
-		Name:        name,
-		Cost:        db.Cost,
+		ProductID:   db.ProductID,
+		Type:        typ,
 		Quantity:    db.Quantity,
+		Reason:      db.Reason,
 		DateCreated: db.DateCreated.In(time.Local),
-		DateUpdated: db.DateUpdated.In(time.Local),
 	}
 
 	return bus, nil
 }
 
-func toBusProducts(dbs []product) ([]productbus.Product, error) {
-	bus := make([]productbus.Product, len(dbs))
+func toBusMovements(dbs []movement) ([]productbus.Movement, error) {
+	bus := make([]productbus.Movement, len(dbs))
 
 	for i, db := range dbs {
 		var err error
-		bus[i], err = toBusProduct(db)
+		bus[i], err = toBusMovement(db)
 		if err != nil {
 			return nil, err
 		}
@@ -64,3 +154,80 @@ func toBusProducts(dbs []product) ([]productbus.Product, error) {
 
 	return bus, nil
 }
+
+// =============================================================================
+
+type association struct {
+	ID uuid.UUID `db:"id"`
+}
+
+func toDBIDs(ids []uuid.UUID) []string {
+	db := make([]string, len(ids))
+	for i, id := range ids {
+		db[i] = id.String()
+	}
+
+	return db
+}
+
+func toBusIDs(dbs []association) []uuid.UUID {
+	bus := make([]uuid.UUID, len(dbs))
+	for i, db := range dbs {
+		bus[i] = db.ID
+	}
+
+	return bus
+}
+
+// =============================================================================
+
+type price struct {
+	ID            uuid.UUID    `db:"price_id"`
+	ProductID     uuid.UUID    `db:"product_id"`
+	Cost          float64      `db:"cost"`
+	DateEffective time.Time    `db:"date_effective"`
+	DateApplied   sql.NullTime `db:"date_applied"`
+	DateCreated   time.Time    `db:"date_created"`
+}
+
+func toDBPrice(bus productbus.Price) price {
+	db := price{
+		ID:            bus.ID,
+		ProductID:     bus.ProductID,
+		Cost:          bus.Cost,
+		DateEffective: bus.DateEffective.UTC(),
+		DateApplied: sql.NullTime{
+			Time:  bus.DateApplied.UTC(),
+			Valid: !bus.DateApplied.IsZero(),
+		},
+		DateCreated: bus.DateCreated.UTC(),
+	}
+
+	return db
+}
+
+func toBusPrice(db price) productbus.Price {
+	bus := productbus.Price{
+		ID:            db.ID,
+		ProductID:     db.ProductID,
+		Cost:          db.Cost,
+		DateEffective: db.DateEffective.In(time.Local),
+		DateCreated:   db.DateCreated.In(time.Local),
+	}
+
+	if db.DateApplied.Valid {
+		bus.DateApplied = db.DateApplied.Time.In(time.Local)
+	}
+
+	return bus
+}
+
+func toBusPrices(dbs []price) []productbus.Price {
+	bus := make([]productbus.Price, len(dbs))
+
+	for i, db := range dbs {
+		bus[i] = toBusPrice(db)
+	}
+
+	return bus
+}
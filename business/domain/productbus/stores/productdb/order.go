@@ -1,25 +1,15 @@
 package productdb
 
 import (
-	"fmt"
-
 	"github.com/ardanlabs/encore/business/domain/productbus"
-	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
-var orderByFields = map[string]string{
+var orderByFields = sqldb.Columns{
 	productbus.OrderByProductID: "product_id",
 	productbus.OrderByUserID:    "user_id",
 	productbus.OrderByName:      "name",
 	productbus.OrderByCost:      "cost",
 	productbus.OrderByQuantity:  "quantity",
-}
-
-func orderByClause(orderBy order.By) (string, error) {
-	by, exists := orderByFields[orderBy.Field]
-	if !exists {
-		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
-	}
-
-	return " ORDER BY " + by + " " + orderBy.Direction, nil
+	productbus.OrderByRank:      "rank",
 }
@@ -2,15 +2,18 @@
 package productdb
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/sqldb/dbarray"
 	"github.com/ardanlabs/encore/foundation/logger"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -51,9 +54,9 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (productbus.Storer, error)
 func (s *Store) Create(ctx context.Context, prd productbus.Product) error {
 	const q = `
 	INSERT INTO products
-		(product_id, user_id, name, cost, quantity, date_created, date_updated)
+		(product_id, user_id, name, cost, quantity, reorder_level, low_stock_alerted, active, date_created, date_updated)
 	VALUES
-		(:product_id, :user_id, :name, :cost, :quantity, :date_created, :date_updated)`
+		(:product_id, :user_id, :name, :cost, :quantity, :reorder_level, :low_stock_alerted, :active, :date_created, :date_updated)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBProduct(prd)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -62,8 +65,35 @@ func (s *Store) Create(ctx context.Context, prd productbus.Product) error {
 	return nil
 }
 
+// CreateMany inserts the products into the database with a single
+// multi-row insert.
+func (s *Store) CreateMany(ctx context.Context, prds []productbus.Product) error {
+	if len(prds) == 0 {
+		return nil
+	}
+
+	const q = `
+	INSERT INTO products
+		(product_id, user_id, name, cost, quantity, reorder_level, low_stock_alerted, active, date_created, date_updated)
+	VALUES
+		(:product_id, :user_id, :name, :cost, :quantity, :reorder_level, :low_stock_alerted, :active, :date_created, :date_updated)`
+
+	dbPrds := make([]product, len(prds))
+	for i, prd := range prds {
+		dbPrds[i] = toDBProduct(prd)
+	}
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, dbPrds); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
 // Update modifies data about a productbus. It will error if the specified ID is
-// invalid or does not reference an existing productbus.
+// invalid or does not reference an existing productbus. The quantity is not
+// written here since it can only change through a stock movement, nor is the
+// low stock alerted state which is only changed by SetLowStock.
 func (s *Store) Update(ctx context.Context, prd productbus.Product) error {
 	const q = `
 	UPDATE
@@ -71,7 +101,7 @@ func (s *Store) Update(ctx context.Context, prd productbus.Product) error {
 	SET
 		"name" = :name,
 		"cost" = :cost,
-		"quantity" = :quantity,
+		"reorder_level" = :reorder_level,
 		"date_updated" = :date_updated
 	WHERE
 		product_id = :product_id`
@@ -83,6 +113,33 @@ func (s *Store) Update(ctx context.Context, prd productbus.Product) error {
 	return nil
 }
 
+// SetLowStock stores the low stock alerted state of the product unless it's
+// already set to that value. It reports whether the state was changed.
+func (s *Store) SetLowStock(ctx context.Context, prd productbus.Product) (bool, error) {
+	const q = `
+	UPDATE
+		products
+	SET
+		"low_stock_alerted" = :low_stock_alerted
+	WHERE
+		product_id = :product_id AND
+		low_stock_alerted <> :low_stock_alerted
+	RETURNING
+		product_id`
+
+	var dbUpdated struct {
+		ID uuid.UUID `db:"product_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBProduct(prd), &dbUpdated); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return true, nil
+}
+
 // Delete removes the product identified by a given ID.
 func (s *Store) Delete(ctx context.Context, prd productbus.Product) error {
 	data := struct {
@@ -105,62 +162,85 @@ func (s *Store) Delete(ctx context.Context, prd productbus.Product) error {
 }
 
 // Query gets all Products from the database.
-func (s *Store) Query(ctx context.Context, filter productbus.QueryFilter, orderBy order.By, page page.Page) ([]productbus.Product, error) {
-	data := map[string]any{
-		"offset":        (page.Number() - 1) * page.RowsPerPage(),
-		"rows_per_page": page.RowsPerPage(),
-	}
+func (s *Store) Query(ctx context.Context, filter productbus.QueryFilter, fields fieldset.Set, orderBy order.By, page page.Page) ([]productbus.Product, error) {
+	qb := sqldb.NewBuilder("products", projection.Columns(fields))
+	s.applySearch(filter, qb)
+	s.applyFilter(filter, qb)
 
-	const q = `
-	SELECT
-	    product_id, user_id, name, cost, quantity, date_created, date_updated
-	FROM
-		products`
-
-	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
-
-	orderByClause, err := orderByClause(orderBy)
-	if err != nil {
+	if err := qb.Page(orderByFields, orderBy, "product_id", page); err != nil {
 		return nil, err
 	}
 
-	buf.WriteString(orderByClause)
-	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
-
 	var dbPrds []product
-	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbPrds); err != nil {
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, qb.String(), qb.Data(), &dbPrds); err != nil {
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
+	if page.IsBackward() {
+		slices.Reverse(dbPrds)
+	}
+
 	return toBusProducts(dbPrds)
 }
 
-// Count returns the total number of users in the DB.
-func (s *Store) Count(ctx context.Context, filter productbus.QueryFilter) (int, error) {
-	data := map[string]any{}
+// QueryEach reads every product matching the filter, passing each to fn as
+// it comes back from the database.
+func (s *Store) QueryEach(ctx context.Context, filter productbus.QueryFilter, orderBy order.By, fn func(productbus.Product) error) error {
+	qb := sqldb.NewBuilder("products", projection.Columns(fieldset.Set{}))
+	s.applySearch(filter, qb)
+	s.applyFilter(filter, qb)
 
-	const q = `
-	SELECT
-		count(1)
-	FROM
-		products`
+	if err := qb.Order(orderByFields, orderBy, "product_id"); err != nil {
+		return err
+	}
+
+	each := func(dbPrd product) error {
+		prd, err := toBusProduct(dbPrd)
+		if err != nil {
+			return err
+		}
+
+		return fn(prd)
+	}
 
-	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	if err := sqldb.NamedQueryEach(ctx, s.log, s.db, qb.String(), qb.Data(), each); err != nil {
+		return fmt.Errorf("namedqueryeach: %w", err)
+	}
+
+	return nil
+}
+
+// Count returns the total number of users in the DB.
+func (s *Store) Count(ctx context.Context, filter productbus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("products", "count(1)")
+	s.applyFilter(filter, qb)
 
 	var count struct {
 		Count   int `db:"count"`
 		Sold    int `db:"sold"`
 		Revenue int `db:"revenue"`
 	}
-	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, qb.String(), qb.Data(), &count); err != nil {
 		return 0, fmt.Errorf("db: %w", err)
 	}
 
 	return count.Count, nil
 }
 
+// EstimateCount returns the planner's estimate of the number of products in
+// the DB.
+func (s *Store) EstimateCount(ctx context.Context, filter productbus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("products", "1")
+	s.applyFilter(filter, qb)
+
+	count, err := sqldb.EstimateCount(ctx, s.log, s.db, qb.String(), qb.Data())
+	if err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count, nil
+}
+
 // QueryByID finds the product identified by a given ID.
 func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (productbus.Product, error) {
 	data := struct {
@@ -171,7 +251,7 @@ func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (productbus.
 
 	const q = `
 	SELECT
-	    product_id, user_id, name, cost, quantity, date_created, date_updated
+	    product_id, user_id, name, cost, quantity, reorder_level, low_stock_alerted, active, date_created, date_updated
 	FROM
 		products
 	WHERE
@@ -188,6 +268,39 @@ func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (productbus.
 	return toBusProduct(dbPrd)
 }
 
+// QueryByIDs gets the specified products from the database.
+func (s *Store) QueryByIDs(ctx context.Context, productIDs []uuid.UUID) ([]productbus.Product, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(productIDs))
+	for i, id := range productIDs {
+		ids[i] = id.String()
+	}
+
+	data := struct {
+		IDs []string `db:"product_ids"`
+	}{
+		IDs: ids,
+	}
+
+	const q = `
+	SELECT
+	    product_id, user_id, name, cost, quantity, reorder_level, low_stock_alerted, active, date_created, date_updated
+	FROM
+		products
+	WHERE
+		product_id IN (:product_ids)`
+
+	var dbs []product
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, q, data, &dbs); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusProducts(dbs)
+}
+
 // QueryByUserID finds the product identified by a given User ID.
 func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]productbus.Product, error) {
 	data := struct {
@@ -198,7 +311,7 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]productb
 
 	const q = `
 	SELECT
-	    product_id, user_id, name, cost, quantity, date_created, date_updated
+	    product_id, user_id, name, cost, quantity, reorder_level, low_stock_alerted, active, date_created, date_updated
 	FROM
 		products
 	WHERE
@@ -211,3 +324,491 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]productb
 
 	return toBusProducts(dbPrds)
 }
+
+// SetActiveByUserID activates or deactivates every product owned by the
+// specified user.
+func (s *Store) SetActiveByUserID(ctx context.Context, userID uuid.UUID, active bool, now time.Time) error {
+	data := struct {
+		UserID      string    `db:"user_id"`
+		Active      bool      `db:"active"`
+		DateUpdated time.Time `db:"date_updated"`
+	}{
+		UserID:      userID.String(),
+		Active:      active,
+		DateUpdated: now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		products
+	SET
+		"active"       = :active,
+		"date_updated" = :date_updated
+	WHERE
+		user_id = :user_id AND
+		active <> :active`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMovement applies the quantity of a movement to the product and
+// appends the movement to the stock ledger in a single statement. The
+// quantity is applied to the value in the database so concurrent movements
+// don't overwrite each other, and a movement that would take the quantity
+// below zero is not recorded. It returns the new quantity of the product.
+// A product that doesn't exist is told apart from one without enough stock
+// by the row the statement reads for it.
+func (s *Store) CreateMovement(ctx context.Context, mov productbus.Movement) (int, error) {
+	const q = `
+	WITH updated AS (
+		UPDATE
+			products
+		SET
+			"quantity" = quantity + :quantity,
+			"date_updated" = :date_created
+		WHERE
+			product_id = :product_id AND
+			quantity + :quantity >= 0
+		RETURNING
+			product_id, quantity
+	), movement AS (
+		INSERT INTO product_movements
+			(movement_id, product_id, type, quantity, reason, date_created)
+		SELECT
+			:movement_id, product_id, :type, :quantity, :reason, :date_created
+		FROM
+			updated
+	)
+	SELECT
+		EXISTS (SELECT 1 FROM updated) AS applied,
+		COALESCE((SELECT quantity FROM updated), 0) AS quantity
+	FROM
+		products
+	WHERE
+		product_id = :product_id`
+
+	var dbQty struct {
+		Applied  bool `db:"applied"`
+		Quantity int  `db:"quantity"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBMovement(mov), &dbQty); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return 0, fmt.Errorf("db: %w", productbus.ErrNotFound)
+		}
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	if !dbQty.Applied {
+		return 0, fmt.Errorf("db: %w", productbus.ErrInsufficientStock)
+	}
+
+	return dbQty.Quantity, nil
+}
+
+// SetQuantity sets the quantity of the product and appends an adjustment for
+// the difference to the stock ledger in a single statement. The product row
+// is locked while the difference is computed, so a concurrent movement is
+// either applied before it and accounted for, or waits for it. Nothing is
+// recorded when the product already has the quantity. It returns the
+// quantity of the adjustment.
+func (s *Store) SetQuantity(ctx context.Context, mov productbus.Movement, quantity int) (int, error) {
+	dbMov := toDBMovement(mov)
+
+	data := map[string]any{
+		"movement_id":  dbMov.ID,
+		"product_id":   dbMov.ProductID,
+		"type":         dbMov.Type,
+		"reason":       dbMov.Reason,
+		"date_created": dbMov.DateCreated,
+		"target":       quantity,
+	}
+
+	const q = `
+	WITH current AS (
+		SELECT
+			product_id, quantity
+		FROM
+			products
+		WHERE
+			product_id = :product_id
+		FOR UPDATE
+	), updated AS (
+		UPDATE
+			products p
+		SET
+			"quantity" = :target,
+			"date_updated" = :date_created
+		FROM
+			current c
+		WHERE
+			p.product_id = c.product_id AND
+			c.quantity <> :target
+		RETURNING
+			p.product_id, :target - c.quantity AS quantity
+	), movement AS (
+		INSERT INTO product_movements
+			(movement_id, product_id, type, quantity, reason, date_created)
+		SELECT
+			:movement_id, product_id, :type, quantity, :reason, :date_created
+		FROM
+			updated
+	)
+	SELECT
+		COALESCE((SELECT quantity FROM updated), 0) AS quantity
+	FROM
+		current`
+
+	var dbQty struct {
+		Quantity int `db:"quantity"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbQty); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return 0, fmt.Errorf("db: %w", productbus.ErrNotFound)
+		}
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return dbQty.Quantity, nil
+}
+
+// QueryMovements gets the stock ledger for the specified product in the
+// order the movements were recorded.
+func (s *Store) QueryMovements(ctx context.Context, productID uuid.UUID, page page.Page) ([]productbus.Movement, error) {
+	data := map[string]any{
+		"product_id":    productID.String(),
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		movement_id, product_id, type, quantity, reason, date_created
+	FROM
+		product_movements
+	WHERE
+		product_id = :product_id
+	ORDER BY
+		date_created, movement_id
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var dbMovs []movement
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbMovs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusMovements(dbMovs)
+}
+
+// CountMovements returns the total number of movements for the specified
+// product.
+func (s *Store) CountMovements(ctx context.Context, productID uuid.UUID) (int, error) {
+	data := struct {
+		ID string `db:"product_id"`
+	}{
+		ID: productID.String(),
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		product_movements
+	WHERE
+		product_id = :product_id`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// SumMovements returns the quantity computed from the stock ledger for the
+// specified product.
+func (s *Store) SumMovements(ctx context.Context, productID uuid.UUID) (int, error) {
+	data := struct {
+		ID string `db:"product_id"`
+	}{
+		ID: productID.String(),
+	}
+
+	const q = `
+	SELECT
+		COALESCE(SUM(quantity), 0) AS quantity
+	FROM
+		product_movements
+	WHERE
+		product_id = :product_id`
+
+	var sum struct {
+		Quantity int `db:"quantity"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &sum); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return sum.Quantity, nil
+}
+
+// SetCategories replaces the categories associated with the product in a
+// single statement.
+func (s *Store) SetCategories(ctx context.Context, productID uuid.UUID, categoryIDs []uuid.UUID) error {
+	data := map[string]any{
+		"product_id":   productID.String(),
+		"category_ids": dbarray.Array(toDBIDs(categoryIDs)),
+	}
+
+	const q = `
+	WITH removed AS (
+		DELETE FROM
+			product_categories
+		WHERE
+			product_id = :product_id AND
+			NOT (category_id = ANY(CAST(:category_ids AS UUID[])))
+	)
+	INSERT INTO product_categories
+		(product_id, category_id)
+	SELECT
+		:product_id, UNNEST(CAST(:category_ids AS UUID[]))
+	ON CONFLICT DO NOTHING`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		if errors.Is(err, sqldb.ErrDBForeignKey) {
+			return fmt.Errorf("namedexeccontext: %w", productbus.ErrUnknownCategory)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryCategoryIDs gets the ids of the categories associated with the
+// product.
+func (s *Store) QueryCategoryIDs(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	data := struct {
+		ID string `db:"product_id"`
+	}{
+		ID: productID.String(),
+	}
+
+	const q = `
+	SELECT
+		category_id AS id
+	FROM
+		product_categories
+	WHERE
+		product_id = :product_id
+	ORDER BY
+		category_id`
+
+	var dbIDs []association
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbIDs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusIDs(dbIDs), nil
+}
+
+// SetTags replaces the tags associated with the product in a single
+// statement.
+func (s *Store) SetTags(ctx context.Context, productID uuid.UUID, tagIDs []uuid.UUID) error {
+	data := map[string]any{
+		"product_id": productID.String(),
+		"tag_ids":    dbarray.Array(toDBIDs(tagIDs)),
+	}
+
+	const q = `
+	WITH removed AS (
+		DELETE FROM
+			product_tags
+		WHERE
+			product_id = :product_id AND
+			NOT (tag_id = ANY(CAST(:tag_ids AS UUID[])))
+	)
+	INSERT INTO product_tags
+		(product_id, tag_id)
+	SELECT
+		:product_id, UNNEST(CAST(:tag_ids AS UUID[]))
+	ON CONFLICT DO NOTHING`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		if errors.Is(err, sqldb.ErrDBForeignKey) {
+			return fmt.Errorf("namedexeccontext: %w", productbus.ErrUnknownTag)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryTagIDs gets the ids of the tags associated with the product.
+func (s *Store) QueryTagIDs(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	data := struct {
+		ID string `db:"product_id"`
+	}{
+		ID: productID.String(),
+	}
+
+	const q = `
+	SELECT
+		tag_id AS id
+	FROM
+		product_tags
+	WHERE
+		product_id = :product_id
+	ORDER BY
+		tag_id`
+
+	var dbIDs []association
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbIDs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusIDs(dbIDs), nil
+}
+
+// CreatePrice inserts a new entry into the price timeline of a product.
+func (s *Store) CreatePrice(ctx context.Context, prc productbus.Price) error {
+	const q = `
+	INSERT INTO product_prices
+		(price_id, product_id, cost, date_effective, date_applied, date_created)
+	VALUES
+		(:price_id, :product_id, :cost, :date_effective, :date_applied, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBPrice(prc)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyPrice marks a scheduled price as applied and sets the product's cost
+// in a single statement. A price that was already applied is left alone. It
+// reports whether the price was applied.
+func (s *Store) ApplyPrice(ctx context.Context, prc productbus.Price) (bool, error) {
+	const q = `
+	WITH applied AS (
+		UPDATE
+			product_prices
+		SET
+			"date_applied" = :date_applied
+		WHERE
+			price_id = :price_id AND
+			date_applied IS NULL
+		RETURNING
+			product_id, cost
+	)
+	UPDATE
+		products AS p
+	SET
+		"cost"         = a.cost,
+		"date_updated" = :date_applied
+	FROM
+		applied AS a
+	WHERE
+		p.product_id = a.product_id
+	RETURNING
+		p.product_id`
+
+	var dbApplied struct {
+		ID uuid.UUID `db:"product_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBPrice(prc), &dbApplied); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return true, nil
+}
+
+// QueryPrices gets the price timeline for the specified product ordered by
+// effective date.
+func (s *Store) QueryPrices(ctx context.Context, productID uuid.UUID, page page.Page) ([]productbus.Price, error) {
+	data := map[string]any{
+		"product_id":    productID.String(),
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		price_id, product_id, cost, date_effective, date_applied, date_created
+	FROM
+		product_prices
+	WHERE
+		product_id = :product_id
+	ORDER BY
+		date_effective, date_created
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var dbPrcs []price
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPrcs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusPrices(dbPrcs), nil
+}
+
+// CountPrices returns the number of entries in the price timeline for the
+// specified product.
+func (s *Store) CountPrices(ctx context.Context, productID uuid.UUID) (int, error) {
+	data := struct {
+		ID string `db:"product_id"`
+	}{
+		ID: productID.String(),
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		product_prices
+	WHERE
+		product_id = :product_id`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryDuePrices gets the scheduled prices whose effective date has been
+// reached, oldest first.
+func (s *Store) QueryDuePrices(ctx context.Context, now time.Time) ([]productbus.Price, error) {
+	data := struct {
+		Now time.Time `db:"now"`
+	}{
+		Now: now.UTC(),
+	}
+
+	const q = `
+	SELECT
+		price_id, product_id, cost, date_effective, date_applied, date_created
+	FROM
+		product_prices
+	WHERE
+		date_applied IS NULL AND
+		date_effective <= :now
+	ORDER BY
+		date_effective, date_created`
+
+	var dbPrcs []price
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPrcs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusPrices(dbPrcs), nil
+}
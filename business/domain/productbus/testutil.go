@@ -19,7 +19,7 @@ func TestGenerateNewProducts(n int, userID uuid.UUID) []NewProduct {
 		np := NewProduct{
 			Name:     MustParseName(fmt.Sprintf("Name%d", idx)),
 			Cost:     float64(rand.Intn(500)),
-			Quantity: rand.Intn(50),
+			Quantity: rand.Intn(49) + 1,
 			UserID:   userID,
 		}
 
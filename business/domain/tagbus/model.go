@@ -0,0 +1,26 @@
+package tagbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag represents a free-form label that can be attached to products.
+type Tag struct {
+	ID          uuid.UUID
+	Name        string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewTag is what we require from clients when adding a Tag.
+type NewTag struct {
+	Name string
+}
+
+// UpdateTag defines what information may be provided to modify an existing
+// Tag.
+type UpdateTag struct {
+	Name *string
+}
@@ -0,0 +1,12 @@
+package tagbus
+
+import "github.com/ardanlabs/encore/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByName, order.ASC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID   = "tag_id"
+	OrderByName = "name"
+)
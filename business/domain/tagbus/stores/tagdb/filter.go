@@ -0,0 +1,16 @@
+package tagdb
+
+import (
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+func (s *Store) applyFilter(filter tagbus.QueryFilter, qb *sqldb.Builder) {
+	if filter.ID != nil {
+		qb.Equal("tag_id", *filter.ID)
+	}
+
+	if filter.Name != nil {
+		qb.Contains("name", *filter.Name)
+	}
+}
@@ -0,0 +1,47 @@
+package tagdb
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+	"github.com/google/uuid"
+)
+
+type tag struct {
+	ID          uuid.UUID `db:"tag_id"`
+	Name        string    `db:"name"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBTag(bus tagbus.Tag) tag {
+	db := tag{
+		ID:          bus.ID,
+		Name:        bus.Name,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+
+	return db
+}
+
+func toBusTag(db tag) tagbus.Tag {
+	bus := tagbus.Tag{
+		ID:          db.ID,
+		Name:        db.Name,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus
+}
+
+func toBusTags(dbs []tag) []tagbus.Tag {
+	bus := make([]tagbus.Tag, len(dbs))
+
+	for i, db := range dbs {
+		bus[i] = toBusTag(db)
+	}
+
+	return bus
+}
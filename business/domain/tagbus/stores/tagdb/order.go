@@ -0,0 +1,11 @@
+package tagdb
+
+import (
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+var orderByFields = sqldb.Columns{
+	tagbus.OrderByID:   "tag_id",
+	tagbus.OrderByName: "name",
+}
@@ -0,0 +1,184 @@
+// Package tagdb contains tag related CRUD functionality.
+package tagdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for tag database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (tagbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new tag into the database.
+func (s *Store) Create(ctx context.Context, tg tagbus.Tag) error {
+	const q = `
+	INSERT INTO tags
+		(tag_id, name, date_created, date_updated)
+	VALUES
+		(:tag_id, :name, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTag(tg)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", tagbus.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a tag document in the database.
+func (s *Store) Update(ctx context.Context, tg tagbus.Tag) error {
+	const q = `
+	UPDATE
+		tags
+	SET
+		"name"         = :name,
+		"date_updated" = :date_updated
+	WHERE
+		tag_id = :tag_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTag(tg)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", tagbus.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a tag from the database.
+func (s *Store) Delete(ctx context.Context, tg tagbus.Tag) error {
+	data := struct {
+		ID string `db:"tag_id"`
+	}{
+		ID: tg.ID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		tags
+	WHERE
+		tag_id = :tag_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing tags from the database.
+func (s *Store) Query(ctx context.Context, filter tagbus.QueryFilter, orderBy order.By, page page.Page) ([]tagbus.Tag, error) {
+	qb := sqldb.NewBuilder("tags", "tag_id, name, date_created, date_updated")
+	s.applyFilter(filter, qb)
+
+	if err := qb.Page(orderByFields, orderBy, "tag_id", page); err != nil {
+		return nil, err
+	}
+
+	var dbTags []tag
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, qb.String(), qb.Data(), &dbTags); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	if page.IsBackward() {
+		slices.Reverse(dbTags)
+	}
+
+	return toBusTags(dbTags), nil
+}
+
+// Count returns the total number of tags in the DB.
+func (s *Store) Count(ctx context.Context, filter tagbus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("tags", "count(1)")
+	s.applyFilter(filter, qb)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, qb.String(), qb.Data(), &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// EstimateCount returns the planner's estimate of the number of tags in
+// the DB.
+func (s *Store) EstimateCount(ctx context.Context, filter tagbus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("tags", "1")
+	s.applyFilter(filter, qb)
+
+	count, err := sqldb.EstimateCount(ctx, s.log, s.db, qb.String(), qb.Data())
+	if err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count, nil
+}
+
+// QueryByID gets the specified tag from the database.
+func (s *Store) QueryByID(ctx context.Context, tagID uuid.UUID) (tagbus.Tag, error) {
+	data := struct {
+		ID string `db:"tag_id"`
+	}{
+		ID: tagID.String(),
+	}
+
+	const q = `
+	SELECT
+		tag_id, name, date_created, date_updated
+	FROM
+		tags
+	WHERE
+		tag_id = :tag_id`
+
+	var dbTag tag
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbTag); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return tagbus.Tag{}, fmt.Errorf("db: %w", tagbus.ErrNotFound)
+		}
+		return tagbus.Tag{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusTag(dbTag), nil
+}
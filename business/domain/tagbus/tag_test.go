@@ -0,0 +1,252 @@
+package tagbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Tag(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, create(db.BusDomain, sd), "create")
+	unitest.Run(t, update(db.BusDomain, sd), "update")
+	unitest.Run(t, product(db.BusDomain, sd), "product")
+	unitest.Run(t, delete(db.BusDomain, sd), "delete")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	tags, err := tagbus.TestGenerateSeedTags(ctx, 2, busDomain.Tag)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding tags : %w", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.Admin, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err := productbus.TestGenerateSeedProducts(ctx, 2, busDomain.Product, usrs[0].ID)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	tu1 := unitest.User{
+		User:     usrs[0],
+		Products: prds,
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := unitest.SeedData{
+		Admins: []unitest.User{tu1},
+		Tags:   tags,
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: tagbus.Tag{Name: "clearance"},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Tag.Create(ctx, tagbus.NewTag{Name: " Clearance "})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(tagbus.Tag)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(tagbus.Tag)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "unique",
+			ExpResp: tagbus.ErrUniqueName,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Tag.Create(ctx, tagbus.NewTag{Name: sd.Tags[0].Name})
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: tagbus.Tag{
+				ID:          sd.Tags[1].ID,
+				Name:        "featured",
+				DateCreated: sd.Tags[1].DateCreated,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				ut := tagbus.UpdateTag{
+					Name: dbtest.StringPointer("Featured"),
+				}
+
+				resp, err := busDomain.Tag.Update(ctx, sd.Tags[1], ut)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(tagbus.Tag)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(tagbus.Tag)
+
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func product(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	prd := sd.Admins[0].Products[0]
+
+	table := []unitest.Table{
+		{
+			Name:    "filter",
+			ExpResp: []uuid.UUID{prd.ID},
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Product.SetTags(ctx, prd, []uuid.UUID{sd.Tags[0].ID}); err != nil {
+					return err
+				}
+
+				filter := productbus.QueryFilter{
+					Tag: &sd.Tags[0].Name,
+				}
+
+				prds, err := busDomain.Product.Query(ctx, filter, fieldset.Set{}, productbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				ids := make([]uuid.UUID, len(prds))
+				for i, prd := range prds {
+					ids[i] = prd.ID
+				}
+
+				return ids
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "replace",
+			ExpResp: []uuid.UUID{sd.Tags[1].ID},
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Product.SetTags(ctx, prd, []uuid.UUID{sd.Tags[1].ID}); err != nil {
+					return err
+				}
+
+				ids, err := busDomain.Product.QueryTagIDs(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				return ids
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: tagbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Tag.Delete(ctx, sd.Tags[0]); err != nil {
+					return err
+				}
+
+				_, err := busDomain.Tag.QueryByID(ctx, sd.Tags[0].ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,145 @@
+// Package tagbus provides business access to tag domain.
+package tagbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound   = errors.New("tag not found")
+	ErrUniqueName = errors.New("tag name is not unique")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, tag Tag) error
+	Update(ctx context.Context, tag Tag) error
+	Delete(ctx context.Context, tag Tag) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Tag, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	EstimateCount(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, tagID uuid.UUID) (Tag, error)
+}
+
+// Business manages the set of APIs for tag access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs a tag business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new tag to the system. Tag names are stored in lower case
+// so the same tag can't be added twice with different spellings.
+func (b *Business) Create(ctx context.Context, nt NewTag) (Tag, error) {
+	now := time.Now()
+
+	tag := Tag{
+		ID:          uuid.New(),
+		Name:        normalize(nt.Name),
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, tag); err != nil {
+		return Tag{}, fmt.Errorf("create: %w", err)
+	}
+
+	return tag, nil
+}
+
+// Update modifies information about a tag.
+func (b *Business) Update(ctx context.Context, tag Tag, ut UpdateTag) (Tag, error) {
+	if ut.Name != nil {
+		tag.Name = normalize(*ut.Name)
+	}
+
+	tag.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, tag); err != nil {
+		return Tag{}, fmt.Errorf("update: %w", err)
+	}
+
+	return tag, nil
+}
+
+// Delete removes the specified tag and its association with any products.
+func (b *Business) Delete(ctx context.Context, tag Tag) error {
+	if err := b.storer.Delete(ctx, tag); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing tags.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Tag, error) {
+	tags, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Count returns the total number of tags.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.Count(ctx, filter)
+}
+
+// EstimateCount returns an estimate of the total number of tags, which is
+// far cheaper than counting them.
+func (b *Business) EstimateCount(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.EstimateCount(ctx, filter)
+}
+
+// QueryByID finds the tag by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, tagID uuid.UUID) (Tag, error) {
+	tag, err := b.storer.QueryByID(ctx, tagID)
+	if err != nil {
+		return Tag{}, fmt.Errorf("query: tagID[%s]: %w", tagID, err)
+	}
+
+	return tag, nil
+}
+
+// =============================================================================
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,42 @@
+package tagbus
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+)
+
+// TestGenerateNewTags is a helper method for testing.
+func TestGenerateNewTags(n int) []NewTag {
+	newTags := make([]NewTag, n)
+
+	idx := rand.Intn(10000)
+	for i := 0; i < n; i++ {
+		idx++
+
+		nt := NewTag{
+			Name: fmt.Sprintf("tag%d", idx),
+		}
+
+		newTags[i] = nt
+	}
+
+	return newTags
+}
+
+// TestGenerateSeedTags is a helper method for testing.
+func TestGenerateSeedTags(ctx context.Context, n int, api *Business) ([]Tag, error) {
+	newTags := TestGenerateNewTags(n)
+
+	tags := make([]Tag, len(newTags))
+	for i, nt := range newTags {
+		tag, err := api.Create(ctx, nt)
+		if err != nil {
+			return nil, fmt.Errorf("seeding tag: idx: %d : %w", i, err)
+		}
+
+		tags[i] = tag
+	}
+
+	return tags, nil
+}
@@ -13,9 +13,46 @@ const DomainName = "user"
 
 // Set of delegate actions.
 const (
+	ActionCreated = "created"
 	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
 )
 
+// ActionChangedParms represents the parameters for the created and deleted
+// actions.
+type ActionChangedParms struct {
+	UserID uuid.UUID
+}
+
+// String returns a string representation of the action parameters.
+func (ac *ActionChangedParms) String() string {
+	return fmt.Sprintf("&EventParamsChanged{UserID:%v}", ac.UserID)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (ac *ActionChangedParms) Marshal() ([]byte, error) {
+	return json.Marshal(ac)
+}
+
+// ActionChangedData constructs the data for the created or deleted action
+// specified. Updates carry more and use ActionUpdatedData.
+func ActionChangedData(action string, usr User) delegate.Data {
+	params := ActionChangedParms{
+		UserID: usr.ID,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    action,
+		RawParams: rawParams,
+	}
+}
+
 // ActionUpdatedParms represents the parameters for the updated action.
 type ActionUpdatedParms struct {
 	UserID uuid.UUID
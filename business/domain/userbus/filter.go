@@ -4,13 +4,14 @@ import (
 	"net/mail"
 	"time"
 
+	"github.com/ardanlabs/encore/business/sdk/filter"
 	"github.com/google/uuid"
 )
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
 type QueryFilter struct {
-	ID               *uuid.UUID
+	ID               []filter.Condition[uuid.UUID]
 	Name             *Name
 	Email            *mail.Address
 	StartCreatedDate *time.Time
@@ -79,11 +79,21 @@ func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy o
 	return s.storer.Query(ctx, filter, orderBy, page)
 }
 
+// QueryEach reads every user matching the filter, passing each to fn.
+func (s *Store) QueryEach(ctx context.Context, filter userbus.QueryFilter, orderBy order.By, fn func(userbus.User) error) error {
+	return s.storer.QueryEach(ctx, filter, orderBy, fn)
+}
+
 // Count returns the total number of cards in the DB.
 func (s *Store) Count(ctx context.Context, filter userbus.QueryFilter) (int, error) {
 	return s.storer.Count(ctx, filter)
 }
 
+// EstimateCount returns an estimate of the number of users in the DB.
+func (s *Store) EstimateCount(ctx context.Context, filter userbus.QueryFilter) (int, error) {
+	return s.storer.EstimateCount(ctx, filter)
+}
+
 // QueryByID gets the specified user from the database.
 func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User, error) {
 	cachedUsr, ok := s.readCache(userID.String())
@@ -101,6 +111,37 @@ func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User,
 	return usr, nil
 }
 
+// QueryByIDs gets the specified users, reading the ones that are cached from
+// the cache and the rest from the database.
+func (s *Store) QueryByIDs(ctx context.Context, userIDs []uuid.UUID) ([]userbus.User, error) {
+	usrs := make([]userbus.User, 0, len(userIDs))
+	var missing []uuid.UUID
+
+	for _, userID := range userIDs {
+		cachedUsr, ok := s.readCache(userID.String())
+		if !ok {
+			missing = append(missing, userID)
+			continue
+		}
+		usrs = append(usrs, cachedUsr)
+	}
+
+	if len(missing) == 0 {
+		return usrs, nil
+	}
+
+	dbUsrs, err := s.storer.QueryByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, usr := range dbUsrs {
+		s.writeCache(usr)
+	}
+
+	return append(usrs, dbUsrs...), nil
+}
+
 // QueryByEmail gets the specified user from the database by email.
 func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.User, error) {
 	cachedUsr, ok := s.readCache(email.Address)
@@ -1,43 +1,26 @@
 package userdb
 
 import (
-	"bytes"
-	"fmt"
-	"strings"
-
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
-func applyFilter(filter userbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
-	var wc []string
-
-	if filter.ID != nil {
-		data["user_id"] = *filter.ID
-		wc = append(wc, "user_id = :user_id")
-	}
+func applyFilter(filter userbus.QueryFilter, qb *sqldb.Builder) {
+	sqldb.Conditions(qb, "user_id", filter.ID)
 
 	if filter.Name != nil {
-		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "name LIKE :name")
+		qb.Contains("name", filter.Name.String())
 	}
 
 	if filter.Email != nil {
-		data["email"] = (*filter.Email).String()
-		wc = append(wc, "email = :email")
+		qb.Equal("email", filter.Email.String())
 	}
 
 	if filter.StartCreatedDate != nil {
-		data["start_date_created"] = filter.StartCreatedDate.UTC()
-		wc = append(wc, "date_created >= :start_date_created")
+		qb.AtLeast("date_created", filter.StartCreatedDate.UTC())
 	}
 
 	if filter.EndCreatedDate != nil {
-		data["end_date_created"] = filter.EndCreatedDate.UTC()
-		wc = append(wc, "date_created <= :end_date_created")
-	}
-
-	if len(wc) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(wc, " AND "))
+		qb.AtMost("date_created", filter.EndCreatedDate.UTC())
 	}
 }
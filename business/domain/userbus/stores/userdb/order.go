@@ -1,25 +1,14 @@
 package userdb
 
 import (
-	"fmt"
-
 	"github.com/ardanlabs/encore/business/domain/userbus"
-	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
-var orderByFields = map[string]string{
+var orderByFields = sqldb.Columns{
 	userbus.OrderByID:      "user_id",
 	userbus.OrderByName:    "name",
 	userbus.OrderByEmail:   "email",
 	userbus.OrderByRoles:   "roles",
 	userbus.OrderByEnabled: "enabled",
 }
-
-func orderByClause(orderBy order.By) (string, error) {
-	by, exists := orderByFields[orderBy.Field]
-	if !exists {
-		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
-	}
-
-	return " ORDER BY " + by + " " + orderBy.Direction, nil
-}
@@ -2,11 +2,11 @@
 package userdb
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"net/mail"
+	"slices"
 
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
@@ -108,59 +108,80 @@ func (s *Store) Delete(ctx context.Context, usr userbus.User) error {
 
 // Query retrieves a list of existing users from the database.
 func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy order.By, page page.Page) ([]userbus.User, error) {
-	data := map[string]any{
-		"offset":        (page.Number() - 1) * page.RowsPerPage(),
-		"rows_per_page": page.RowsPerPage(),
-	}
+	qb := sqldb.NewBuilder("users", "user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated")
+	applyFilter(filter, qb)
 
-	const q = `
-	SELECT
-		user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
-	FROM
-		users`
-
-	buf := bytes.NewBufferString(q)
-	applyFilter(filter, data, buf)
-
-	orderByClause, err := orderByClause(orderBy)
-	if err != nil {
+	if err := qb.Page(orderByFields, orderBy, "user_id", page); err != nil {
 		return nil, err
 	}
 
-	buf.WriteString(orderByClause)
-	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
-
 	var dbUsrs []user
-	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbUsrs); err != nil {
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, qb.String(), qb.Data(), &dbUsrs); err != nil {
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
+	if page.IsBackward() {
+		slices.Reverse(dbUsrs)
+	}
+
 	return toBusUsers(dbUsrs)
 }
 
-// Count returns the total number of users in the DB.
-func (s *Store) Count(ctx context.Context, filter userbus.QueryFilter) (int, error) {
-	data := map[string]any{}
+// QueryEach reads every user matching the filter, passing each to fn as it
+// comes back from the database.
+func (s *Store) QueryEach(ctx context.Context, filter userbus.QueryFilter, orderBy order.By, fn func(userbus.User) error) error {
+	qb := sqldb.NewBuilder("users", "user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated")
+	applyFilter(filter, qb)
 
-	const q = `
-	SELECT
-		count(1)
-	FROM
-		users`
+	if err := qb.Order(orderByFields, orderBy, "user_id"); err != nil {
+		return err
+	}
+
+	each := func(dbUsr user) error {
+		usr, err := toBusUser(dbUsr)
+		if err != nil {
+			return err
+		}
+
+		return fn(usr)
+	}
 
-	buf := bytes.NewBufferString(q)
-	applyFilter(filter, data, buf)
+	if err := sqldb.NamedQueryEach(ctx, s.log, s.db, qb.String(), qb.Data(), each); err != nil {
+		return fmt.Errorf("namedqueryeach: %w", err)
+	}
+
+	return nil
+}
+
+// Count returns the total number of users in the DB.
+func (s *Store) Count(ctx context.Context, filter userbus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("users", "count(1)")
+	applyFilter(filter, qb)
 
 	var count struct {
 		Count int `db:"count"`
 	}
-	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, qb.String(), qb.Data(), &count); err != nil {
 		return 0, fmt.Errorf("db: %w", err)
 	}
 
 	return count.Count, nil
 }
 
+// EstimateCount returns the planner's estimate of the number of users in
+// the DB.
+func (s *Store) EstimateCount(ctx context.Context, filter userbus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("users", "1")
+	applyFilter(filter, qb)
+
+	count, err := sqldb.EstimateCount(ctx, s.log, s.db, qb.String(), qb.Data())
+	if err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count, nil
+}
+
 // QueryByID gets the specified user from the database.
 func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User, error) {
 	data := struct {
@@ -188,6 +209,39 @@ func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User,
 	return toBusUser(dbUsr)
 }
 
+// QueryByIDs gets the specified users from the database.
+func (s *Store) QueryByIDs(ctx context.Context, userIDs []uuid.UUID) ([]userbus.User, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	data := struct {
+		IDs []string `db:"user_ids"`
+	}{
+		IDs: ids,
+	}
+
+	const q = `
+	SELECT
+	    user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+	FROM
+		users
+	WHERE
+		user_id IN (:user_ids)`
+
+	var dbs []user
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, q, data, &dbs); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusUsers(dbs)
+}
+
 // QueryByEmail gets the specified user from the database by email.
 func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.User, error) {
 	data := struct {
@@ -8,7 +8,7 @@ import (
 	"net/mail"
 	"time"
 
-	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
@@ -32,39 +32,54 @@ type Storer interface {
 	Update(ctx context.Context, usr User) error
 	Delete(ctx context.Context, usr User) error
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]User, error)
+	QueryEach(ctx context.Context, filter QueryFilter, orderBy order.By, fn func(User) error) error
 	Count(ctx context.Context, filter QueryFilter) (int, error)
+	EstimateCount(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
+	QueryByIDs(ctx context.Context, userIDs []uuid.UUID) ([]User, error)
 	QueryByEmail(ctx context.Context, email mail.Address) (User, error)
 }
 
 // Business manages the set of APIs for user access.
 type Business struct {
-	log      *logger.Logger
-	storer   Storer
-	delegate *delegate.Delegate
+	log       *logger.Logger
+	storer    Storer
+	outboxBus *outboxbus.Business
 }
 
-// NewBusiness constructs a user business API for use.
-func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer) *Business {
+// NewBusiness constructs a user business API for use. The changes made to
+// users are sent to other domains through the outbox. If the business is
+// only used to query users, there won't be an outbox provided and any change
+// to a user fails with outboxbus.ErrNoOutbox.
+func NewBusiness(log *logger.Logger, outboxBus *outboxbus.Business, storer Storer) *Business {
 	return &Business{
-		log:      log,
-		delegate: delegate,
-		storer:   storer,
+		log:       log,
+		outboxBus: outboxBus,
+		storer:    storer,
 	}
 }
 
 // NewWithTx constructs a new business value that will use the
-// specified transaction in any store related calls.
+// specified transaction in any store related calls. The events for the
+// changes made are written in the same transaction.
 func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 	storer, err := b.storer.NewWithTx(tx)
 	if err != nil {
 		return nil, err
 	}
 
+	outboxBus := b.outboxBus
+	if outboxBus != nil {
+		outboxBus, err = b.outboxBus.NewWithTx(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	bus := Business{
-		log:      b.log,
-		delegate: b.delegate,
-		storer:   storer,
+		log:       b.log,
+		outboxBus: outboxBus,
+		storer:    storer,
 	}
 
 	return &bus, nil
@@ -95,6 +110,10 @@ func (b *Business) Create(ctx context.Context, nu NewUser) (User, error) {
 		return User{}, fmt.Errorf("create: %w", err)
 	}
 
+	if err := b.outboxBus.Add(ctx, ActionChangedData(ActionCreated, usr)); err != nil {
+		return User{}, fmt.Errorf("outbox: %w", err)
+	}
+
 	return usr, nil
 }
 
@@ -134,9 +153,10 @@ func (b *Business) Update(ctx context.Context, usr User, uu UpdateUser) (User, e
 	}
 
 	// Other domains may need to know when a user is updated so business
-	// logic can be applieb. This represents a delegate call to other domains.
-	if err := b.delegate.Call(ctx, ActionUpdatedData(uu, usr.ID)); err != nil {
-		return User{}, fmt.Errorf("failed to execute `%s` action: %w", ActionUpdated, err)
+	// logic can be applied. The event is written to the outbox so it's only
+	// sent if the update is committed.
+	if err := b.outboxBus.Add(ctx, ActionUpdatedData(uu, usr.ID)); err != nil {
+		return User{}, fmt.Errorf("outbox: %w", err)
 	}
 
 	return usr, nil
@@ -148,6 +168,10 @@ func (b *Business) Delete(ctx context.Context, usr User) error {
 		return fmt.Errorf("delete: %w", err)
 	}
 
+	if err := b.outboxBus.Add(ctx, ActionChangedData(ActionDeleted, usr)); err != nil {
+		return fmt.Errorf("outbox: %w", err)
+	}
+
 	return nil
 }
 
@@ -161,11 +185,27 @@ func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.
 	return users, nil
 }
 
+// QueryEach finds every user matching the filter and passes each to fn as
+// it's read, so they can be processed without holding them in memory.
+func (b *Business) QueryEach(ctx context.Context, filter QueryFilter, orderBy order.By, fn func(User) error) error {
+	if err := b.storer.QueryEach(ctx, filter, orderBy, fn); err != nil {
+		return fmt.Errorf("queryeach: %w", err)
+	}
+
+	return nil
+}
+
 // Count returns the total number of users.
 func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return b.storer.Count(ctx, filter)
 }
 
+// EstimateCount returns an estimate of the total number of users, which is
+// far cheaper than counting them.
+func (b *Business) EstimateCount(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.EstimateCount(ctx, filter)
+}
+
 // QueryByID finds the user by the specified Ib.
 func (b *Business) QueryByID(ctx context.Context, userID uuid.UUID) (User, error) {
 	user, err := b.storer.QueryByID(ctx, userID)
@@ -176,6 +216,17 @@ func (b *Business) QueryByID(ctx context.Context, userID uuid.UUID) (User, error
 	return user, nil
 }
 
+// QueryByIDs finds the users with the specified IDs. IDs that don't
+// match a user are left out of the result.
+func (b *Business) QueryByIDs(ctx context.Context, userIDs []uuid.UUID) ([]User, error) {
+	usrs, err := b.storer.QueryByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query: userIDs[%s]: %w", userIDs, err)
+	}
+
+	return usrs, nil
+}
+
 // QueryByEmail finds the user by a specified user email.
 func (b *Business) QueryByEmail(ctx context.Context, email mail.Address) (User, error) {
 	user, err := b.storer.QueryByEmail(ctx, email)
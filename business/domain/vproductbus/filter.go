@@ -3,15 +3,20 @@ package vproductbus
 import (
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/filter"
 	"github.com/google/uuid"
 )
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
 type QueryFilter struct {
-	ID       *uuid.UUID
+	ID       []filter.Condition[uuid.UUID]
 	Name     *productbus.Name
-	Cost     *float64
-	Quantity *int
+	Cost     []filter.Condition[float64]
+	Quantity []filter.Condition[int]
 	UserName *userbus.Name
+	Active   *bool
+
+	// Search matches products against a web search style query.
+	Search *string
 }
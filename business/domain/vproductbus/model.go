@@ -9,6 +9,7 @@ import (
 )
 
 // Product represents an individual product with extended information.
+// Snippet and Rank are only set when the product was found through a search.
 type Product struct {
 	ID          uuid.UUID
 	UserID      uuid.UUID
@@ -18,4 +19,6 @@ type Product struct {
 	DateCreated time.Time
 	DateUpdated time.Time
 	UserName    userbus.Name
+	Snippet     string
+	Rank        float64
 }
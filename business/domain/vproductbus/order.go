@@ -13,4 +13,5 @@ const (
 	OrderByCost      = "cost"
 	OrderByQuantity  = "quantity"
 	OrderByUserName  = "user_name"
+	OrderByRank      = "rank"
 )
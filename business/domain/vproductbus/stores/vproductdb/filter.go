@@ -1,43 +1,48 @@
 package vproductdb
 
 import (
-	"bytes"
-	"fmt"
-	"strings"
-
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
-func (s *Store) applyFilter(filter vproductbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
-	var wc []string
-
-	if filter.ID != nil {
-		data["product_id"] = *filter.ID
-		wc = append(wc, "product_id = :product_id")
+// escapedName is the product name escaped for HTML, so the only markup in a
+// highlighted snippet is the markup ts_headline adds around the matches.
+const escapedName = `replace(replace(replace(replace(replace(name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+
+// applySearch adds the highlighted snippet and the relevance rank to the
+// selected columns. Without a search every product has the same rank.
+func (s *Store) applySearch(filter vproductbus.QueryFilter, qb *sqldb.Builder) {
+	if filter.Search == nil {
+		qb.Column("'' AS snippet")
+		qb.Column("0 AS rank")
+		return
 	}
 
+	qb.Bind("search", *filter.Search)
+	qb.Column("ts_headline('english', " + escapedName + ", websearch_to_tsquery('english', :search)) AS snippet")
+	qb.Column("ts_rank(search, websearch_to_tsquery('english', :search)) AS rank")
+}
+
+func (s *Store) applyFilter(filter vproductbus.QueryFilter, qb *sqldb.Builder) {
+	sqldb.Conditions(qb, "product_id", filter.ID)
+
 	if filter.Name != nil {
-		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "name LIKE :name")
+		qb.Contains("name", filter.Name.String())
 	}
 
-	if filter.Cost != nil {
-		data["cost"] = *filter.Cost
-		wc = append(wc, "cost = :cost")
-	}
+	sqldb.Conditions(qb, "cost", filter.Cost)
+	sqldb.Conditions(qb, "quantity", filter.Quantity)
 
-	if filter.Quantity != nil {
-		data["quantity"] = *filter.Quantity
-		wc = append(wc, "quantity = :quantity")
+	if filter.UserName != nil {
+		qb.Contains("user_name", filter.UserName.String())
 	}
 
-	if filter.UserName != nil {
-		data["user_name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "user_name LIKE :user_name")
+	if filter.Active != nil {
+		qb.Equal("active", *filter.Active)
 	}
 
-	if len(wc) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(wc, " AND "))
+	if filter.Search != nil {
+		qb.Bind("search", *filter.Search)
+		qb.Where("search @@ websearch_to_tsquery('english', :search)")
 	}
 }
@@ -19,6 +19,8 @@ type product struct {
 	DateCreated time.Time `db:"date_created"`
 	DateUpdated time.Time `db:"date_updated"`
 	UserName    string    `db:"user_name"`
+	Snippet     string    `db:"snippet"`
+	Rank        float64   `db:"rank"`
 }
 
 func toBusProduct(db product) (vproductbus.Product, error) {
@@ -41,6 +43,8 @@ func toBusProduct(db product) (vproductbus.Product, error) {
 		DateCreated: db.DateCreated.In(time.Local),
 		DateUpdated: db.DateUpdated.In(time.Local),
 		UserName:    userName,
+		Snippet:     db.Snippet,
+		Rank:        db.Rank,
 	}
 
 	return bus, nil
@@ -2,9 +2,9 @@
 package vproductdb
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
@@ -30,40 +30,23 @@ func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
 
 // Query retrieves a list of existing products from the database.
 func (s *Store) Query(ctx context.Context, filter vproductbus.QueryFilter, orderBy order.By, page page.Page) ([]vproductbus.Product, error) {
-	data := map[string]any{
-		"offset":        (page.Number() - 1) * page.RowsPerPage(),
-		"rows_per_page": page.RowsPerPage(),
-	}
+	qb := sqldb.NewBuilder("view_products", "product_id, user_id, name, cost, quantity, date_created, date_updated, user_name")
+	s.applySearch(filter, qb)
+	s.applyFilter(filter, qb)
 
-	const q = `
-	SELECT
-		product_id,
-		user_id,
-		name,
-		cost,
-		quantity,
-		date_created,
-		date_updated,
-		user_name
-	FROM
-		view_products`
-
-	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
-
-	orderByClause, err := orderByClause(orderBy)
-	if err != nil {
+	if err := qb.Page(orderByFields, orderBy, "product_id", page); err != nil {
 		return nil, err
 	}
 
-	buf.WriteString(orderByClause)
-	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
-
 	var dnPrd []product
-	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dnPrd); err != nil {
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, qb.String(), qb.Data(), &dnPrd); err != nil {
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
+	if page.IsBackward() {
+		slices.Reverse(dnPrd)
+	}
+
 	prd, err := toBusProducts(dnPrd)
 	if err != nil {
 		return nil, err
@@ -74,23 +57,29 @@ func (s *Store) Query(ctx context.Context, filter vproductbus.QueryFilter, order
 
 // Count returns the total number of products in the DB.
 func (s *Store) Count(ctx context.Context, filter vproductbus.QueryFilter) (int, error) {
-	data := map[string]any{}
-
-	const q = `
-	SELECT
-		count(1)
-	FROM
-		view_products`
-
-	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	qb := sqldb.NewBuilder("view_products", "count(1)")
+	s.applyFilter(filter, qb)
 
 	var count struct {
 		Count int `db:"count"`
 	}
-	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, qb.String(), qb.Data(), &count); err != nil {
 		return 0, fmt.Errorf("db: %w", err)
 	}
 
 	return count.Count, nil
 }
+
+// EstimateCount returns the planner's estimate of the number of products in
+// the DB.
+func (s *Store) EstimateCount(ctx context.Context, filter vproductbus.QueryFilter) (int, error) {
+	qb := sqldb.NewBuilder("view_products", "1")
+	s.applyFilter(filter, qb)
+
+	count, err := sqldb.EstimateCount(ctx, s.log, s.db, qb.String(), qb.Data())
+	if err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count, nil
+}
@@ -155,6 +155,26 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "inactive",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				filter := vproductbus.QueryFilter{
+					Name:   dbtest.ProductNamePointer("Name"),
+					Active: dbtest.BoolPointer(false),
+				}
+
+				resp, err := busDomain.VProduct.Query(ctx, filter, vproductbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return len(resp)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 	}
 
 	return table
@@ -0,0 +1,314 @@
+package webhookbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/eventbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/google/uuid"
+)
+
+// Set of values that control the attempts made for a delivery. The wait
+// before each retry doubles, from MinBackoff up to MaxBackoff.
+const (
+	MaxAttempts = 8
+	MinBackoff  = 30 * time.Second
+	MaxBackoff  = 6 * time.Hour
+)
+
+// retryRows is the most deliveries retried by a single call to RetryDue.
+const retryRows = 100
+
+// payload represents the body of a delivery.
+type payload struct {
+	ID          uuid.UUID       `json:"id"`
+	Type        string          `json:"type"`
+	EntityID    uuid.UUID       `json:"entityID"`
+	UserID      uuid.UUID       `json:"userID"`
+	Data        json.RawMessage `json:"data"`
+	DateCreated time.Time       `json:"dateCreated"`
+}
+
+// Dispatch sends the event described by the delegate data to the webhooks
+// registered for it. Webhooks only get the events about what their user
+// owns, unless the user is an admin. The id of the event is used for every
+// delivery of it, so a webhook can recognize an event it already received.
+// A delivery that fails is retried later by RetryDue. An error is only
+// returned while no delivery has been recorded, since the event is then
+// handled again from the start; once one is recorded the webhooks that
+// couldn't get a delivery are only logged.
+func (b *Business) Dispatch(ctx context.Context, data delegate.Data) error {
+	typ := eventbus.Type(data.Domain, data.Action)
+	if !slices.Contains(eventbus.Types(), typ) {
+		return nil
+	}
+
+	entityID, userID, err := eventbus.ParseChange(data)
+	if err != nil {
+		return fmt.Errorf("parsechange: %w", err)
+	}
+
+	whs, err := b.storer.QueryByEvent(ctx, typ)
+	if err != nil {
+		return fmt.Errorf("querybyevent: %s: %w", typ, err)
+	}
+
+	var targets []Webhook
+	for _, wh := range whs {
+		if wh.UserID != userID {
+			admin, err := b.isAdmin(ctx, wh.UserID)
+			if err != nil {
+				return err
+			}
+
+			if !admin {
+				continue
+			}
+		}
+
+		targets = append(targets, wh)
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	// Events published without going through the outbox have no id. The
+	// ones that do can be delivered again, and the webhooks that already
+	// have a delivery of the event don't get another one.
+	eventID := data.ID
+	switch eventID {
+	case uuid.Nil:
+		eventID = uuid.New()
+
+	default:
+		delivered, err := b.storer.QueryDeliveredWebhookIDs(ctx, eventID)
+		if err != nil {
+			return fmt.Errorf("querydeliveredwebhookids: eventID[%s]: %w", eventID, err)
+		}
+
+		targets = slices.DeleteFunc(targets, func(wh Webhook) bool {
+			return slices.Contains(delivered, wh.ID)
+		})
+
+		if len(targets) == 0 {
+			return nil
+		}
+	}
+
+	now := time.Now()
+
+	body, err := json.Marshal(payload{
+		ID:          eventID,
+		Type:        typ,
+		EntityID:    entityID,
+		UserID:      userID,
+		Data:        data.RawParams,
+		DateCreated: now,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	var recorded int
+	for _, wh := range targets {
+		dlv := Delivery{
+			ID:          uuid.New(),
+			WebhookID:   wh.ID,
+			EventID:     eventID,
+			EventType:   typ,
+			Payload:     body,
+			Status:      Statuses.Pending,
+			DateCreated: now,
+		}
+
+		if err := b.deliver(ctx, wh, dlv); err != nil {
+			if recorded == 0 {
+				return fmt.Errorf("deliver: webhookID[%s]: %w", wh.ID, err)
+			}
+
+			b.log.Error(ctx, "webhook delivery", "eventID", eventID, "webhookID", wh.ID, "msg", err)
+			continue
+		}
+
+		recorded++
+	}
+
+	return nil
+}
+
+// RetryDue makes another attempt at every pending delivery whose next
+// attempt is due. It returns the number of deliveries attempted.
+func (b *Business) RetryDue(ctx context.Context, now time.Time) (int, error) {
+	dlvs, err := b.storer.QueryDueDeliveries(ctx, now, retryRows)
+	if err != nil {
+		return 0, fmt.Errorf("queryduedeliveries: %w", err)
+	}
+
+	whs := make(map[uuid.UUID]Webhook)
+
+	for i, dlv := range dlvs {
+		wh, exists := whs[dlv.WebhookID]
+		if !exists {
+			wh, err = b.storer.QueryByID(ctx, dlv.WebhookID)
+			if err != nil {
+				return i, fmt.Errorf("querybyid: webhookID[%s]: %w", dlv.WebhookID, err)
+			}
+			whs[wh.ID] = wh
+		}
+
+		if !wh.Enabled {
+			dlv.Status = Statuses.Failed
+			dlv.Error = "webhook disabled"
+			dlv.DateNext = time.Time{}
+			dlv.DateUpdated = now
+
+			if err := b.storer.UpdateDelivery(ctx, dlv); err != nil {
+				return i, fmt.Errorf("updatedelivery: deliveryID[%s]: %w", dlv.ID, err)
+			}
+			continue
+		}
+
+		if err := b.attempt(ctx, wh, dlv); err != nil {
+			return i, fmt.Errorf("attempt: deliveryID[%s]: %w", dlv.ID, err)
+		}
+	}
+
+	return len(dlvs), nil
+}
+
+// Replay sends a failed delivery again as a new delivery of the same event,
+// so the log keeps the failed one.
+func (b *Business) Replay(ctx context.Context, dlv Delivery) (Delivery, error) {
+	if dlv.Status != Statuses.Failed {
+		return Delivery{}, ErrNotFailed
+	}
+
+	wh, err := b.storer.QueryByID(ctx, dlv.WebhookID)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("querybyid: webhookID[%s]: %w", dlv.WebhookID, err)
+	}
+
+	rpl := Delivery{
+		ID:          uuid.New(),
+		WebhookID:   dlv.WebhookID,
+		EventID:     dlv.EventID,
+		EventType:   dlv.EventType,
+		Payload:     dlv.Payload,
+		Status:      Statuses.Pending,
+		DateCreated: time.Now(),
+	}
+
+	if err := b.deliver(ctx, wh, rpl); err != nil {
+		return Delivery{}, fmt.Errorf("deliver: %w", err)
+	}
+
+	return b.storer.QueryDeliveryByID(ctx, rpl.ID)
+}
+
+// =============================================================================
+
+// deliver records a new delivery and makes the first attempt at it. The
+// delivery is recorded as due after the first wait, so a process that dies
+// while sending it, or fails to record the outcome, leaves it to be retried.
+// Only a failure to record the delivery is returned.
+func (b *Business) deliver(ctx context.Context, wh Webhook, dlv Delivery) error {
+	dlv.DateNext = dlv.DateCreated.Add(MinBackoff)
+	dlv.DateUpdated = dlv.DateCreated
+
+	if err := b.storer.CreateDelivery(ctx, dlv); err != nil {
+		return fmt.Errorf("createdelivery: %w", err)
+	}
+
+	if err := b.attempt(ctx, wh, dlv); err != nil {
+		b.log.Error(ctx, "webhook delivery", "deliveryID", dlv.ID, "webhookID", wh.ID, "msg", err)
+	}
+
+	return nil
+}
+
+// attempt sends the delivery to the webhook and records the outcome. A
+// delivery that fails waits twice as long as the last time before it's
+// tried again, until it runs out of attempts.
+func (b *Business) attempt(ctx context.Context, wh Webhook, dlv Delivery) error {
+	now := time.Now()
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set(HeaderEvent, dlv.EventType)
+	header.Set(HeaderEventID, dlv.EventID.String())
+	header.Set(HeaderDelivery, dlv.ID.String())
+	header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	header.Set(HeaderSignature, Sign(wh.Secret, now.Unix(), dlv.Payload))
+
+	code, err := b.sender.Send(ctx, wh.URL, header, dlv.Payload)
+	if err == nil && (code < 200 || code > 299) {
+		err = fmt.Errorf("unexpected status code %d", code)
+	}
+
+	dlv.Attempts++
+	dlv.StatusCode = code
+	dlv.DateUpdated = now
+
+	switch {
+	case err == nil:
+		dlv.Status = Statuses.Succeeded
+		dlv.Error = ""
+		dlv.DateNext = time.Time{}
+
+	case dlv.Attempts >= MaxAttempts:
+		dlv.Status = Statuses.Failed
+		dlv.Error = err.Error()
+		dlv.DateNext = time.Time{}
+
+	default:
+		dlv.Status = Statuses.Pending
+		dlv.Error = err.Error()
+		dlv.DateNext = now.Add(backoff(dlv.Attempts))
+	}
+
+	if err != nil {
+		b.log.Info(ctx, "webhook delivery", "deliveryID", dlv.ID, "webhookID", wh.ID, "attempts", dlv.Attempts, "status", dlv.Status, "msg", err)
+	}
+
+	if err := b.storer.UpdateDelivery(ctx, dlv); err != nil {
+		return fmt.Errorf("updatedelivery: %w", err)
+	}
+
+	return nil
+}
+
+// backoff returns the wait after the specified number of failed attempts.
+func backoff(attempts int) time.Duration {
+	d := MinBackoff
+	for range attempts - 1 {
+		d *= 2
+		if d >= MaxBackoff {
+			return MaxBackoff
+		}
+	}
+
+	return d
+}
+
+// isAdmin reports whether the specified user is an admin. A user that no
+// longer exists isn't.
+func (b *Business) isAdmin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	usr, err := b.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("user.querybyid: %s: %w", userID, err)
+	}
+
+	return slices.Contains(usr.Roles, userbus.Roles.Admin), nil
+}
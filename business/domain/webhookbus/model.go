@@ -0,0 +1,55 @@
+package webhookbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook represents an endpoint registered by a user to be told about
+// events of the specified types. The secret signs every delivery so the
+// endpoint can check it came from us.
+type Webhook struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	URL         string
+	Secret      string
+	Events      []string
+	Enabled     bool
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewWebhook is what we require from clients when adding a Webhook.
+type NewWebhook struct {
+	UserID uuid.UUID
+	URL    string
+	Events []string
+}
+
+// UpdateWebhook defines what information may be provided to modify an
+// existing Webhook. All fields are optional so clients can send just the
+// fields they want changed.
+type UpdateWebhook struct {
+	URL     *string
+	Events  []string
+	Enabled *bool
+}
+
+// Delivery represents the sending of an event to a webhook and the outcome
+// of the last attempt. The event id is the same for every delivery of the
+// event, so endpoints can drop the ones they have already seen.
+type Delivery struct {
+	ID          uuid.UUID
+	WebhookID   uuid.UUID
+	EventID     uuid.UUID
+	EventType   string
+	Payload     []byte
+	Status      Status
+	Attempts    int
+	StatusCode  int
+	Error       string
+	DateNext    time.Time
+	DateCreated time.Time
+	DateUpdated time.Time
+}
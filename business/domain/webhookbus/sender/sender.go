@@ -0,0 +1,159 @@
+// Package sender provides support for sending deliveries to webhooks.
+package sender
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrNotAllowed is used when a webhook url isn't one deliveries can be sent
+// to.
+var ErrNotAllowed = errors.New("url not allowed")
+
+// CheckURL checks the url is one deliveries can be sent to. It must use https
+// and its host can't resolve to a loopback, link-local or private address.
+// The addresses are checked again when a delivery is sent, since the host
+// can resolve to a different address by then.
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parse: %w", err)
+	}
+
+	if u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q isn't https", ErrNotAllowed, u.Scheme)
+	}
+
+	ips, err := net.LookupIP(u.Hostname())
+	if err != nil {
+		return fmt.Errorf("lookup: %s: %w", u.Hostname(), err)
+	}
+
+	for _, ip := range ips {
+		if err := checkIP(ip); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkIP checks the address is a public one.
+func checkIP(ip net.IP) error {
+	switch {
+	case ip.IsLoopback(), ip.IsPrivate(), ip.IsUnspecified(),
+		ip.IsLinkLocalUnicast(), ip.IsLinkLocalMulticast(), ip.IsInterfaceLocalMulticast():
+		return fmt.Errorf("%w: address %s isn't public", ErrNotAllowed, ip)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// Option represents a change to how the sender is constructed.
+type Option func(*HTTP)
+
+// WithInsecure lets the sender send deliveries over http and to any address,
+// which is only meant for tests sending to a local endpoint.
+func WithInsecure() Option {
+	return func(h *HTTP) {
+		h.insecure = true
+	}
+}
+
+// HTTP sends deliveries as http POST requests.
+type HTTP struct {
+	client   *http.Client
+	insecure bool
+}
+
+// NewHTTP constructs a sender that gives up on a webhook that hasn't
+// responded within the timeout. Deliveries are only sent over https to
+// public addresses, which is checked for every connection made, including
+// the ones for a redirect.
+func NewHTTP(timeout time.Duration, opts ...Option) *HTTP {
+	var h HTTP
+	for _, opt := range opts {
+		opt(&h)
+	}
+
+	if h.insecure {
+		h.client = &http.Client{
+			Timeout: timeout,
+		}
+		return &h
+	}
+
+	dialer := net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return fmt.Errorf("split: %w", err)
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil {
+				return fmt.Errorf("%w: address %q isn't an ip", ErrNotAllowed, host)
+			}
+
+			return checkIP(ip)
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	h.client = &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: redirect scheme %q isn't https", ErrNotAllowed, req.URL.Scheme)
+			}
+
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+
+			return nil
+		},
+	}
+
+	return &h
+}
+
+// Send implements the webhookbus.Sender interface.
+func (h *HTTP) Send(ctx context.Context, rawURL string, header http.Header, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("new request: %w", err)
+	}
+	req.Header = header
+
+	if !h.insecure && req.URL.Scheme != "https" {
+		return 0, fmt.Errorf("%w: scheme %q isn't https", ErrNotAllowed, req.URL.Scheme)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The response is read so the connection can be reused, but only so
+	// much of it since nothing is done with it.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
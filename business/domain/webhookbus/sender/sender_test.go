@@ -0,0 +1,46 @@
+package sender_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/webhookbus/sender"
+)
+
+func Test_CheckURL(t *testing.T) {
+	notAllowed := []string{
+		"http://93.184.216.34/hook",
+		"https://127.0.0.1/hook",
+		"https://10.0.0.1/hook",
+		"https://192.168.1.1/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/hook",
+		"https://[fe80::1]/hook",
+	}
+
+	for _, u := range notAllowed {
+		if err := sender.CheckURL(u); !errors.Is(err, sender.ErrNotAllowed) {
+			t.Errorf("Should not allow %s: got %v", u, err)
+		}
+	}
+
+	if err := sender.CheckURL("https://93.184.216.34/hook"); err != nil {
+		t.Errorf("Should allow a public address: %s", err)
+	}
+}
+
+func Test_Send(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	_, err := sender.NewHTTP(time.Second).Send(context.Background(), srv.URL, make(http.Header), nil)
+	if !errors.Is(err, sender.ErrNotAllowed) {
+		t.Errorf("Should not send to a loopback address: got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package webhookbus_test
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
+)
+
+func Test_Sign(t *testing.T) {
+	const secret = "s3cr3t"
+	const ts = 1700000000
+	body := []byte(`{"type":"product.created"}`)
+
+	sig := webhookbus.Sign(secret, ts, body)
+
+	if !webhookbus.Verify(secret, ts, body, sig) {
+		t.Fatalf("Should verify the signature of the body: %s", sig)
+	}
+
+	if webhookbus.Verify(secret, ts+1, body, sig) {
+		t.Errorf("Should not verify the signature for another timestamp")
+	}
+
+	if webhookbus.Verify(secret, ts, []byte(`{"type":"product.deleted"}`), sig) {
+		t.Errorf("Should not verify the signature for another body")
+	}
+
+	if webhookbus.Verify("other", ts, body, sig) {
+		t.Errorf("Should not verify the signature for another secret")
+	}
+}
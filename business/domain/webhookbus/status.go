@@ -0,0 +1,66 @@
+package webhookbus
+
+import "fmt"
+
+type statusSet struct {
+	Pending   Status
+	Succeeded Status
+	Failed    Status
+}
+
+// Statuses represents the set of states a delivery can be in. A pending
+// delivery is waiting for its next attempt, a failed one ran out of them.
+var Statuses = statusSet{
+	Pending:   newStatus("PENDING"),
+	Succeeded: newStatus("SUCCEEDED"),
+	Failed:    newStatus("FAILED"),
+}
+
+// =============================================================================
+
+// Set of known statuses.
+var statuses = make(map[string]Status)
+
+// Status represents the state of a delivery.
+type Status struct {
+	name string
+}
+
+func newStatus(status string) Status {
+	s := Status{status}
+	statuses[status] = s
+	return s
+}
+
+// String returns the name of the status.
+func (s Status) String() string {
+	return s.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (s Status) Equal(s2 Status) bool {
+	return s.name == s2.name
+}
+
+// =============================================================================
+
+// ParseStatus parses the string value and returns a status if one exists.
+func ParseStatus(value string) (Status, error) {
+	status, exists := statuses[value]
+	if !exists {
+		return Status{}, fmt.Errorf("invalid status %q", value)
+	}
+
+	return status, nil
+}
+
+// MustParseStatus parses the string value and returns a status if one
+// exists. If an error occurs the function panics.
+func MustParseStatus(value string) Status {
+	status, err := ParseStatus(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return status
+}
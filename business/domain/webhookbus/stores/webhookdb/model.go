@@ -0,0 +1,142 @@
+package webhookdb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb/dbarray"
+	"github.com/google/uuid"
+)
+
+type webhook struct {
+	ID          uuid.UUID      `db:"webhook_id"`
+	UserID      uuid.UUID      `db:"user_id"`
+	URL         string         `db:"url"`
+	Secret      string         `db:"secret"`
+	Events      dbarray.String `db:"events"`
+	Enabled     bool           `db:"enabled"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+func toDBWebhook(bus webhookbus.Webhook) webhook {
+	db := webhook{
+		ID:          bus.ID,
+		UserID:      bus.UserID,
+		URL:         bus.URL,
+		Secret:      bus.Secret,
+		Events:      bus.Events,
+		Enabled:     bus.Enabled,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+
+	return db
+}
+
+func toBusWebhook(db webhook) webhookbus.Webhook {
+	bus := webhookbus.Webhook{
+		ID:          db.ID,
+		UserID:      db.UserID,
+		URL:         db.URL,
+		Secret:      db.Secret,
+		Events:      db.Events,
+		Enabled:     db.Enabled,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus
+}
+
+func toBusWebhooks(dbs []webhook) []webhookbus.Webhook {
+	bus := make([]webhookbus.Webhook, len(dbs))
+
+	for i, db := range dbs {
+		bus[i] = toBusWebhook(db)
+	}
+
+	return bus
+}
+
+// =============================================================================
+
+type delivery struct {
+	ID          uuid.UUID    `db:"delivery_id"`
+	WebhookID   uuid.UUID    `db:"webhook_id"`
+	EventID     uuid.UUID    `db:"event_id"`
+	EventType   string       `db:"event_type"`
+	Payload     []byte       `db:"payload"`
+	Status      string       `db:"status"`
+	Attempts    int          `db:"attempts"`
+	StatusCode  int          `db:"status_code"`
+	Error       string       `db:"error"`
+	DateNext    sql.NullTime `db:"date_next"`
+	DateCreated time.Time    `db:"date_created"`
+	DateUpdated time.Time    `db:"date_updated"`
+}
+
+func toDBDelivery(bus webhookbus.Delivery) delivery {
+	db := delivery{
+		ID:         bus.ID,
+		WebhookID:  bus.WebhookID,
+		EventID:    bus.EventID,
+		EventType:  bus.EventType,
+		Payload:    bus.Payload,
+		Status:     bus.Status.String(),
+		Attempts:   bus.Attempts,
+		StatusCode: bus.StatusCode,
+		Error:      bus.Error,
+		DateNext: sql.NullTime{
+			Time:  bus.DateNext.UTC(),
+			Valid: !bus.DateNext.IsZero(),
+		},
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+
+	return db
+}
+
+func toBusDelivery(db delivery) (webhookbus.Delivery, error) {
+	status, err := webhookbus.ParseStatus(db.Status)
+	if err != nil {
+		return webhookbus.Delivery{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	bus := webhookbus.Delivery{
+		ID:          db.ID,
+		WebhookID:   db.WebhookID,
+		EventID:     db.EventID,
+		EventType:   db.EventType,
+		Payload:     db.Payload,
+		Status:      status,
+		Attempts:    db.Attempts,
+		StatusCode:  db.StatusCode,
+		Error:       db.Error,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	if db.DateNext.Valid {
+		bus.DateNext = db.DateNext.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
+
+func toBusDeliveries(dbs []delivery) ([]webhookbus.Delivery, error) {
+	bus := make([]webhookbus.Delivery, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusDelivery(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
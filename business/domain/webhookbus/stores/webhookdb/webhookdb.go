@@ -0,0 +1,348 @@
+// Package webhookdb contains webhook related CRUD functionality.
+package webhookdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for webhook database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new webhook into the database.
+func (s *Store) Create(ctx context.Context, wh webhookbus.Webhook) error {
+	const q = `
+	INSERT INTO webhooks
+		(webhook_id, user_id, url, secret, events, enabled, date_created, date_updated)
+	VALUES
+		(:webhook_id, :user_id, :url, :secret, :events, :enabled, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBWebhook(wh)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a webhook document in the database.
+func (s *Store) Update(ctx context.Context, wh webhookbus.Webhook) error {
+	const q = `
+	UPDATE
+		webhooks
+	SET
+		"url" = :url,
+		"events" = :events,
+		"enabled" = :enabled,
+		"date_updated" = :date_updated
+	WHERE
+		webhook_id = :webhook_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBWebhook(wh)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the webhook identified by a given ID along with its
+// deliveries.
+func (s *Store) Delete(ctx context.Context, wh webhookbus.Webhook) error {
+	data := struct {
+		ID string `db:"webhook_id"`
+	}{
+		ID: wh.ID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		webhooks
+	WHERE
+		webhook_id = :webhook_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID finds the webhook identified by a given ID.
+func (s *Store) QueryByID(ctx context.Context, webhookID uuid.UUID) (webhookbus.Webhook, error) {
+	data := struct {
+		ID string `db:"webhook_id"`
+	}{
+		ID: webhookID.String(),
+	}
+
+	const q = `
+	SELECT
+		webhook_id, user_id, url, secret, events, enabled, date_created, date_updated
+	FROM
+		webhooks
+	WHERE
+		webhook_id = :webhook_id`
+
+	var dbWh webhook
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbWh); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return webhookbus.Webhook{}, fmt.Errorf("db: %w", webhookbus.ErrNotFound)
+		}
+		return webhookbus.Webhook{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusWebhook(dbWh), nil
+}
+
+// QueryByUserID finds the webhooks registered by a given user.
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]webhookbus.Webhook, error) {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		webhook_id, user_id, url, secret, events, enabled, date_created, date_updated
+	FROM
+		webhooks
+	WHERE
+		user_id = :user_id
+	ORDER BY
+		date_created`
+
+	var dbWhs []webhook
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbWhs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusWebhooks(dbWhs), nil
+}
+
+// QueryByEvent finds the enabled webhooks registered for a given type of
+// event.
+func (s *Store) QueryByEvent(ctx context.Context, eventType string) ([]webhookbus.Webhook, error) {
+	data := struct {
+		EventType string `db:"event_type"`
+	}{
+		EventType: eventType,
+	}
+
+	const q = `
+	SELECT
+		webhook_id, user_id, url, secret, events, enabled, date_created, date_updated
+	FROM
+		webhooks
+	WHERE
+		enabled AND
+		:event_type = ANY(events)`
+
+	var dbWhs []webhook
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbWhs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusWebhooks(dbWhs), nil
+}
+
+// =============================================================================
+
+// CreateDelivery inserts a new delivery into the database.
+func (s *Store) CreateDelivery(ctx context.Context, dlv webhookbus.Delivery) error {
+	const q = `
+	INSERT INTO webhook_deliveries
+		(delivery_id, webhook_id, event_id, event_type, payload, status, attempts, status_code, error, date_next, date_created, date_updated)
+	VALUES
+		(:delivery_id, :webhook_id, :event_id, :event_type, :payload, :status, :attempts, :status_code, :error, :date_next, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDelivery(dlv)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDelivery records the outcome of the last attempt at a delivery.
+func (s *Store) UpdateDelivery(ctx context.Context, dlv webhookbus.Delivery) error {
+	const q = `
+	UPDATE
+		webhook_deliveries
+	SET
+		"status" = :status,
+		"attempts" = :attempts,
+		"status_code" = :status_code,
+		"error" = :error,
+		"date_next" = :date_next,
+		"date_updated" = :date_updated
+	WHERE
+		delivery_id = :delivery_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDelivery(dlv)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryDeliveryByID finds the delivery identified by a given ID.
+func (s *Store) QueryDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (webhookbus.Delivery, error) {
+	data := struct {
+		ID string `db:"delivery_id"`
+	}{
+		ID: deliveryID.String(),
+	}
+
+	const q = `
+	SELECT
+		delivery_id, webhook_id, event_id, event_type, payload, status, attempts, status_code, error, date_next, date_created, date_updated
+	FROM
+		webhook_deliveries
+	WHERE
+		delivery_id = :delivery_id`
+
+	var dbDlv delivery
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbDlv); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return webhookbus.Delivery{}, fmt.Errorf("db: %w", webhookbus.ErrDeliveryNotFound)
+		}
+		return webhookbus.Delivery{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusDelivery(dbDlv)
+}
+
+// QueryDeliveries gets the deliveries made to the specified webhook, newest
+// first.
+func (s *Store) QueryDeliveries(ctx context.Context, webhookID uuid.UUID, page page.Page) ([]webhookbus.Delivery, error) {
+	data := map[string]any{
+		"webhook_id":    webhookID.String(),
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		delivery_id, webhook_id, event_id, event_type, payload, status, attempts, status_code, error, date_next, date_created, date_updated
+	FROM
+		webhook_deliveries
+	WHERE
+		webhook_id = :webhook_id
+	ORDER BY
+		date_created DESC, delivery_id
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var dbDlvs []delivery
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbDlvs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusDeliveries(dbDlvs)
+}
+
+// QueryDeliveredWebhookIDs gets the ids of the webhooks that have a delivery
+// of the specified event.
+func (s *Store) QueryDeliveredWebhookIDs(ctx context.Context, eventID uuid.UUID) ([]uuid.UUID, error) {
+	data := struct {
+		EventID string `db:"event_id"`
+	}{
+		EventID: eventID.String(),
+	}
+
+	const q = `
+	SELECT DISTINCT
+		webhook_id
+	FROM
+		webhook_deliveries
+	WHERE
+		event_id = :event_id`
+
+	var dbIDs []struct {
+		ID uuid.UUID `db:"webhook_id"`
+	}
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbIDs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(dbIDs))
+	for i, dbID := range dbIDs {
+		ids[i] = dbID.ID
+	}
+
+	return ids, nil
+}
+
+// CountDeliveries returns the number of deliveries made to the specified
+// webhook.
+func (s *Store) CountDeliveries(ctx context.Context, webhookID uuid.UUID) (int, error) {
+	data := struct {
+		ID string `db:"webhook_id"`
+	}{
+		ID: webhookID.String(),
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		webhook_deliveries
+	WHERE
+		webhook_id = :webhook_id`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryDueDeliveries gets the pending deliveries whose next attempt is due,
+// oldest first.
+func (s *Store) QueryDueDeliveries(ctx context.Context, now time.Time, rows int) ([]webhookbus.Delivery, error) {
+	data := map[string]any{
+		"status": webhookbus.Statuses.Pending.String(),
+		"now":    now.UTC(),
+		"rows":   rows,
+	}
+
+	const q = `
+	SELECT
+		delivery_id, webhook_id, event_id, event_type, payload, status, attempts, status_code, error, date_next, date_created, date_updated
+	FROM
+		webhook_deliveries
+	WHERE
+		status = :status AND
+		date_next <= :now
+	ORDER BY
+		date_next
+	LIMIT :rows`
+
+	var dbDlvs []delivery
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbDlvs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusDeliveries(dbDlvs)
+}
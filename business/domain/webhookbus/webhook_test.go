@@ -0,0 +1,282 @@
+package webhookbus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Webhook(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, create(db.BusDomain, sd), "create")
+	unitest.Run(t, deliver(t, db.BusDomain, sd), "deliver")
+	unitest.Run(t, retry(t, db.BusDomain, sd), "retry")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, userbus.Roles.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+// request represents what the receiver was sent.
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver starts a webhook endpoint that responds with the specified
+// status code and passes on every request it's sent.
+func newReceiver(t *testing.T, statusCode int) (*httptest.Server, chan request) {
+	reqs := make(chan request, 10)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		reqs <- request{header: r.Header, body: body}
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, reqs
+}
+
+// delivery holds the parts of a delivery the tests check.
+type delivery struct {
+	EventType  string
+	Status     webhookbus.Status
+	Attempts   int
+	StatusCode int
+}
+
+func toDelivery(dlv webhookbus.Delivery) delivery {
+	return delivery{
+		EventType:  dlv.EventType,
+		Status:     dlv.Status,
+		Attempts:   dlv.Attempts,
+		StatusCode: dlv.StatusCode,
+	}
+}
+
+// =============================================================================
+
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "event",
+			ExpResp: webhookbus.ErrInvalidEvent,
+			ExcFunc: func(ctx context.Context) any {
+				nw := webhookbus.NewWebhook{
+					UserID: sd.Users[0].ID,
+					URL:    "http://localhost/hook",
+					Events: []string{"product.created", "product.archived"},
+				}
+
+				_, err := busDomain.Webhook.Create(ctx, nw)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func deliver(t *testing.T, busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	srv, reqs := newReceiver(t, http.StatusNoContent)
+
+	table := []unitest.Table{
+		{
+			Name: "signed",
+			ExpResp: []delivery{
+				{
+					EventType:  "product.created",
+					Status:     webhookbus.Statuses.Succeeded,
+					Attempts:   1,
+					StatusCode: http.StatusNoContent,
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				nw := webhookbus.NewWebhook{
+					UserID: sd.Users[0].ID,
+					URL:    srv.URL,
+					Events: []string{"product.created"},
+				}
+
+				wh, err := busDomain.Webhook.Create(ctx, nw)
+				if err != nil {
+					return err
+				}
+
+				// The product of the other user isn't sent to the webhook.
+				eventID := uuid.New()
+				for _, usr := range sd.Users {
+					prd := productbus.Product{ID: uuid.New(), UserID: usr.ID}
+
+					data := productbus.ActionChangedData(productbus.ActionCreated, prd)
+					data.ID = eventID
+
+					if err := busDomain.Webhook.Dispatch(ctx, data); err != nil {
+						return err
+					}
+				}
+
+				req := <-reqs
+				if len(reqs) != 0 {
+					return fmt.Errorf("got %d requests, exp 1", len(reqs)+1)
+				}
+
+				ts, err := strconv.ParseInt(req.header.Get(webhookbus.HeaderTimestamp), 10, 64)
+				if err != nil {
+					return err
+				}
+
+				if !webhookbus.Verify(wh.Secret, ts, req.body, req.header.Get(webhookbus.HeaderSignature)) {
+					return errors.New("signature doesn't verify")
+				}
+
+				// The body and the header carry the id of the event.
+				var body struct {
+					ID uuid.UUID `json:"id"`
+				}
+				if err := json.Unmarshal(req.body, &body); err != nil {
+					return err
+				}
+
+				if body.ID != eventID || req.header.Get(webhookbus.HeaderEventID) != eventID.String() {
+					return fmt.Errorf("got event id %s and header %s, exp %s", body.ID, req.header.Get(webhookbus.HeaderEventID), eventID)
+				}
+
+				dlvs, err := busDomain.Webhook.QueryDeliveries(ctx, wh.ID, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				resp := make([]delivery, len(dlvs))
+				for i, dlv := range dlvs {
+					resp[i] = toDelivery(dlv)
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func retry(t *testing.T, busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	srv, _ := newReceiver(t, http.StatusInternalServerError)
+
+	table := []unitest.Table{
+		{
+			Name: "pending",
+			ExpResp: delivery{
+				EventType:  "product.created",
+				Status:     webhookbus.Statuses.Pending,
+				Attempts:   1,
+				StatusCode: http.StatusInternalServerError,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				nw := webhookbus.NewWebhook{
+					UserID: sd.Users[1].ID,
+					URL:    srv.URL,
+					Events: []string{"product.created"},
+				}
+
+				wh, err := busDomain.Webhook.Create(ctx, nw)
+				if err != nil {
+					return err
+				}
+
+				prd := productbus.Product{ID: uuid.New(), UserID: sd.Users[1].ID}
+				if err := busDomain.Webhook.Dispatch(ctx, productbus.ActionChangedData(productbus.ActionCreated, prd)); err != nil {
+					return err
+				}
+
+				dlvs, err := busDomain.Webhook.QueryDeliveries(ctx, wh.ID, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				if len(dlvs) != 1 {
+					return fmt.Errorf("got %d deliveries, exp 1", len(dlvs))
+				}
+
+				if dlvs[0].DateNext.IsZero() {
+					return errors.New("next attempt should be scheduled")
+				}
+
+				// Only deliveries that ran out of attempts can be replayed.
+				if _, err := busDomain.Webhook.Replay(ctx, dlvs[0]); !errors.Is(err, webhookbus.ErrNotFailed) {
+					return fmt.Errorf("replay: got %v, exp %v", err, webhookbus.ErrNotFailed)
+				}
+
+				return toDelivery(dlvs[0])
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,212 @@
+// Package webhookbus provides business access to webhook domain.
+package webhookbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/eventbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound         = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrInvalidEvent     = errors.New("invalid event type")
+	ErrNotFailed        = errors.New("only failed deliveries can be replayed")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, wh Webhook) error
+	Update(ctx context.Context, wh Webhook) error
+	Delete(ctx context.Context, wh Webhook) error
+	QueryByID(ctx context.Context, webhookID uuid.UUID) (Webhook, error)
+	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Webhook, error)
+
+	// QueryByEvent must only return the webhooks that are enabled.
+	QueryByEvent(ctx context.Context, eventType string) ([]Webhook, error)
+
+	CreateDelivery(ctx context.Context, dlv Delivery) error
+	UpdateDelivery(ctx context.Context, dlv Delivery) error
+	QueryDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (Delivery, error)
+	QueryDeliveries(ctx context.Context, webhookID uuid.UUID, page page.Page) ([]Delivery, error)
+	QueryDeliveredWebhookIDs(ctx context.Context, eventID uuid.UUID) ([]uuid.UUID, error)
+	CountDeliveries(ctx context.Context, webhookID uuid.UUID) (int, error)
+	QueryDueDeliveries(ctx context.Context, now time.Time, rows int) ([]Delivery, error)
+}
+
+// Sender declares the behavior needed to send a delivery to a webhook. It
+// returns the status code the webhook responded with.
+type Sender interface {
+	Send(ctx context.Context, url string, header http.Header, body []byte) (int, error)
+}
+
+// Business manages the set of APIs for webhook access.
+type Business struct {
+	log     *logger.Logger
+	userBus *userbus.Business
+	sender  Sender
+	storer  Storer
+}
+
+// NewBusiness constructs a webhook business API for use.
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, sender Sender, storer Storer) *Business {
+	return &Business{
+		log:     log,
+		userBus: userBus,
+		sender:  sender,
+		storer:  storer,
+	}
+}
+
+// Create adds a new webhook to the system with a new secret for signing its
+// deliveries.
+func (b *Business) Create(ctx context.Context, nw NewWebhook) (Webhook, error) {
+	if err := checkEvents(nw.Events); err != nil {
+		return Webhook{}, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return Webhook{}, fmt.Errorf("newsecret: %w", err)
+	}
+
+	now := time.Now()
+
+	wh := Webhook{
+		ID:          uuid.New(),
+		UserID:      nw.UserID,
+		URL:         nw.URL,
+		Secret:      secret,
+		Events:      nw.Events,
+		Enabled:     true,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, wh); err != nil {
+		return Webhook{}, fmt.Errorf("create: %w", err)
+	}
+
+	return wh, nil
+}
+
+// Update modifies information about a webhook.
+func (b *Business) Update(ctx context.Context, wh Webhook, uw UpdateWebhook) (Webhook, error) {
+	if uw.URL != nil {
+		wh.URL = *uw.URL
+	}
+
+	if uw.Events != nil {
+		if err := checkEvents(uw.Events); err != nil {
+			return Webhook{}, err
+		}
+		wh.Events = uw.Events
+	}
+
+	if uw.Enabled != nil {
+		wh.Enabled = *uw.Enabled
+	}
+
+	wh.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, wh); err != nil {
+		return Webhook{}, fmt.Errorf("update: %w", err)
+	}
+
+	return wh, nil
+}
+
+// Delete removes the specified webhook along with its deliveries.
+func (b *Business) Delete(ctx context.Context, wh Webhook) error {
+	if err := b.storer.Delete(ctx, wh); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID finds the webhook by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, webhookID uuid.UUID) (Webhook, error) {
+	wh, err := b.storer.QueryByID(ctx, webhookID)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("query: webhookID[%s]: %w", webhookID, err)
+	}
+
+	return wh, nil
+}
+
+// QueryByUserID finds the webhooks registered by the specified user.
+func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Webhook, error) {
+	whs, err := b.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return whs, nil
+}
+
+// QueryDeliveryByID finds the delivery by the specified ID.
+func (b *Business) QueryDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (Delivery, error) {
+	dlv, err := b.storer.QueryDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("query: deliveryID[%s]: %w", deliveryID, err)
+	}
+
+	return dlv, nil
+}
+
+// QueryDeliveries retrieves the deliveries made to the specified webhook,
+// newest first.
+func (b *Business) QueryDeliveries(ctx context.Context, webhookID uuid.UUID, page page.Page) ([]Delivery, error) {
+	dlvs, err := b.storer.QueryDeliveries(ctx, webhookID, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: webhookID[%s]: %w", webhookID, err)
+	}
+
+	return dlvs, nil
+}
+
+// CountDeliveries returns the number of deliveries made to the specified
+// webhook.
+func (b *Business) CountDeliveries(ctx context.Context, webhookID uuid.UUID) (int, error) {
+	return b.storer.CountDeliveries(ctx, webhookID)
+}
+
+// =============================================================================
+
+// checkEvents makes sure webhooks are only registered for the types of event
+// that are sent to them.
+func checkEvents(events []string) error {
+	types := eventbus.Types()
+
+	for _, event := range events {
+		if !slices.Contains(types, event) {
+			return fmt.Errorf("%w: %q", ErrInvalidEvent, event)
+		}
+	}
+
+	return nil
+}
+
+// newSecret returns a random secret for signing deliveries.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,6 @@
+ALTER TABLE homes ADD COLUMN latitude DOUBLE PRECISION NULL;
+ALTER TABLE homes ADD COLUMN longitude DOUBLE PRECISION NULL;
+
+-- Proximity searches narrow homes down with a bounding box on this index
+-- before computing the exact distance.
+CREATE INDEX homes_location_idx ON homes (latitude, longitude);
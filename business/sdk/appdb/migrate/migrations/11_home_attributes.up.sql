@@ -0,0 +1,5 @@
+ALTER TABLE homes ADD COLUMN bedrooms    INT              NOT NULL DEFAULT 0;
+ALTER TABLE homes ADD COLUMN bathrooms   DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE homes ADD COLUMN square_feet INT              NOT NULL DEFAULT 0;
+ALTER TABLE homes ADD COLUMN year_built  INT              NOT NULL DEFAULT 0;
+ALTER TABLE homes ADD COLUMN units       INT              NOT NULL DEFAULT 0;
@@ -0,0 +1,14 @@
+CREATE TABLE events (
+	event_id     BIGSERIAL NOT NULL,
+	domain       TEXT      NOT NULL,
+	action       TEXT      NOT NULL,
+	entity_id    UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	data         JSONB     NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (event_id)
+);
+
+-- Users following the stream only read the events of what they own.
+CREATE INDEX events_user_id_idx ON events (user_id, event_id);
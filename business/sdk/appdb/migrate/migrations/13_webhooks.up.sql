@@ -0,0 +1,38 @@
+CREATE TABLE webhooks (
+	webhook_id   UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	url          TEXT      NOT NULL,
+	secret       TEXT      NOT NULL,
+	events       TEXT[]    NOT NULL,
+	enabled      BOOLEAN   NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (webhook_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhooks_user_id_idx ON webhooks (user_id);
+
+CREATE TABLE webhook_deliveries (
+	delivery_id  UUID      NOT NULL,
+	webhook_id   UUID      NOT NULL,
+	event_id     UUID      NOT NULL,
+	event_type   TEXT      NOT NULL,
+	payload      JSONB     NOT NULL,
+	status       TEXT      NOT NULL,
+	attempts     INT       NOT NULL,
+	status_code  INT       NOT NULL,
+	error        TEXT      NOT NULL,
+	date_next    TIMESTAMP NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (delivery_id),
+	FOREIGN KEY (webhook_id) REFERENCES webhooks(webhook_id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, date_created);
+
+-- Pending deliveries are looked up by the job that retries them.
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (date_next) WHERE status = 'PENDING';
@@ -0,0 +1,25 @@
+CREATE TABLE outbox (
+	event_id     UUID      NOT NULL,
+	domain       TEXT      NOT NULL,
+	action       TEXT      NOT NULL,
+	params       JSONB     NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_sent    TIMESTAMP NULL,
+
+	PRIMARY KEY (event_id)
+);
+
+-- Unsent events are looked up by the relay that publishes them.
+CREATE INDEX outbox_unsent_idx ON outbox (date_created) WHERE date_sent IS NULL;
+
+-- The events each consumer has handled, so the ones published again can be
+-- dropped.
+CREATE TABLE outbox_claims (
+	consumer     TEXT      NOT NULL,
+	event_id     UUID      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (consumer, event_id)
+);
+
+CREATE INDEX outbox_claims_date_created_idx ON outbox_claims (date_created);
@@ -0,0 +1,19 @@
+-- The events the delegate functions failed to handle, kept so they can be
+-- inspected and replayed.
+CREATE TABLE dead_letters (
+	dead_letter_id UUID      NOT NULL,
+	event_id       UUID      NOT NULL,
+	domain         TEXT      NOT NULL,
+	action         TEXT      NOT NULL,
+	params         JSONB     NOT NULL,
+	target         TEXT      NOT NULL,
+	attempts       INT       NOT NULL,
+	error          TEXT      NOT NULL,
+	date_created   TIMESTAMP NOT NULL,
+	date_updated   TIMESTAMP NOT NULL,
+	date_replayed  TIMESTAMP NULL,
+
+	PRIMARY KEY (dead_letter_id)
+);
+
+CREATE INDEX dead_letters_date_created_idx ON dead_letters (date_created);
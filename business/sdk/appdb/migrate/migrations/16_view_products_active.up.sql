@@ -0,0 +1,18 @@
+-- The view carries the status of the product so inactive products can be
+-- left out of its queries.
+CREATE OR REPLACE VIEW view_products AS
+SELECT
+    p.product_id,
+    p.user_id,
+	p.name,
+    p.cost,
+	p.quantity,
+    p.date_created,
+    p.date_updated,
+    u.name AS user_name,
+    p.search,
+    p.active
+FROM
+    products AS p
+JOIN
+    users AS u ON u.user_id = p.user_id;
@@ -0,0 +1,9 @@
+-- The id of the delegate event an event was recorded from, so an event
+-- delivered more than once is only recorded once.
+ALTER TABLE events ADD COLUMN source_id UUID NULL;
+
+CREATE UNIQUE INDEX events_source_id_idx ON events (source_id);
+
+-- The deliveries of an event are looked up, so an event delivered more than
+-- once is only sent to each webhook once.
+CREATE INDEX webhook_deliveries_event_id_idx ON webhook_deliveries (event_id);
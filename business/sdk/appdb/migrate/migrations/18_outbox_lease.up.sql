@@ -0,0 +1,3 @@
+-- The date until which a relay holds the unsent event, so relays running at
+-- the same time don't publish the same events.
+ALTER TABLE outbox ADD COLUMN date_leased TIMESTAMP NULL;
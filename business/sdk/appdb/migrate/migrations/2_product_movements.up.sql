@@ -0,0 +1,23 @@
+CREATE TABLE product_movements (
+	movement_id  UUID      NOT NULL,
+	product_id   UUID      NOT NULL,
+	type         TEXT      NOT NULL,
+	quantity     INT       NOT NULL,
+	reason       TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (movement_id),
+	FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE
+);
+
+CREATE INDEX product_movements_product_id_idx ON product_movements (product_id, date_created);
+
+-- Record the existing stock as an opening receipt so every product's
+-- quantity reconciles with its ledger.
+INSERT INTO product_movements (movement_id, product_id, type, quantity, reason, date_created)
+SELECT
+	gen_random_uuid(), product_id, 'RECEIPT', quantity, 'opening balance', date_created
+FROM
+	products
+WHERE
+	quantity <> 0;
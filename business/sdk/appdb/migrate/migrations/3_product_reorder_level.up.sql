@@ -0,0 +1,3 @@
+ALTER TABLE products
+	ADD COLUMN reorder_level     INT     NOT NULL DEFAULT 0,
+	ADD COLUMN low_stock_alerted BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,44 @@
+CREATE TABLE categories (
+	category_id  UUID      NOT NULL,
+	parent_id    UUID      NULL,
+	name         TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (category_id),
+	FOREIGN KEY (parent_id) REFERENCES categories(category_id) ON DELETE SET NULL
+);
+
+-- Category names only need to be unique among their siblings.
+CREATE UNIQUE INDEX categories_parent_id_name_idx ON categories (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), name);
+
+CREATE TABLE tags (
+	tag_id       UUID        NOT NULL,
+	name         TEXT UNIQUE NOT NULL,
+	date_created TIMESTAMP   NOT NULL,
+	date_updated TIMESTAMP   NOT NULL,
+
+	PRIMARY KEY (tag_id)
+);
+
+CREATE TABLE product_categories (
+	product_id  UUID NOT NULL,
+	category_id UUID NOT NULL,
+
+	PRIMARY KEY (product_id, category_id),
+	FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE,
+	FOREIGN KEY (category_id) REFERENCES categories(category_id) ON DELETE CASCADE
+);
+
+CREATE INDEX product_categories_category_id_idx ON product_categories (category_id);
+
+CREATE TABLE product_tags (
+	product_id UUID NOT NULL,
+	tag_id     UUID NOT NULL,
+
+	PRIMARY KEY (product_id, tag_id),
+	FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE,
+	FOREIGN KEY (tag_id) REFERENCES tags(tag_id) ON DELETE CASCADE
+);
+
+CREATE INDEX product_tags_tag_id_idx ON product_tags (tag_id);
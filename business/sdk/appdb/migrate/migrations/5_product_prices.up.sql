@@ -0,0 +1,24 @@
+CREATE TABLE product_prices (
+	price_id       UUID           NOT NULL,
+	product_id     UUID           NOT NULL,
+	cost           NUMERIC(10, 2) NOT NULL,
+	date_effective TIMESTAMP      NOT NULL,
+	date_applied   TIMESTAMP      NULL,
+	date_created   TIMESTAMP      NOT NULL,
+
+	PRIMARY KEY (price_id),
+	FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE
+);
+
+CREATE INDEX product_prices_product_id_idx ON product_prices (product_id, date_effective);
+
+-- Scheduled price changes are looked up by the job that applies them.
+CREATE INDEX product_prices_pending_idx ON product_prices (date_effective) WHERE date_applied IS NULL;
+
+-- Record the current cost of existing products as the start of their
+-- price history.
+INSERT INTO product_prices (price_id, product_id, cost, date_effective, date_applied, date_created)
+SELECT
+	gen_random_uuid(), product_id, cost, date_created, date_created, date_created
+FROM
+	products;
@@ -0,0 +1,6 @@
+ALTER TABLE products ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Products owned by users that are already disabled start out inactive.
+UPDATE products SET active = FALSE WHERE user_id IN (SELECT user_id FROM users WHERE NOT enabled);
+
+CREATE INDEX products_user_id_idx ON products (user_id);
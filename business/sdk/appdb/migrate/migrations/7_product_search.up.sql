@@ -0,0 +1,34 @@
+ALTER TABLE products ADD COLUMN search TSVECTOR;
+
+-- The search document is built from the product name. Other text such as a
+-- description or tags can be added here with a lower weight.
+CREATE FUNCTION products_search_document() RETURNS TRIGGER AS $$
+BEGIN
+	NEW.search := setweight(to_tsvector('english', COALESCE(NEW.name, '')), 'A');
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_search_update
+BEFORE INSERT OR UPDATE OF name ON products
+FOR EACH ROW EXECUTE FUNCTION products_search_document();
+
+UPDATE products SET search = setweight(to_tsvector('english', COALESCE(name, '')), 'A');
+
+CREATE INDEX products_search_idx ON products USING GIN (search);
+
+CREATE OR REPLACE VIEW view_products AS
+SELECT
+    p.product_id,
+    p.user_id,
+	p.name,
+    p.cost,
+	p.quantity,
+    p.date_created,
+    p.date_updated,
+    u.name AS user_name,
+    p.search
+FROM
+    products AS p
+JOIN
+    users AS u ON u.user_id = p.user_id;
@@ -0,0 +1,12 @@
+CREATE TABLE home_members (
+	home_id      UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	role         TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (home_id, user_id),
+	FOREIGN KEY (home_id) REFERENCES homes(home_id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX home_members_user_id_idx ON home_members (user_id);
@@ -0,0 +1,17 @@
+CREATE TABLE home_transfers (
+	transfer_id   UUID      NOT NULL,
+	home_id       UUID      NOT NULL,
+	from_user_id  UUID      NOT NULL,
+	to_user_id    UUID      NOT NULL,
+	date_created  TIMESTAMP NOT NULL,
+	date_expires  TIMESTAMP NOT NULL,
+	date_accepted TIMESTAMP NULL,
+
+	PRIMARY KEY (transfer_id),
+	FOREIGN KEY (home_id) REFERENCES homes(home_id) ON DELETE CASCADE,
+	FOREIGN KEY (from_user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+	FOREIGN KEY (to_user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- A home can only have one transfer waiting to be accepted.
+CREATE UNIQUE INDEX home_transfers_pending_idx ON home_transfers (home_id) WHERE date_accepted IS NULL;
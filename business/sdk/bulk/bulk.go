@@ -0,0 +1,63 @@
+// Package bulk provides support for working with many items in one call.
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ItemError is the error of a single item of a bulk call. Index is the
+// position of the item in the call.
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+// Error implements the error interface.
+func (ie ItemError) Error() string {
+	return fmt.Sprintf("item[%d]: %s", ie.Index, ie.Err)
+}
+
+// Unwrap returns the error of the item.
+func (ie ItemError) Unwrap() error {
+	return ie.Err
+}
+
+// Errors holds the errors of every item that failed in a bulk call.
+type Errors []ItemError
+
+// Add records the error of the item at the index.
+func (e *Errors) Add(index int, err error) {
+	*e = append(*e, ItemError{Index: index, Err: err})
+}
+
+// Error implements the error interface.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, ie := range e {
+		msgs[i] = ie.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Indexes returns the set of item indexes that failed.
+func (e Errors) Indexes() map[int]bool {
+	m := make(map[int]bool, len(e))
+	for _, ie := range e {
+		m[ie.Index] = true
+	}
+
+	return m
+}
+
+// AsErrors returns the item errors held by err.
+func AsErrors(err error) (Errors, bool) {
+	var e Errors
+	if !errors.As(err, &e) {
+		return nil, false
+	}
+
+	return e, true
+}
@@ -2,20 +2,37 @@
 package dbtest
 
 import (
+	"bytes"
 	"context"
+	_ "embed"
 	"testing"
 	"time"
 
 	esqldb "encore.dev/storage/sqldb"
+	"github.com/ardanlabs/encore/business/domain/categorybus"
+	"github.com/ardanlabs/encore/business/domain/categorybus/stores/categorydb"
+	"github.com/ardanlabs/encore/business/domain/deadletterbus"
+	"github.com/ardanlabs/encore/business/domain/deadletterbus/stores/deadletterdb"
+	"github.com/ardanlabs/encore/business/domain/eventbus"
+	"github.com/ardanlabs/encore/business/domain/eventbus/stores/eventdb"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/homebus/address"
+	"github.com/ardanlabs/encore/business/domain/homebus/geocode"
 	"github.com/ardanlabs/encore/business/domain/homebus/stores/homedb"
+	"github.com/ardanlabs/encore/business/domain/outboxbus"
+	"github.com/ardanlabs/encore/business/domain/outboxbus/stores/outboxdb"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/productbus/stores/productdb"
+	"github.com/ardanlabs/encore/business/domain/tagbus"
+	"github.com/ardanlabs/encore/business/domain/tagbus/stores/tagdb"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/domain/userbus/stores/usercache"
 	"github.com/ardanlabs/encore/business/domain/userbus/stores/userdb"
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
 	"github.com/ardanlabs/encore/business/domain/vproductbus/stores/vproductdb"
+	"github.com/ardanlabs/encore/business/domain/webhookbus"
+	"github.com/ardanlabs/encore/business/domain/webhookbus/sender"
+	"github.com/ardanlabs/encore/business/domain/webhookbus/stores/webhookdb"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/foundation/logger"
@@ -24,26 +41,62 @@ import (
 
 // BusDomain represents all the business domain apis needed for testing.
 type BusDomain struct {
-	Delegate *delegate.Delegate
-	Home     *homebus.Business
-	Product  *productbus.Business
-	User     *userbus.Business
-	VProduct *vproductbus.Business
+	Delegate   *delegate.Delegate
+	Category   *categorybus.Business
+	DeadLetter *deadletterbus.Business
+	Event      *eventbus.Business
+	Home       *homebus.Business
+	Outbox     *outboxbus.Business
+	Product    *productbus.Business
+	Tag        *tagbus.Business
+	User       *userbus.Business
+	VProduct   *vproductbus.Business
+	Webhook    *webhookbus.Business
 }
 
-func newBusDomains(log *logger.Logger, db *sqlx.DB) BusDomain {
-	delegate := delegate.New(log)
-	userBus := userbus.NewBusiness(log, delegate, usercache.NewStore(log, userdb.NewStore(log, db), time.Hour))
-	productBus := productbus.NewBusiness(log, userBus, delegate, productdb.NewStore(log, db))
-	homeBus := homebus.NewBusiness(log, userBus, delegate, homedb.NewStore(log, db))
+// delegatePublisher calls the delegate functions for the events relayed from
+// the outbox, since the pubsub subscriptions that would call them don't run
+// in tests.
+type delegatePublisher struct {
+	delegate *delegate.Delegate
+}
+
+// Publish implements the outboxbus.Publisher interface.
+func (p delegatePublisher) Publish(ctx context.Context, data delegate.Data) (string, error) {
+	return data.ID.String(), p.delegate.Call(ctx, data)
+}
+
+//go:embed geocode.json
+var geocodeFile []byte
+
+func newBusDomains(log *logger.Logger, db *sqlx.DB, geocoder homebus.Geocoder) BusDomain {
+	deadLetterBus := deadletterbus.NewBusiness(log, deadletterdb.NewStore(log, db))
+
+	// Without a publisher the asynchronous functions are called like the
+	// others, there's no pubsub subscription to send them to in tests.
+	delegate := delegate.New(log, nil, deadLetterBus)
+	outboxBus := outboxbus.NewBusiness(log, delegatePublisher{delegate: delegate}, outboxdb.NewStore(log, db))
+	userBus := userbus.NewBusiness(log, outboxBus, usercache.NewStore(log, userdb.NewStore(log, db), time.Hour))
+	productBus := productbus.NewBusiness(log, userBus, delegate, outboxBus, productdb.NewStore(log, db))
+	homeBus := homebus.NewBusiness(log, userBus, delegate, outboxBus, address.NewValidator(), geocoder, homedb.NewStore(log, db))
 	vproductBus := vproductbus.NewBusiness(vproductdb.NewStore(log, db))
+	categoryBus := categorybus.NewBusiness(log, categorydb.NewStore(log, db))
+	tagBus := tagbus.NewBusiness(log, tagdb.NewStore(log, db))
+	eventBus := eventbus.NewBusiness(log, delegate, eventdb.NewStore(log, db), eventbus.DefaultSize)
+	webhookBus := webhookbus.NewBusiness(log, userBus, sender.NewHTTP(5*time.Second, sender.WithInsecure()), webhookdb.NewStore(log, db))
 
 	return BusDomain{
-		Delegate: delegate,
-		Home:     homeBus,
-		Product:  productBus,
-		User:     userBus,
-		VProduct: vproductBus,
+		Delegate:   delegate,
+		Category:   categoryBus,
+		DeadLetter: deadLetterBus,
+		Event:      eventBus,
+		Home:       homeBus,
+		Outbox:     outboxBus,
+		Product:    productBus,
+		Tag:        tagBus,
+		User:       userBus,
+		VProduct:   vproductBus,
+		Webhook:    webhookBus,
 	}
 }
 
@@ -53,6 +106,7 @@ func newBusDomains(log *logger.Logger, db *sqlx.DB) BusDomain {
 type Database struct {
 	DB        *sqlx.DB
 	Log       *logger.Logger
+	Geocoder  homebus.Geocoder
 	BusDomain BusDomain
 }
 
@@ -85,10 +139,16 @@ func NewDatabase(t *testing.T, edb *esqldb.Database) *Database {
 
 	log := logger.New("test")
 
+	geocoder, err := geocode.NewFile(bytes.NewReader(geocodeFile))
+	if err != nil {
+		t.Fatalf("loading geocode file: %v", err)
+	}
+
 	return &Database{
 		Log:       log,
 		DB:        db,
-		BusDomain: newBusDomains(log, db),
+		Geocoder:  geocoder,
+		BusDomain: newBusDomains(log, db, geocoder),
 	}
 }
 
@@ -4,10 +4,17 @@ package delegate
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/ardanlabs/encore/foundation/logger"
 )
 
+// ErrUnknownTarget is used when an event is sent to a function that isn't
+// registered for its domain and action.
+var ErrUnknownTarget = errors.New("unknown target")
+
 // These types are just for documentation so we know what keys go
 // where in the map.
 type (
@@ -15,51 +22,193 @@ type (
 	action string
 )
 
+// Publisher declares the behavior needed to publish the events for the
+// functions registered to run asynchronously. The delegate pubsub topic
+// implements it.
+type Publisher interface {
+	Publish(ctx context.Context, data Data) (string, error)
+}
+
+// DeadLetterer declares the behavior needed to keep the events a function
+// still failed to handle after its last attempt.
+type DeadLetterer interface {
+	DeadLetter(ctx context.Context, data Data, attempts int, cause error) error
+}
+
 // Delegate manages the set of functions to be called by domain
 // packages when an import is not possible.
 type Delegate struct {
-	log   *logger.Logger
-	funcs map[domain]map[action][]Func
+	log         *logger.Logger
+	publisher   Publisher
+	deadLetters DeadLetterer
+	funcs       map[domain]map[action][]registration
 }
 
-// New constructs a delegate for indirect api access.
-func New(log *logger.Logger) *Delegate {
+// New constructs a delegate for indirect api access. Without a publisher the
+// functions registered to run asynchronously are called like the others,
+// and without a dead letterer the events that failed are only logged.
+func New(log *logger.Logger, publisher Publisher, deadLetters DeadLetterer) *Delegate {
 	return &Delegate{
-		log:   log,
-		funcs: make(map[domain]map[action][]Func),
+		log:         log,
+		publisher:   publisher,
+		deadLetters: deadLetters,
+		funcs:       make(map[domain]map[action][]registration),
 	}
 }
 
 // Register adds a function to be called for a specified domain and action.
-func (d *Delegate) Register(domainType string, actionType string, fn Func) {
+// The name identifies the function as the target of the events sent to it
+// alone, which are kept in pubsub and in the dead letters, so it must stay
+// the same across releases and be unique for the domain and action. By
+// default the function is called synchronously with the DefaultRetry
+// policy, which the options can change.
+func (d *Delegate) Register(domainType string, actionType string, name string, fn Func, opts ...Option) {
+	reg := registration{
+		target: name,
+		fn:     fn,
+		mode:   Sync,
+		retry:  DefaultRetry,
+	}
+
+	for _, opt := range opts {
+		opt(&reg)
+	}
+
 	aMap, ok := d.funcs[domain(domainType)]
 	if !ok {
-		aMap = make(map[action][]Func)
+		aMap = make(map[action][]registration)
 		d.funcs[domain(domainType)] = aMap
 	}
 
-	funcs := aMap[action(actionType)]
-	funcs = append(funcs, fn)
-	aMap[action(actionType)] = funcs
+	regs := aMap[action(actionType)]
+	regs = append(regs, reg)
+	aMap[action(actionType)] = regs
 }
 
 // Call executes all functions registered for the specified domain and
-// action. These functions are executed synchronously on the G making the call.
+// action. The synchronous functions are executed on the G making the call
+// and retried under their policy, while the asynchronous ones are sent a
+// copy of the event through the publisher. An event with a target is only
+// handled by the function it names. A function that runs out of attempts
+// has the event dead lettered, so the error returned only reports the events
+// that couldn't be published or dead lettered.
 func (d *Delegate) Call(ctx context.Context, data Data) error {
-	d.log.Info(ctx, "delegate call", "status", "started", "domain", data.Domain, "action", data.Action, "params", data.RawParams)
+	d.log.Info(ctx, "delegate call", "status", "started", "domain", data.Domain, "action", data.Action, "target", data.Target, "params", data.RawParams)
 	defer d.log.Info(ctx, "delegate call", "status", "completed")
 
-	if dMap, ok := d.funcs[domain(data.Domain)]; ok {
-		if funcs, ok := dMap[action(data.Action)]; ok {
-			for _, fn := range funcs {
-				d.log.Info(ctx, "delegate call", "status", "sending")
+	regs := d.funcs[domain(data.Domain)][action(data.Action)]
+
+	if data.Target != "" {
+		reg, ok := find(regs, data.Target)
+		if !ok {
+			d.log.Error(ctx, "delegate call", "target", data.Target, "msg", ErrUnknownTarget)
+			return d.deadLetter(ctx, data, 0, ErrUnknownTarget)
+		}
+
+		return d.run(ctx, reg, data)
+	}
+
+	var errs []error
+	for _, reg := range regs {
+		if reg.mode == Async && d.publisher != nil {
+			d.log.Info(ctx, "delegate call", "status", "publishing", "target", reg.target)
+
+			msg := data
+			msg.Target = reg.target
 
-				if err := fn(ctx, data); err != nil {
-					d.log.Error(ctx, "delegate call", "msg", err)
-				}
+			if _, err := d.publisher.Publish(ctx, msg); err != nil {
+				errs = append(errs, fmt.Errorf("publish: target[%s]: %w", reg.target, err))
 			}
+			continue
+		}
+
+		if err := d.run(ctx, reg, data); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
+	return errors.Join(errs...)
+}
+
+// Replay calls the function named by the target of the event once more,
+// for events that were dead lettered. The event isn't dead lettered again
+// when the function fails, the error is returned instead.
+func (d *Delegate) Replay(ctx context.Context, data Data) error {
+	reg, ok := find(d.funcs[domain(data.Domain)][action(data.Action)], data.Target)
+	if !ok {
+		return fmt.Errorf("target[%s]: %w", data.Target, ErrUnknownTarget)
+	}
+
+	d.log.Info(ctx, "delegate call", "status", "replaying", "target", reg.target, "id", data.ID)
+
+	return reg.fn(ctx, data)
+}
+
+// run calls the function until it succeeds or runs out of attempts, waiting
+// longer after every failure. The event is dead lettered when the last
+// attempt fails.
+func (d *Delegate) run(ctx context.Context, reg registration, data Data) error {
+	d.log.Info(ctx, "delegate call", "status", "sending", "target", reg.target)
+
+	backoff := reg.retry.MinBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := reg.fn(ctx, data)
+		if err == nil {
+			return nil
+		}
+
+		d.log.Error(ctx, "delegate call", "target", reg.target, "attempt", attempt, "msg", err)
+
+		if attempt >= reg.retry.Attempts {
+			data.Target = reg.target
+			return d.deadLetter(ctx, data, attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			data.Target = reg.target
+			return d.deadLetter(ctx, data, attempt, err)
+		case <-time.After(backoff):
+		}
+
+		backoff = min(2*backoff, reg.retry.MaxBackoff)
+	}
+}
+
+func (d *Delegate) deadLetter(ctx context.Context, data Data, attempts int, cause error) error {
+	if d.deadLetters == nil {
+		return nil
+	}
+
+	// The event is kept even when the caller has given up waiting on it.
+	ctx = context.WithoutCancel(ctx)
+
+	if err := d.deadLetters.DeadLetter(ctx, data, attempts, cause); err != nil {
+		return fmt.Errorf("deadletter: target[%s]: %w", data.Target, err)
+	}
+
+	d.log.Info(ctx, "delegate call", "status", "dead lettered", "target", data.Target, "attempts", attempts)
+
 	return nil
 }
+
+// =============================================================================
+
+// registration holds a function along with how it's called.
+type registration struct {
+	target string
+	fn     Func
+	mode   Mode
+	retry  Retry
+}
+
+func find(regs []registration, target string) (registration, bool) {
+	for _, reg := range regs {
+		if reg.target == target {
+			return reg, true
+		}
+	}
+
+	return registration{}, false
+}
@@ -0,0 +1,174 @@
+package delegate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	domain = "test"
+	action = "changed"
+)
+
+// publisher keeps the events it's asked to publish.
+type publisher struct {
+	msgs []delegate.Data
+}
+
+func (p *publisher) Publish(ctx context.Context, data delegate.Data) (string, error) {
+	p.msgs = append(p.msgs, data)
+	return "", nil
+}
+
+// deadLetter holds what the dead letterer was given.
+type deadLetter struct {
+	Target   string
+	Attempts int
+	Cause    string
+}
+
+// deadLetterer keeps the events it's asked to dead letter.
+type deadLetterer struct {
+	dls []deadLetter
+}
+
+func (d *deadLetterer) DeadLetter(ctx context.Context, data delegate.Data, attempts int, cause error) error {
+	d.dls = append(d.dls, deadLetter{Target: data.Target, Attempts: attempts, Cause: cause.Error()})
+	return nil
+}
+
+// calls records the names of the functions called.
+type calls []string
+
+func (c *calls) fn(name string) delegate.Func {
+	return func(ctx context.Context, data delegate.Data) error {
+		*c = append(*c, name)
+		return nil
+	}
+}
+
+// =============================================================================
+
+func Test_Routing(t *testing.T) {
+	var pub publisher
+	var got calls
+
+	dlg := delegate.New(logger.New("test"), &pub, nil)
+	dlg.Register(domain, action, "sync", got.fn("sync"))
+	dlg.Register(domain, action, "async", got.fn("async"), delegate.WithMode(delegate.Async))
+	dlg.Register(domain, "other", "other", got.fn("other"))
+
+	data := delegate.Data{Domain: domain, Action: action, RawParams: []byte(`{}`)}
+
+	if err := dlg.Call(context.Background(), data); err != nil {
+		t.Fatalf("Should be able to call the functions: %s", err)
+	}
+
+	if diff := cmp.Diff([]string(got), []string{"sync"}); diff != "" {
+		t.Errorf("Should only call the sync function of the action:\n%s", diff)
+	}
+
+	exp := data
+	exp.Target = "async"
+
+	if diff := cmp.Diff(pub.msgs, []delegate.Data{exp}); diff != "" {
+		t.Errorf("Should publish a copy of the event for the async function:\n%s", diff)
+	}
+}
+
+func Test_Target(t *testing.T) {
+	var pub publisher
+	var dlr deadLetterer
+	var got calls
+
+	dlg := delegate.New(logger.New("test"), &pub, &dlr)
+	dlg.Register(domain, action, "sync", got.fn("sync"))
+	dlg.Register(domain, action, "async", got.fn("async"), delegate.WithMode(delegate.Async))
+
+	// The copy published for the async function is handled by it alone.
+	data := delegate.Data{Domain: domain, Action: action, RawParams: []byte(`{}`), Target: "async"}
+
+	if err := dlg.Call(context.Background(), data); err != nil {
+		t.Fatalf("Should be able to call the target: %s", err)
+	}
+
+	if diff := cmp.Diff([]string(got), []string{"async"}); diff != "" {
+		t.Errorf("Should only call the target function:\n%s", diff)
+	}
+
+	if len(pub.msgs) != 0 {
+		t.Errorf("Should not publish an event with a target: got %d", len(pub.msgs))
+	}
+
+	// An event for a function that isn't registered is dead lettered.
+	data.Target = "unknown"
+
+	if err := dlg.Call(context.Background(), data); err != nil {
+		t.Fatalf("Should be able to dead letter an unknown target: %s", err)
+	}
+
+	exp := []deadLetter{{Target: "unknown", Attempts: 0, Cause: delegate.ErrUnknownTarget.Error()}}
+	if diff := cmp.Diff(dlr.dls, exp); diff != "" {
+		t.Errorf("Should dead letter the event for the unknown target:\n%s", diff)
+	}
+
+	if err := dlg.Replay(context.Background(), data); !errors.Is(err, delegate.ErrUnknownTarget) {
+		t.Errorf("Should not replay an unknown target: got %v", err)
+	}
+}
+
+func Test_Retry(t *testing.T) {
+	var dlr deadLetterer
+
+	retry := delegate.Retry{
+		Attempts:   4,
+		MinBackoff: 20 * time.Millisecond,
+		MaxBackoff: 30 * time.Millisecond,
+	}
+
+	// The function fails until its fourth attempt when flaky, and always
+	// when broken.
+	var flaky, broken []time.Time
+	fail := func(times *[]time.Time, succeedAt int) delegate.Func {
+		return func(ctx context.Context, data delegate.Data) error {
+			*times = append(*times, time.Now())
+			if len(*times) == succeedAt {
+				return nil
+			}
+			return errors.New("failed")
+		}
+	}
+
+	dlg := delegate.New(logger.New("test"), nil, &dlr)
+	dlg.Register(domain, action, "flaky", fail(&flaky, 4), delegate.WithRetry(retry))
+	dlg.Register(domain, action, "broken", fail(&broken, 0), delegate.WithRetry(retry))
+
+	data := delegate.Data{Domain: domain, Action: action, RawParams: []byte(`{}`)}
+
+	if err := dlg.Call(context.Background(), data); err != nil {
+		t.Fatalf("Should be able to call the functions: %s", err)
+	}
+
+	if len(flaky) != 4 || len(broken) != 4 {
+		t.Fatalf("Should call the functions %d times: got %d and %d", retry.Attempts, len(flaky), len(broken))
+	}
+
+	// The wait doubles after every failure up to the maximum.
+	waits := []time.Duration{20 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond}
+	for i, wait := range waits {
+		if got := broken[i+1].Sub(broken[i]); got < wait {
+			t.Errorf("Should wait at least %v before attempt %d: got %v", wait, i+2, got)
+		}
+	}
+
+	exp := []deadLetter{{Target: "broken", Attempts: 4, Cause: "failed"}}
+	if diff := cmp.Diff(dlr.dls, exp); diff != "" {
+		t.Errorf("Should only dead letter the event of the function that ran out of attempts:\n%s", diff)
+	}
+}
@@ -3,22 +3,79 @@ package delegate
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 // Func represents a function that is registered and called by the system.
 type Func func(context.Context, Data) error
 
-// Data represents an event between domains.
+// Data represents an event between domains. ID is set for events sent
+// through the outbox and stays the same when an event is delivered again,
+// so consumers can drop the ones they have already handled. Target names
+// the one registered function the event is for, it's set on the copies sent
+// to asynchronous functions and on the events that were dead lettered.
 type Data struct {
+	ID        uuid.UUID
 	Domain    string
 	Action    string
 	RawParams []byte
+	Target    string
 }
 
 // String implements the Stringer interface.
 func (d Data) String() string {
 	return fmt.Sprintf(
-		"Event{Domain:%#v, Action:%#v, RawParams:%#v}",
-		d.Domain, d.Action, string(d.RawParams),
+		"Event{ID:%v, Domain:%#v, Action:%#v, Target:%#v, RawParams:%#v}",
+		d.ID, d.Domain, d.Action, d.Target, string(d.RawParams),
 	)
 }
+
+// =============================================================================
+
+// Mode represents how a registered function is called.
+type Mode int
+
+// Set of modes a function can be called in.
+const (
+	// Sync calls the function on the G making the delegate call.
+	Sync Mode = iota
+
+	// Async sends the function its own copy of the event through the
+	// publisher, so it's called apart from the other functions.
+	Async
+)
+
+// Retry represents how many times a function is called for an event before
+// the event is dead lettered, and how long to wait between the attempts. The
+// wait starts at the minimum backoff and doubles up to the maximum.
+type Retry struct {
+	Attempts   int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetry is the policy used when a function is registered without one.
+var DefaultRetry = Retry{
+	Attempts:   3,
+	MinBackoff: 100 * time.Millisecond,
+	MaxBackoff: 2 * time.Second,
+}
+
+// Option represents a setting for a registered function.
+type Option func(*registration)
+
+// WithMode sets how the function is called.
+func WithMode(mode Mode) Option {
+	return func(reg *registration) {
+		reg.mode = mode
+	}
+}
+
+// WithRetry sets the retry policy of the function.
+func WithRetry(retry Retry) Option {
+	return func(reg *registration) {
+		reg.retry = retry
+	}
+}
@@ -0,0 +1,73 @@
+// Package fieldset provides support for limiting the fields a query returns.
+package fieldset
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Set represents the fields a query returns. The zero value holds every
+// field, so a query only returns less when it's asked to.
+type Set struct {
+	fields []string
+}
+
+// New constructs a set holding the fields. A set constructed without fields
+// holds every field.
+func New(fields ...string) Set {
+	fields = slices.Clone(fields)
+	slices.Sort(fields)
+
+	return Set{
+		fields: slices.Compact(fields),
+	}
+}
+
+// Parse parses a comma separated list of field names. Each name is mapped
+// through fieldMappings, which holds the names a caller can use, the same way
+// order.Parse maps the fields of an ordering. An empty value holds every
+// field.
+func Parse(fieldMappings map[string]string, value string) (Set, error) {
+	if value == "" {
+		return Set{}, nil
+	}
+
+	var fields []string
+	for name := range strings.SplitSeq(value, ",") {
+		field, exists := fieldMappings[strings.TrimSpace(name)]
+		if !exists {
+			return Set{}, fmt.Errorf("unknown field %q", name)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return New(fields...), nil
+}
+
+// IsAll reports whether the set holds every field.
+func (s Set) IsAll() bool {
+	return len(s.fields) == 0
+}
+
+// Contains reports whether the field is in the set.
+func (s Set) Contains(field string) bool {
+	return s.IsAll() || slices.Contains(s.fields, field)
+}
+
+// With returns a set that also holds the fields. Adding fields to the set of
+// every field leaves it unchanged.
+func (s Set) With(fields ...string) Set {
+	if s.IsAll() {
+		return s
+	}
+
+	return New(slices.Concat(s.fields, fields)...)
+}
+
+// Fields returns the fields in the set in sorted order. The set of every
+// field returns nil.
+func (s Set) Fields() []string {
+	return slices.Clone(s.fields)
+}
@@ -0,0 +1,152 @@
+// Package filter provides support for filtering query results with a shared
+// grammar of comparison operators.
+//
+// A field is compared for equality with `cost=10` and with any other operator
+// by naming it in brackets, such as `cost[gte]=10&cost[lt]=50`. The in
+// operator takes a comma separated list of values, `id[in]=a,b,c`, and the
+// between operator takes an inclusive lower and upper bound,
+// `cost[between]=10,50`.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MaxValues is the most values an in operator can be given.
+const MaxValues = 100
+
+// Set of error variables for parsing conditions.
+var (
+	ErrOperatorNotAllowed = errors.New("operator not allowed")
+	ErrInvalidValue       = errors.New("invalid value")
+)
+
+// Condition compares a field against its values. The in operator matches
+// any of the values, the between operator holds an inclusive lower and upper
+// bound, and every other operator holds exactly one value. A filter with
+// several conditions for the same field matches when all of them hold.
+type Condition[T any] struct {
+	Operator Operator
+	Values   []T
+}
+
+// Compare returns a condition comparing a field with the value using one of
+// the operators that take a single value.
+func Compare[T any](op Operator, value T) Condition[T] {
+	return Condition[T]{
+		Operator: op,
+		Values:   []T{value},
+	}
+}
+
+// In returns a condition matching a field equal to any of the values.
+func In[T any](values ...T) Condition[T] {
+	return Condition[T]{
+		Operator: Operators.IN,
+		Values:   values,
+	}
+}
+
+// Between returns a condition matching a field inside the inclusive range.
+func Between[T any](lower T, upper T) Condition[T] {
+	return Condition[T]{
+		Operator: Operators.BETWEEN,
+		Values:   []T{lower, upper},
+	}
+}
+
+// Params holds the raw values given for a field keyed by operator. The value
+// of a bare field, such as `cost=10`, is keyed by the eq operator.
+type Params map[Operator]string
+
+// Field describes a field that can be filtered on, the operators it allows
+// and how its values are parsed.
+type Field[T any] struct {
+	Name      string
+	Operators []Operator
+	Parse     func(string) (T, error)
+}
+
+// Key returns the name of the query string for the operator on the field.
+func (f Field[T]) Key(op Operator) string {
+	if op == Operators.EQ {
+		return f.Name
+	}
+
+	return fmt.Sprintf("%s[%s]", f.Name, op)
+}
+
+// Conditions parses the raw values given for the field into conditions.
+// Operators without a value are skipped, and an operator the field doesn't
+// allow is an error.
+func (f Field[T]) Conditions(params Params) ([]Condition[T], error) {
+	var conds []Condition[T]
+
+	for _, op := range known {
+		raw := params[op]
+		if raw == "" {
+			continue
+		}
+
+		if !slices.Contains(f.Operators, op) {
+			return nil, fmt.Errorf("%s: %w", f.Key(op), ErrOperatorNotAllowed)
+		}
+
+		values, err := f.values(op, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Key(op), err)
+		}
+
+		conds = append(conds, Condition[T]{
+			Operator: op,
+			Values:   values,
+		})
+	}
+
+	return conds, nil
+}
+
+func (f Field[T]) values(op Operator, raw string) ([]T, error) {
+	parts := []string{raw}
+
+	switch op {
+	case Operators.IN:
+		parts = strings.Split(raw, ",")
+		if len(parts) > MaxValues {
+			return nil, fmt.Errorf("at most %d values are allowed", MaxValues)
+		}
+
+	case Operators.BETWEEN:
+		parts = strings.Split(raw, ",")
+		if len(parts) != 2 {
+			return nil, errors.New("must be formatted as lower,upper")
+		}
+	}
+
+	values := make([]T, len(parts))
+	for i, part := range parts {
+		v, err := f.Parse(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("%w %q", ErrInvalidValue, part)
+		}
+		values[i] = v
+	}
+
+	return values, nil
+}
+
+// =============================================================================
+
+// Float parses a value as a float64 for a numeric field.
+func Float(value string) (float64, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+// Int parses a value as an int for a numeric field.
+func Int(value string) (int, error) {
+	return strconv.Atoi(value)
+}
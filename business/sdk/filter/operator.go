@@ -0,0 +1,101 @@
+package filter
+
+import "fmt"
+
+type operatorSet struct {
+	EQ      Operator
+	NE      Operator
+	GT      Operator
+	GTE     Operator
+	LT      Operator
+	LTE     Operator
+	IN      Operator
+	BETWEEN Operator
+}
+
+// Operators represents the set of operators that can be used to compare a
+// field in a query.
+var Operators = operatorSet{
+	EQ:      newOperator("eq"),
+	NE:      newOperator("ne"),
+	GT:      newOperator("gt"),
+	GTE:     newOperator("gte"),
+	LT:      newOperator("lt"),
+	LTE:     newOperator("lte"),
+	IN:      newOperator("in"),
+	BETWEEN: newOperator("between"),
+}
+
+// Identity is the set of operators that make sense for identifiers and other
+// values without an order.
+var Identity = []Operator{
+	Operators.EQ,
+	Operators.NE,
+	Operators.IN,
+}
+
+// Ordered is the set of operators that make sense for numbers, dates and
+// other values with an order.
+var Ordered = []Operator{
+	Operators.EQ,
+	Operators.NE,
+	Operators.GT,
+	Operators.GTE,
+	Operators.LT,
+	Operators.LTE,
+	Operators.IN,
+	Operators.BETWEEN,
+}
+
+// =============================================================================
+
+// Set of known operators, and the order they were declared in.
+var (
+	operators = make(map[string]Operator)
+	known     []Operator
+)
+
+// Operator represents a comparison in the system.
+type Operator struct {
+	name string
+}
+
+func newOperator(op string) Operator {
+	o := Operator{op}
+	operators[op] = o
+	known = append(known, o)
+	return o
+}
+
+// String returns the name of the operator.
+func (o Operator) String() string {
+	return o.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (o Operator) Equal(o2 Operator) bool {
+	return o.name == o2.name
+}
+
+// =============================================================================
+
+// ParseOperator parses the string value and returns an operator if one exists.
+func ParseOperator(value string) (Operator, error) {
+	op, exists := operators[value]
+	if !exists {
+		return Operator{}, fmt.Errorf("invalid operator %q", value)
+	}
+
+	return op, nil
+}
+
+// MustParseOperator parses the string value and returns an operator if one
+// exists. If an error occurs the function panics.
+func MustParseOperator(value string) Operator {
+	op, err := ParseOperator(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return op
+}
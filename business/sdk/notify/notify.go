@@ -0,0 +1,76 @@
+// Package notify provides support for sending notifications to people or
+// systems outside of the service.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/encore/foundation/logger"
+)
+
+// Notifier represents behavior for delivering a notification.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Message represents a notification to be delivered.
+type Message struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// =============================================================================
+
+// Fanout delivers a notification to a set of notifiers.
+type Fanout struct {
+	log       *logger.Logger
+	notifiers []Notifier
+}
+
+// NewFanout constructs a notifier that delivers every message to each of the
+// specified notifiers.
+func NewFanout(log *logger.Logger, notifiers ...Notifier) *Fanout {
+	return &Fanout{
+		log:       log,
+		notifiers: notifiers,
+	}
+}
+
+// Notify implements the Notifier interface. Every notifier is called even if
+// one of them fails and the failures are returned together.
+func (f *Fanout) Notify(ctx context.Context, msg Message) error {
+	var errs []error
+
+	for _, n := range f.notifiers {
+		if err := n.Notify(ctx, msg); err != nil {
+			f.log.Error(ctx, "notify", "kind", msg.Kind, "notifier", fmt.Sprintf("%T", n), "msg", err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// =============================================================================
+
+// Log delivers notifications by writing them to the log.
+type Log struct {
+	log *logger.Logger
+}
+
+// NewLog constructs a notifier that writes messages to the log.
+func NewLog(log *logger.Logger) *Log {
+	return &Log{
+		log: log,
+	}
+}
+
+// Notify implements the Notifier interface.
+func (l *Log) Notify(ctx context.Context, msg Message) error {
+	l.log.Info(ctx, "notify", "kind", msg.Kind, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
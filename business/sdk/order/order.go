@@ -17,10 +17,19 @@ var directions = map[string]string{
 	DESC: "DESC",
 }
 
-// By represents a field used to order by and direction.
+// Key represents a single field used to order by and its direction.
+type Key struct {
+	Field     string
+	Direction string
+}
+
+// By represents the fields used to order by, from the most significant to
+// the least. Field and Direction describe the first of them and Then holds
+// the ones used to order rows that are equal on it.
 type By struct {
 	Field     string
 	Direction string
+	Then      []Key
 }
 
 // NewBy constructs a new By value with no checks.
@@ -38,34 +47,109 @@ func NewBy(field string, direction string) By {
 	}
 }
 
+// ThenBy returns a copy of the ordering with another field added to it.
+func (b By) ThenBy(field string, direction string) By {
+	if _, exists := directions[direction]; !exists {
+		direction = ASC
+	}
+
+	then := make([]Key, len(b.Then), len(b.Then)+1)
+	copy(then, b.Then)
+
+	b.Then = append(then, Key{Field: field, Direction: direction})
+	return b
+}
+
+// Keys returns every field of the ordering from the most significant to the
+// least.
+func (b By) Keys() []Key {
+	keys := make([]Key, 0, len(b.Then)+1)
+	keys = append(keys, Key{Field: b.Field, Direction: b.Direction})
+	keys = append(keys, b.Then...)
+
+	return keys
+}
+
+// Contains reports whether the field is used by the ordering.
+func (b By) Contains(field string) bool {
+	for _, key := range b.Keys() {
+		if key.Field == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String implements the stringer interface using the format accepted by
+// Parse, ie "cost,DESC;name,ASC".
+func (b By) String() string {
+	keys := b.Keys()
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key.Field + "," + key.Direction
+	}
+
+	return strings.Join(parts, ";")
+}
+
 // Parse constructs a By value by parsing a string in the form of
-// "field,direction" ie "user_id,ASC".
+// "field,direction" ie "user_id,ASC". Several fields can be provided
+// separated by semicolons, ie "cost,DESC;name,ASC", where each field orders
+// the rows that are equal on the ones before it.
 func Parse(fieldMappings map[string]string, orderBy string, defaultOrder By) (By, error) {
 	if orderBy == "" {
 		return defaultOrder, nil
 	}
 
+	var by By
+	seen := make(map[string]bool)
+
+	for i, part := range strings.Split(orderBy, ";") {
+		key, err := parseKey(fieldMappings, part)
+		if err != nil {
+			return By{}, err
+		}
+
+		if seen[key.Field] {
+			return By{}, fmt.Errorf("duplicate order: %s", strings.TrimSpace(part))
+		}
+		seen[key.Field] = true
+
+		if i == 0 {
+			by = NewBy(key.Field, key.Direction)
+			continue
+		}
+
+		by = by.ThenBy(key.Field, key.Direction)
+	}
+
+	return by, nil
+}
+
+func parseKey(fieldMappings map[string]string, orderBy string) (Key, error) {
 	orderParts := strings.Split(orderBy, ",")
 
 	orgFieldName := strings.TrimSpace(orderParts[0])
 	fieldName, exists := fieldMappings[orgFieldName]
 	if !exists {
-		return By{}, fmt.Errorf("unknown order: %s", orgFieldName)
+		return Key{}, fmt.Errorf("unknown order: %s", orgFieldName)
 	}
 
 	switch len(orderParts) {
 	case 1:
-		return NewBy(fieldName, ASC), nil
+		return Key{Field: fieldName, Direction: ASC}, nil
 
 	case 2:
 		direction := strings.TrimSpace(orderParts[1])
 		if _, exists := directions[direction]; !exists {
-			return By{}, fmt.Errorf("unknown direction: %s", direction)
+			return Key{}, fmt.Errorf("unknown direction: %s", direction)
 		}
 
-		return NewBy(fieldName, direction), nil
+		return Key{Field: fieldName, Direction: direction}, nil
 
 	default:
-		return By{}, fmt.Errorf("unknown order: %s", orderBy)
+		return Key{}, fmt.Errorf("unknown order: %s", orderBy)
 	}
 }
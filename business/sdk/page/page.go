@@ -2,14 +2,25 @@
 package page
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
 )
 
-// Page represents the requested page and rows per page.
+// ErrInvalidCursor is returned when a cursor can't be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page represents the requested page and rows per page. A page is either
+// identified by its number or, for keyset paging, by a cursor.
 type Page struct {
-	number int
-	rows   int
+	number    int
+	rows      int
+	cursor    *Cursor
+	lookahead bool
 }
 
 // Parse parses the strings and validates the values are in reason.
@@ -23,33 +34,82 @@ func Parse(page string, rowsPerPage string) (Page, error) {
 		}
 	}
 
+	if number <= 0 {
+		return Page{}, fmt.Errorf("page value too small, must be larger than 0")
+	}
+
+	rows, err := parseRows(rowsPerPage)
+	if err != nil {
+		return Page{}, err
+	}
+
+	p := Page{
+		number: number,
+		rows:   rows,
+	}
+
+	return p, nil
+}
+
+// ParseQuery parses the paging query strings of a request. A cursor takes
+// the place of the page number, so only one of them can be provided.
+func ParseQuery(page string, rowsPerPage string, cursor string) (Page, error) {
+	if cursor == "" {
+		return Parse(page, rowsPerPage)
+	}
+
+	if page != "" {
+		return Page{}, fmt.Errorf("page and cursor can't be used together")
+	}
+
+	return ParseCursor(cursor, rowsPerPage)
+}
+
+// ParseCursor parses the strings into a page that continues from the position
+// held by the cursor. An empty cursor behaves like the first page.
+func ParseCursor(cursor string, rowsPerPage string) (Page, error) {
+	if cursor == "" {
+		return Parse("", rowsPerPage)
+	}
+
+	c, err := decodeCursor(cursor)
+	if err != nil {
+		return Page{}, err
+	}
+
+	rows, err := parseRows(rowsPerPage)
+	if err != nil {
+		return Page{}, err
+	}
+
+	p := Page{
+		number: 1,
+		rows:   rows,
+		cursor: &c,
+	}
+
+	return p, nil
+}
+
+func parseRows(rowsPerPage string) (int, error) {
 	rows := 10
 	if rowsPerPage != "" {
 		var err error
 		rows, err = strconv.Atoi(rowsPerPage)
 		if err != nil {
-			return Page{}, fmt.Errorf("rows conversion: %w", err)
+			return 0, fmt.Errorf("rows conversion: %w", err)
 		}
 	}
 
-	if number <= 0 {
-		return Page{}, fmt.Errorf("page value too small, must be larger than 0")
-	}
-
 	if rows <= 0 {
-		return Page{}, fmt.Errorf("rows value too small, must be larger than 0")
+		return 0, fmt.Errorf("rows value too small, must be larger than 0")
 	}
 
 	if rows > 100 {
-		return Page{}, fmt.Errorf("rows value too large, must be less than 100")
-	}
-
-	p := Page{
-		number: number,
-		rows:   rows,
+		return 0, fmt.Errorf("rows value too large, must be less than 100")
 	}
 
-	return p, nil
+	return rows, nil
 }
 
 // MustParse creates a paging value for testing.
@@ -62,12 +122,26 @@ func MustParse(page string, rowsPerPage string) Page {
 	return pg
 }
 
+// MustParseCursor creates a cursor paging value for testing.
+func MustParseCursor(cursor string, rowsPerPage string) Page {
+	pg, err := ParseCursor(cursor, rowsPerPage)
+	if err != nil {
+		panic(err)
+	}
+
+	return pg
+}
+
 // String implements the stringer interface.
 func (p Page) String() string {
+	if p.cursor != nil {
+		return fmt.Sprintf("cursor: %s rows: %d", p.cursor.encode(), p.rows)
+	}
+
 	return fmt.Sprintf("page: %d rows: %d", p.number, p.rows)
 }
 
-// Number returns the page number.
+// Number returns the page number. Cursor pages always report page 1.
 func (p Page) Number() int {
 	return p.number
 }
@@ -76,3 +150,131 @@ func (p Page) Number() int {
 func (p Page) RowsPerPage() int {
 	return p.rows
 }
+
+// WithLookahead returns the page set to fetch one row more than it holds, so
+// the caller can tell there are more rows without counting them.
+func (p Page) WithLookahead() Page {
+	p.lookahead = true
+	return p
+}
+
+// FetchRows returns the number of rows to fetch for the page, which is one
+// more than the rows per page when the page looks ahead.
+func (p Page) FetchRows() int {
+	if p.lookahead {
+		return p.rows + 1
+	}
+
+	return p.rows
+}
+
+// Cursor returns the cursor of the page and false when the page is
+// identified by its number.
+func (p Page) Cursor() (Cursor, bool) {
+	if p.cursor == nil {
+		return Cursor{}, false
+	}
+
+	return *p.cursor, true
+}
+
+// IsBackward reports whether the page holds the rows before its cursor.
+func (p Page) IsBackward() bool {
+	return p.cursor != nil && p.cursor.Backward
+}
+
+// ValidateOrder checks a cursor page is used with the order it was created
+// for, since its position means nothing in any other order.
+func (p Page) ValidateOrder(orderBy order.By) error {
+	if p.cursor == nil {
+		return nil
+	}
+
+	if p.cursor.Order != orderBy.String() {
+		return fmt.Errorf("cursor was created for a different order")
+	}
+
+	return nil
+}
+
+// Cursors returns the cursors of the pages before and after this one once it
+// has been fetched. Count is the number of rows that came back and key returns
+// the key of the row at the specified index. A cursor is empty when there is
+// nothing to page to in that direction.
+func (p Page) Cursors(orderBy order.By, count int, key func(i int) Key) (prev string, next string) {
+	if count == 0 {
+		return "", ""
+	}
+
+	hasPrev := p.number > 1
+	hasNext := count == p.rows
+
+	if p.cursor != nil {
+		hasPrev = true
+		if p.cursor.Backward {
+			hasPrev = count == p.rows
+			hasNext = true
+		}
+	}
+
+	if hasPrev {
+		c := Cursor{
+			Order:    orderBy.String(),
+			Key:      key(0),
+			Backward: true,
+		}
+		prev = c.encode()
+	}
+
+	if hasNext {
+		c := Cursor{
+			Order: orderBy.String(),
+			Key:   key(count - 1),
+		}
+		next = c.encode()
+	}
+
+	return prev, next
+}
+
+// =============================================================================
+
+// Key identifies the position of a row in an ordered result. Values are the
+// text form of the row's order fields, ID the row's unique id which breaks
+// ties between rows with the same values.
+type Key struct {
+	Values []string `json:"v"`
+	ID     string   `json:"id"`
+}
+
+// Cursor marks the position after which, or before which when Backward is
+// set, the rows of a page are fetched. Order describes the ordering the key
+// was taken from.
+type Cursor struct {
+	Order    string `json:"o"`
+	Key      Key    `json:"k"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+func (c Cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if c.Order == "" || c.Key.ID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return c, nil
+}
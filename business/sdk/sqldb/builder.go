@@ -0,0 +1,180 @@
+package sqldb
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+)
+
+// Columns maps the fields a business package orders by to the column, select
+// alias or expression a store sorts on.
+type Columns map[string]string
+
+// Keys returns the keys of the ordering with each field mapped to its column.
+func (c Columns) Keys(orderBy order.By) ([]order.Key, error) {
+	keys := orderBy.Keys()
+
+	columns := make([]order.Key, len(keys))
+	for i, key := range keys {
+		column, exists := c[key.Field]
+		if !exists {
+			return nil, fmt.Errorf("field %q does not exist", key.Field)
+		}
+
+		columns[i] = order.Key{
+			Field:     column,
+			Direction: key.Direction,
+		}
+	}
+
+	return columns, nil
+}
+
+// =============================================================================
+
+// Builder composes a select statement from a table, where clauses, ordering
+// and paging. Every value is bound as a named parameter, so the sql is only
+// ever made up of the table, columns and clauses written by the store.
+type Builder struct {
+	table   string
+	columns []string
+	clauses []string
+	data    map[string]any
+	paged   string
+}
+
+// NewBuilder starts a select of the columns from the table.
+func NewBuilder(table string, columns string) *Builder {
+	return &Builder{
+		table:   table,
+		columns: []string{columns},
+		data:    map[string]any{},
+	}
+}
+
+// Column adds a column or expression to the select list.
+func (b *Builder) Column(expr string) {
+	b.columns = append(b.columns, expr)
+}
+
+// Bind binds the value to the named parameter so clauses and expressions
+// added to the builder can refer to it.
+func (b *Builder) Bind(name string, value any) {
+	b.data[name] = value
+}
+
+// Where adds a clause to the where clause. Clauses are joined with AND.
+func (b *Builder) Where(clause string) {
+	b.clauses = append(b.clauses, clause)
+}
+
+// Equal matches rows where the column is equal to the value.
+func (b *Builder) Equal(column string, value any) {
+	b.compare(column, "=", value)
+}
+
+// AtLeast matches rows where the column is greater than or equal to the value.
+func (b *Builder) AtLeast(column string, value any) {
+	b.compare(column, ">=", value)
+}
+
+// AtMost matches rows where the column is less than or equal to the value.
+func (b *Builder) AtMost(column string, value any) {
+	b.compare(column, "<=", value)
+}
+
+// Contains matches rows where the column contains the value.
+func (b *Builder) Contains(column string, value string) {
+	b.compare(column, "LIKE", fmt.Sprintf("%%%s%%", value))
+}
+
+// IsNull matches rows where the column is null.
+func (b *Builder) IsNull(column string) {
+	b.Where(column + " IS NULL")
+}
+
+// Page completes the query with the ordering and paging clauses for the page
+// as described by ApplyPage. It must be called after every where clause has
+// been added.
+func (b *Builder) Page(columns Columns, orderBy order.By, idColumn string, pg page.Page) error {
+	keys, err := columns.Keys(orderBy)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBufferString(b.selectClause())
+	if err := ApplyPage(buf, b.data, keys, idColumn, pg); err != nil {
+		return fmt.Errorf("applypage: %w", err)
+	}
+	b.paged = buf.String()
+
+	return nil
+}
+
+// Order completes the query with the ordering for orderBy and no paging, for
+// queries that read every matching row. The idColumn breaks ties like it
+// does for Page.
+func (b *Builder) Order(columns Columns, orderBy order.By, idColumn string) error {
+	keys, err := columns.Keys(orderBy)
+	if err != nil {
+		return err
+	}
+
+	keys, _ = withTiebreaker(keys, idColumn)
+	b.paged = b.selectClause() + orderByClause(keys)
+
+	return nil
+}
+
+// String returns the sql of the query.
+func (b *Builder) String() string {
+	if b.paged != "" {
+		return b.paged
+	}
+
+	return b.selectClause()
+}
+
+// Data returns the values of the named parameters used by the query.
+func (b *Builder) Data() map[string]any {
+	return b.data
+}
+
+func (b *Builder) selectClause() string {
+	var buf strings.Builder
+
+	buf.WriteString("SELECT ")
+	buf.WriteString(strings.Join(b.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(b.table)
+
+	if len(b.clauses) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(b.clauses, " AND "))
+	}
+
+	return buf.String()
+}
+
+func (b *Builder) compare(column string, op string, value any) {
+	name := b.param(column)
+	b.data[name] = value
+	b.Where(fmt.Sprintf("%s %s :%s", column, op, name))
+}
+
+// param returns a parameter name for the column that isn't bound yet, since
+// a column can be compared more than once.
+func (b *Builder) param(column string) string {
+	prefix := strings.ReplaceAll(column, ".", "_")
+
+	name := prefix
+	for i := 1; ; i++ {
+		if _, exists := b.data[name]; !exists {
+			return name
+		}
+		name = fmt.Sprintf("%s_%d", prefix, i)
+	}
+}
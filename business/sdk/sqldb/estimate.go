@@ -0,0 +1,40 @@
+package sqldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// EstimateCount returns the query planner's estimate of the number of rows
+// the query returns. It costs next to nothing on large tables where a count
+// has to read every matching row, but it's only as accurate as the table
+// statistics.
+func EstimateCount(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any) (int, error) {
+	var explain struct {
+		Plan []byte `db:"QUERY PLAN"`
+	}
+	if err := NamedQueryStruct(ctx, log, db, "EXPLAIN (FORMAT JSON) "+query, data, &explain); err != nil {
+		return 0, err
+	}
+
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(explain.Plan, &plans); err != nil {
+		return 0, fmt.Errorf("unmarshal plan: %w", err)
+	}
+
+	if len(plans) == 0 {
+		return 0, errors.New("no plan returned")
+	}
+
+	return int(math.Round(plans[0].Plan.Rows)), nil
+}
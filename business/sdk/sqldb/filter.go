@@ -0,0 +1,43 @@
+package sqldb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ardanlabs/encore/business/sdk/filter"
+)
+
+// operators maps the filter operators that compare a single value to sql.
+var operators = map[filter.Operator]string{
+	filter.Operators.EQ:  "=",
+	filter.Operators.NE:  "<>",
+	filter.Operators.GT:  ">",
+	filter.Operators.GTE: ">=",
+	filter.Operators.LT:  "<",
+	filter.Operators.LTE: "<=",
+}
+
+// Conditions adds a where clause to the builder for each condition on the
+// column. The conditions are expected to come from Field.Conditions or the
+// filter constructors, and the column must come from the store, never from
+// the caller.
+func Conditions[T any](b *Builder, column string, conds []filter.Condition[T]) {
+	for _, cond := range conds {
+		names := make([]string, len(cond.Values))
+		for i, v := range cond.Values {
+			names[i] = b.param(column)
+			b.data[names[i]] = v
+		}
+
+		switch cond.Operator {
+		case filter.Operators.IN:
+			b.Where(fmt.Sprintf("%s IN (:%s)", column, strings.Join(names, ", :")))
+
+		case filter.Operators.BETWEEN:
+			b.Where(fmt.Sprintf("%s BETWEEN :%s AND :%s", column, names[0], names[1]))
+
+		default:
+			b.Where(fmt.Sprintf("%s %s :%s", column, operators[cond.Operator], names[0]))
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package sqldb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+)
+
+// ErrCursorMismatch is returned when a cursor doesn't hold a value for every
+// column of the ordering.
+var ErrCursorMismatch = errors.New("cursor does not match the order")
+
+// ApplyPage completes the query in buf with the ordering and paging clauses
+// for the page. Each key names the column, select alias or expression of an
+// order field. The idColumn is appended when it's not already part of the
+// ordering, so rows that are equal on every key still come back in a stable
+// order.
+//
+// Cursor pages wrap the query so the cursor can be compared against select
+// aliases. A page fetched backward is read in reverse order, so the caller
+// must put the rows back in order with slices.Reverse.
+func ApplyPage(buf *bytes.Buffer, data map[string]any, columns []order.Key, idColumn string, pg page.Page) error {
+	columns, values := withTiebreaker(columns, idColumn)
+
+	data["rows_per_page"] = pg.FetchRows()
+
+	cursor, ok := pg.Cursor()
+	if !ok {
+		data["offset"] = (pg.Number() - 1) * pg.RowsPerPage()
+
+		buf.WriteString(orderByClause(columns))
+		buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+		return nil
+	}
+
+	if len(cursor.Key.Values)+values != len(columns) {
+		return ErrCursorMismatch
+	}
+
+	if cursor.Backward {
+		columns = reverse(columns)
+	}
+
+	args := make([]string, len(columns))
+	for i := range columns {
+		name := fmt.Sprintf("cursor_%d", i)
+		args[i] = ":" + name
+
+		switch {
+		case i < len(cursor.Key.Values):
+			data[name] = cursor.Key.Values[i]
+		default:
+			data[name] = cursor.Key.ID
+		}
+	}
+
+	q := buf.String()
+	buf.Reset()
+
+	buf.WriteString("SELECT * FROM (")
+	buf.WriteString(q)
+	buf.WriteString(") AS paged WHERE ")
+	buf.WriteString(afterClause(columns, args))
+	buf.WriteString(orderByClause(columns))
+	buf.WriteString(" FETCH FIRST :rows_per_page ROWS ONLY")
+
+	return nil
+}
+
+// withTiebreaker appends the id column to the ordering when it's missing and
+// reports how many columns were added.
+func withTiebreaker(columns []order.Key, idColumn string) ([]order.Key, int) {
+	for _, col := range columns {
+		if col.Field == idColumn {
+			return columns, 0
+		}
+	}
+
+	tie := order.Key{
+		Field:     idColumn,
+		Direction: columns[0].Direction,
+	}
+
+	return append(columns[:len(columns):len(columns)], tie), 1
+}
+
+// afterClause matches the rows that come after the cursor in the ordering.
+// A row comparison is used when every column has the same direction since it
+// can be answered by an index, otherwise the comparison is spelled out one
+// column at a time.
+func afterClause(columns []order.Key, args []string) string {
+	same := true
+	for _, col := range columns {
+		if col.Direction != columns[0].Direction {
+			same = false
+			break
+		}
+	}
+
+	if same {
+		names := make([]string, len(columns))
+		for i, col := range columns {
+			names[i] = col.Field
+		}
+
+		return "(" + strings.Join(names, ", ") + ") " + operator(columns[0].Direction) + " (" + strings.Join(args, ", ") + ")"
+	}
+
+	ors := make([]string, len(columns))
+	for i, col := range columns {
+		ands := make([]string, 0, i+1)
+		for j := range i {
+			ands = append(ands, columns[j].Field+" = "+args[j])
+		}
+		ands = append(ands, col.Field+" "+operator(col.Direction)+" "+args[i])
+
+		ors[i] = "(" + strings.Join(ands, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(ors, " OR ") + ")"
+}
+
+func orderByClause(columns []order.Key) string {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = col.Field + " " + col.Direction
+	}
+
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+func operator(direction string) string {
+	if direction == order.DESC {
+		return "<"
+	}
+
+	return ">"
+}
+
+func reverse(columns []order.Key) []order.Key {
+	rev := make([]order.Key, len(columns))
+	for i, col := range columns {
+		rev[i] = col
+		rev[i].Direction = order.DESC
+		if col.Direction == order.DESC {
+			rev[i].Direction = order.ASC
+		}
+	}
+
+	return rev
+}
@@ -0,0 +1,30 @@
+package sqldb
+
+import (
+	"strings"
+
+	"github.com/ardanlabs/encore/business/sdk/fieldset"
+)
+
+// FieldColumns pairs a field a query can be limited to with the comma
+// separated columns it's read from.
+type FieldColumns struct {
+	Field   string
+	Columns string
+}
+
+// Projection lists the fields a store can return in the order they are
+// selected.
+type Projection []FieldColumns
+
+// Columns returns the select list for the fields in the set.
+func (p Projection) Columns(fields fieldset.Set) string {
+	var columns []string
+	for _, fc := range p {
+		if fields.Contains(fc.Field) {
+			columns = append(columns, fc.Columns)
+		}
+	}
+
+	return strings.Join(columns, ", ")
+}
@@ -3,8 +3,10 @@ package unitest
 import (
 	"context"
 
+	"github.com/ardanlabs/encore/business/domain/categorybus"
 	"github.com/ardanlabs/encore/business/domain/homebus"
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/tagbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 )
 
@@ -17,8 +19,10 @@ type User struct {
 
 // SeedData represents data that was seeded for the test.
 type SeedData struct {
-	Users  []User
-	Admins []User
+	Users      []User
+	Admins     []User
+	Categories []categorybus.Category
+	Tags       []tagbus.Tag
 }
 
 // Table represent fields needed for running an unit test.